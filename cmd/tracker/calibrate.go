@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+func runCalibrate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("calibrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	steps := flags.Int("steps", 0, "количество шагов на контрольной дистанции")
+	distance := flags.String("distance", "400m", "измеренная дистанция в м, км, ft, yd или mi, число без единиц - метры или ярды для --units imperial")
+	unitsName := flags.String("units", string(units.Metric), "система единиц ввода и вывода: metric или imperial")
+	path := flags.String("profile", "", "путь к файлу профиля в JSON или YAML (.yaml, .yml), куда сохраняется длина шага")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(stderr, "не задан --profile для сохранения длины шага")
+		return exitUsage
+	}
+
+	// Профиль может еще не существовать, тогда создаем новый
+	p, err := profile.Load(*path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	// Единицы берутся из профиля, если не заданы флагом
+	visited := false
+	flags.Visit(func(f *flag.Flag) { visited = visited || f.Name == "units" })
+	if !visited && p.Units != "" {
+		*unitsName = string(p.Units)
+	}
+	sys, err := units.ParseSystem(*unitsName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	meters, err := units.ParseDistance(*distance, sys)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	length, err := stride.Calibrate(*steps, meters)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	p.Stride = float64(length)
+	if err := profile.Save(*path, p); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	fmt.Fprintf(stdout, "Длина шага: %s, сохранена в %s\n", sys.Length(float64(length)), *path)
+	return exitOK
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/journal"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/storage"
+)
+
+// Пользователь базы по умолчанию
+const defaultUser = "default"
+
+// dbWriter копит результаты и сохраняет их в базу пачками, каждую в одной
+// транзакции. Nil-значение означает, что база не задана, и ничего не сохраняет
+type dbWriter struct {
+	store     *storage.SQLite
+	user      int64
+	days      []daysteps.DayActionResult
+	trainings []spentcalories.TrainingResult
+}
+
+// openDB открывает базу из флага --db, создает пользователя и сохраняет профиль,
+// по которому посчитаны результаты. Возвращает nil, если флаг не задан
+func openDB(c *commonFlags, set settings) (*dbWriter, error) {
+	if c.db == "" {
+		return nil, nil
+	}
+	ctx := context.Background()
+	store, err := storage.Open(ctx, c.db)
+	if err != nil {
+		return nil, err
+	}
+	u, err := store.EnsureUser(ctx, c.user)
+	if err == nil {
+		err = store.SaveProfile(ctx, u.ID, set.profile)
+	}
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return &dbWriter{store: store, user: u.ID}, nil
+}
+
+func (w *dbWriter) add(e journal.Entry) error {
+	if w == nil {
+		return nil
+	}
+	switch {
+	case e.Day != nil:
+		w.days = append(w.days, *e.Day)
+	case e.Training != nil:
+		w.trainings = append(w.trainings, *e.Training)
+	}
+	// Пачки того же размера, что и у журнала
+	if len(w.days)+len(w.trainings) >= journalBatch {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *dbWriter) flush() error {
+	if w == nil {
+		return nil
+	}
+	ctx := context.Background()
+	if len(w.days) > 0 {
+		if _, err := w.store.AddDays(ctx, w.user, w.days...); err != nil {
+			return err
+		}
+		w.days = w.days[:0]
+	}
+	if len(w.trainings) > 0 {
+		if _, err := w.store.AddTrainings(ctx, w.user, w.trainings...); err != nil {
+			return err
+		}
+		w.trainings = w.trainings[:0]
+	}
+	return nil
+}
+
+// close сохраняет оставшиеся результаты и закрывает базу
+func (w *dbWriter) close() error {
+	if w == nil {
+		return nil
+	}
+	err := w.flush()
+	if cerr := w.store.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// saver сохраняет результаты в журнал и в базу, если они заданы флагами
+type saver struct {
+	journal *journalWriter
+	db      *dbWriter
+}
+
+// openSaver открывает журнал и базу из флагов --journal и --db
+func openSaver(c *commonFlags, set settings, stderr io.Writer) (*saver, error) {
+	jw, err := openJournal(c.journal, stderr)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openDB(c, set)
+	if err != nil {
+		jw.close()
+		return nil, err
+	}
+	return &saver{journal: jw, db: db}, nil
+}
+
+func (s *saver) add(e journal.Entry) error {
+	if err := s.journal.add(e); err != nil {
+		return err
+	}
+	return s.db.add(e)
+}
+
+// close сохраняет оставшиеся результаты, закрывает журнал и базу
+func (s *saver) close() error {
+	return errors.Join(s.journal.close(), s.db.close())
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/Yandex-Practicum/tracker/internal/rpc"
+)
+
+// runGRPC запускает gRPC-сервис расчета калорий и работает до SIGINT или SIGTERM
+func runGRPC(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("grpc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":9090", "адрес, на котором сервис принимает запросы")
+	tz := fs.String("tz", "", "часовой пояс для дат запросов, например Europe/Moscow, по умолчанию местный")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	loc := time.Local
+	if *tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(*tz); err != nil {
+			fmt.Fprintf(stderr, "неизвестный часовой пояс %q\n", *tz)
+			return exitUsage
+		}
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	srv := grpc.NewServer()
+	rpc.Register(srv, rpc.WithLocation(loc))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Дожидаемся обрабатываемых запросов, но не дольше shutdownTimeout
+		timer := time.AfterFunc(shutdownTimeout, srv.Stop)
+		defer timer.Stop()
+		srv.GracefulStop()
+	}()
+
+	fmt.Fprintf(stdout, "gRPC-сервис слушает %s\n", lis.Addr())
+	if err := srv.Serve(lis); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
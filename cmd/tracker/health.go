@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/health"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// importHealth - импорт тренировок из export.xml для команды import
+func importHealth(r io.Reader, activity string, weight, height float64, opts ...spentcalories.Option) ([]spentcalories.TrainingResult, error) {
+	result, err := health.Import(r, activity, weight, height, health.WithTrainingOptions(opts...))
+	return result.Trainings, err
+}
+
+func runHealth(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags commonFlags
+	flags.register(fs)
+	sources := fs.String("source", "", "источники записей через запятую, например название часов, чтобы шаги не считались дважды")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	set, err := flags.resolve(fs)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if flags.format == formatTCX {
+		fmt.Fprintln(stderr, "формат tcx поддерживается только для тренировок")
+		return exitUsage
+	}
+
+	p := set.profile
+	// Команда выводит только итоги дней, тренировки не рассчитываются
+	opts := []health.Option{health.WithDayOptions(daysteps.WithStride(stride.FromProfile(p))), health.WithoutWorkouts()}
+	if *sources != "" {
+		opts = append(opts, health.WithSources(strings.Split(*sources, ",")...))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	out := newOutput(flags.format, set.loc, set.sys, stdout, daySummaryColumns, daySummaryRow)
+	broken := false
+	for _, name := range files {
+		err := withInput(name, stdin, func(r io.Reader) error {
+			result, err := health.Import(r, "", p.Weight, p.Height, opts...)
+			if err != nil {
+				return err
+			}
+			for _, day := range result.Days {
+				if err := out.write(day); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, i18n.Localize(err, set.loc))
+			broken = true
+		}
+	}
+
+	// Итоги по прочитанным файлам выводятся, даже если какой-то файл не удалось обработать
+	if err := out.flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	if broken {
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Yandex-Practicum/tracker/internal/fit"
+	"github.com/Yandex-Practicum/tracker/internal/gpx"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/journal"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/tcx"
+)
+
+// importer читает файл тренировок одного формата и рассчитывает тренировки из него
+type importer func(r io.Reader, activity string, weight, height float64, opts ...spentcalories.Option) ([]spentcalories.TrainingResult, error)
+
+// importers - поддерживаемые форматы по расширению файла
+var importers = map[string]importer{
+	".fit": fit.Import,
+	".gpx": gpx.Import,
+	".tcx": tcx.Import,
+	".xml": importHealth,
+}
+
+func runImport(args []string, stdout, stderr io.Writer) (code int) {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags commonFlags
+	flags.register(fs)
+	model := fs.String("model", spentcalories.SpeedModel.String(), "модель расчета калорий: speed, met или hr - по пульсу, если он есть в данных")
+	activity := fs.String("activity", "Ходьба", "активность для треков, в которых она не указана")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	set, err := flags.resolve(fs)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	opts, err := trainingOptions(set, *model)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "не указаны файлы для импорта")
+		return exitUsage
+	}
+
+	sv, err := openSaver(&flags, set, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	p := set.profile
+	out := newTrainingOutput(flags.format, set, stdout)
+	// Уже рассчитанные тренировки выводятся и при досрочном выходе с ошибкой
+	defer func() {
+		if err := out.flush(); err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitFailed
+		}
+	}()
+
+	code = exitOK
+	for _, name := range fs.Args() {
+		imp, ok := importers[strings.ToLower(filepath.Ext(name))]
+		if !ok {
+			fmt.Fprintf(stderr, "%s: неизвестный формат файла\n", name)
+			code = exitFailed
+			continue
+		}
+
+		var results []spentcalories.TrainingResult
+		err := withInput(name, nil, func(r io.Reader) error {
+			results, err = imp(r, *activity, p.Weight, p.Height, opts...)
+			return err
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, i18n.Localize(err, set.loc))
+			code = exitFailed
+			continue
+		}
+		for _, r := range results {
+			if err := out.write(r); err != nil {
+				fmt.Fprintln(stderr, err)
+				sv.close()
+				return exitFailed
+			}
+			if err := sv.add(journal.Training(r)); err != nil {
+				fmt.Fprintln(stderr, err)
+				sv.close()
+				return exitFailed
+			}
+		}
+	}
+
+	if err := sv.close(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	return code
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/journal"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Записи сохраняются в журнал пачками, чтобы не делать fsync на каждую строку
+const journalBatch = 256
+
+// journalWriter копит записи и добавляет их в журнал пачками. Nil-значение
+// означает, что журнал не задан, и ничего не сохраняет
+type journalWriter struct {
+	j       *journal.Journal
+	pending []journal.Entry
+}
+
+// openJournal открывает журнал из флага --journal или возвращает nil, если флаг не задан
+func openJournal(path string, stderr io.Writer) (*journalWriter, error) {
+	if path == "" {
+		return nil, nil
+	}
+	j, err := journal.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if n := j.Truncated(); n > 0 {
+		fmt.Fprintf(stderr, "%s: отрезана недописанная запись, %d байт\n", path, n)
+	}
+	return &journalWriter{j: j}, nil
+}
+
+func (w *journalWriter) add(e journal.Entry) error {
+	if w == nil {
+		return nil
+	}
+	w.pending = append(w.pending, e)
+	if len(w.pending) >= journalBatch {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *journalWriter) flush() error {
+	if w == nil || len(w.pending) == 0 {
+		return nil
+	}
+	_, err := w.j.Append(w.pending...)
+	w.pending = w.pending[:0]
+	return err
+}
+
+// close сохраняет оставшиеся записи и закрывает журнал
+func (w *journalWriter) close() error {
+	if w == nil {
+		return nil
+	}
+	err := w.flush()
+	if cerr := w.j.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+var journalColumns = []string{"seq", "kind", "time", "activity", "steps", "duration", "distance_km", "calories"}
+
+func journalRow(e journal.Entry, sys units.System) []string {
+	var activity string
+	var steps int
+	var duration time.Duration
+	var distance, calories float64
+	switch {
+	case e.Day != nil:
+		steps, duration, distance, calories = e.Day.Steps, e.Day.Duration, e.Day.Distance, e.Day.Calories
+	case e.Training != nil:
+		activity = e.Training.Activity
+		steps, duration, distance, calories = e.Training.Steps, e.Training.Duration, e.Training.Distance, e.Training.Calories
+	}
+	return []string{
+		strconv.FormatUint(e.Seq, 10),
+		string(e.Kind),
+		formatTime(e.Time),
+		activity,
+		strconv.Itoa(steps),
+		duration.String(),
+		formatFloat(sys.Distance(distance)),
+		formatFloat(calories),
+	}
+}
+
+// runJournal работает с журналом активности: journal list выводит записи
+// за период, journal delete удаляет запись, journal compact сжимает файл
+func runJournal(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "Использование: tracker journal list|delete|compact --journal activity.journal [флаги]")
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("journal "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("journal", "", "файл журнала активности")
+	from := fs.String("from", "", "начало периода ГГГГ-ММ-ДД включительно")
+	to := fs.String("to", "", "конец периода ГГГГ-ММ-ДД включительно")
+	kind := fs.String("kind", "", "вид записей: day или training, по умолчанию все")
+	unitsName := fs.String("units", string(units.Metric), "система единиц вывода: metric или imperial")
+	locale := fs.String("locale", string(i18n.Default), "язык вывода: ru или en")
+	format := fs.String("format", formatText, "формат вывода: text, json или csv")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(stderr, "не задан --journal")
+		return exitUsage
+	}
+
+	// Просмотр не меняет файл и не мешает другим читателям
+	open := journal.Open
+	if args[0] == "list" {
+		open = journal.OpenReadOnly
+	}
+	j, err := open(*path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	defer j.Close()
+	if n := j.Truncated(); n > 0 {
+		fmt.Fprintf(stderr, "%s: отрезана недописанная запись, %d байт\n", *path, n)
+	}
+
+	switch args[0] {
+	case "list":
+		sys, err := units.ParseSystem(*unitsName)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		loc, err := i18n.Parse(*locale)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		if *format != formatText && *format != formatJSON && *format != formatCSV {
+			fmt.Fprintf(stderr, "неизвестный формат вывода %q\n", *format)
+			return exitUsage
+		}
+		start, err := parseDay(*from)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		end, err := parseDay(*to)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		if !end.IsZero() {
+			// Конец периода включительно
+			end = end.AddDate(0, 0, 1)
+		}
+
+		entries, err := j.Range(start, end)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		out := newOutput(*format, loc, sys, stdout, journalColumns, journalRow)
+		for _, e := range entries {
+			if *kind != "" && string(e.Kind) != *kind {
+				continue
+			}
+			if err := out.write(e); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitFailed
+			}
+		}
+		if err := out.flush(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	case "delete":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, "ожидается номер записи, например tracker journal delete --journal activity.journal 42")
+			return exitUsage
+		}
+		seq, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+		if err != nil {
+			fmt.Fprintf(stderr, "неверный номер записи %q\n", fs.Arg(0))
+			return exitUsage
+		}
+		if err := j.Delete(seq); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	case "compact":
+		if err := j.Compact(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		fmt.Fprintf(stdout, "Записей в журнале: %d\n", j.Len())
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "неизвестная команда journal %q: ожидается list, delete или compact\n", args[0])
+		return exitUsage
+	}
+}
+
+// parseDay разбирает дату ГГГГ-ММ-ДД в локальном часовом поясе, пустая строка - нулевое время
+func parseDay(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата %q: ожидается ГГГГ-ММ-ДД", value)
+	}
+	return d, nil
+}
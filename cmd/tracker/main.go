@@ -2,68 +2,74 @@ package main
 
 import (
 	"fmt"
-	"log"
-
-	"github.com/Yandex-Practicum/tracker/internal/daysteps"
-	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"io"
+	"os"
 )
 
-func main() {
-	weight := 84.6
-	height := 1.87
-
-	// дневная активность
-	input := []string{
-		"678,0h50m",
-		"792,1h14m",
-		"1078,1h30m",
-		"7830,2h40m",
-		",3456",
-		"12:40:00, 3456",
-		"something is wrong",
-	}
-
-	fmt.Println("Активность в течение дня")
-
-	var (
-		dayActionsInfo string
-		dayActionsLog  []string
-	)
-
-	for _, v := range input {
-		dayActionsInfo = daysteps.DayActionInfo(v, weight, height)
-		dayActionsLog = append(dayActionsLog, dayActionsInfo)
-	}
+// Коды завершения программы
+const (
+	exitOK      = 0
+	exitFailed  = 1 // часть записей не удалось обработать
+	exitUsage   = 2 // неверные аргументы командной строки
+	usageHeader = `Использование: tracker <команда> [флаги] [файлы...]
 
-	for _, v := range dayActionsLog {
-		fmt.Println(v)
-	}
+Команды:
+  day        обработать пакеты дневной активности "шаги,продолжительность"
+  training   обработать тренировки "шаги,активность,продолжительность"
+  import     рассчитать тренировки из файлов спортивных часов (.gpx, .tcx, .fit)
+             и тренировок из export.xml Apple Health
+  health     подвести итоги дней по шагам из export.xml Apple Health
+  tdee       рассчитать суммарный расход энергии за день: основной обмен,
+             ходьба и тренировки
+  weight     вести историю веса: weight add добавляет взвешивание,
+             weight show выводит историю со сглаженным трендом
+  journal    работать с журналом активности: journal list выводит записи за период,
+             journal delete удаляет запись, journal compact сжимает файл
+  calibrate  рассчитать длину шага по контрольной дистанции и сохранить в профиль
+  serve      запустить HTTP JSON API для расчета и хранения активности
+  grpc       запустить gRPC-сервис расчета калорий
 
-	// тренировки
-	trainings := []string{
-		"3456,Ходьба,3h00m",
-		"something is wrong",
-		"678,Бег,0h5m",
-		"1078,Бег,0h10m",
-		",3456 Ходьба",
-		"7892,Ходьба,3h10m",
-		"15392,Бег,0h45m",
-	}
+Записи читаются по одной на строку из файлов или из stdin, если файлы не указаны
+или указан "-". Флаги команды: tracker <команда> -h
+`
+)
 
-	var trainingLog []string
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
 
-	for _, v := range trainings {
-		trainingInfo, err := spentcalories.TrainingInfo(v, weight, height)
-		if err != nil {
-			log.Printf("не получилось получить информацию о тренировке: %v", err)
-			continue
-		}
-		trainingLog = append(trainingLog, trainingInfo)
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usageHeader)
+		return exitUsage
 	}
 
-	fmt.Println("Журнал тренировок")
-
-	for _, v := range trainingLog {
-		fmt.Println(v)
+	switch args[0] {
+	case "day":
+		return runDay(args[1:], stdin, stdout, stderr)
+	case "training":
+		return runTraining(args[1:], stdin, stdout, stderr)
+	case "import":
+		return runImport(args[1:], stdout, stderr)
+	case "health":
+		return runHealth(args[1:], stdin, stdout, stderr)
+	case "tdee":
+		return runTDEE(args[1:], stdin, stdout, stderr)
+	case "weight":
+		return runWeight(args[1:], stdout, stderr)
+	case "journal":
+		return runJournal(args[1:], stdout, stderr)
+	case "calibrate":
+		return runCalibrate(args[1:], stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stdout, stderr)
+	case "grpc":
+		return runGRPC(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usageHeader)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "неизвестная команда %q\n\n%s", args[0], usageHeader)
+		return exitUsage
 	}
 }
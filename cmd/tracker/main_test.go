@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestMainSuite(t *testing.T) {
+	suite.Run(t, new(MainTestSuite))
+}
+
+func (suite *MainTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+// file создает во временном каталоге файл с содержимым content и возвращает путь к нему
+func (suite *MainTestSuite) file(name, content string) string {
+	path := filepath.Join(suite.dir, name)
+	assert.NoError(suite.T(), os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// run запускает программу и возвращает код завершения, stdout и stderr
+func (suite *MainTestSuite) run(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func (suite *MainTestSuite) TestUsage() {
+	code, _, stderr := suite.run("")
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.Contains(suite.T(), stderr, "Использование")
+
+	code, _, stderr = suite.run("", "nope")
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.Contains(suite.T(), stderr, `неизвестная команда "nope"`)
+
+	code, stdout, _ := suite.run("", "help")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Contains(suite.T(), stdout, "Использование")
+
+	code, _, stderr = suite.run("", "day", "--height", "1.75")
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.Contains(suite.T(), stderr, "--weight")
+
+	code, _, _ = suite.run("", "day", "--weight", "75", "--height", "1.75", "--format", "xml")
+	assert.Equal(suite.T(), exitUsage, code)
+
+	code, _, _ = suite.run("", "training", "--weight", "75", "--height", "1.75", "--model", "magic")
+	assert.Equal(suite.T(), exitUsage, code)
+}
+
+func (suite *MainTestSuite) TestFormats() {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "text", want: "Количество шагов: 678.\nДистанция составила 0.53 км.\nВы сожгли 20.02 ккал.\n\n"},
+		{format: "json", want: `{"steps":678,"duration":"50m0s","distance_km":0.533925,"speed_kmh":0.64071,"calories":20.022187499999998}` + "\n"},
+		{format: "csv", want: "start,steps,duration,distance_km,speed_kmh,calories\n,678,50m0s,0.53,0.64,20.02\n"},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.format, func() {
+			code, stdout, stderr := suite.run("678,50m\n", "day", "--weight", "75", "--height", "1.75", "--format", tt.format)
+			assert.Equal(suite.T(), exitOK, code)
+			assert.Equal(suite.T(), tt.want, stdout)
+			assert.Empty(suite.T(), stderr)
+		})
+	}
+}
+
+func (suite *MainTestSuite) TestImperialJSON() {
+	// Поля пересчитываются на месте, порядок совпадает с метрическим выводом
+	code, stdout, stderr := suite.run("678,50m\n", "day", "--weight", "75kg", "--height", "175cm", "--units", "imperial", "--format", "json")
+	assert.Equal(suite.T(), exitOK, code, stderr)
+	assert.Regexp(suite.T(), `^\{"steps":678,"duration":"50m0s","distance_mi":0\.33\d*,"speed_mph":0\.39\d*,"calories":20\.022187499999998\}\n$`, stdout)
+}
+
+func (suite *MainTestSuite) TestTraining() {
+	code, stdout, stderr := suite.run("8000,Бег,1h\n5000,Ходьба,1h\n", "training", "--weight", "75", "--height", "1.75", "--model", "met", "--format", "csv")
+	assert.Equal(suite.T(), exitOK, code, stderr)
+	assert.Equal(suite.T(), "start,activity,steps,duration,distance_km,speed_kmh,calories,model,zones\n,Бег,8000,1h0m0s,6.30,6.30,450.00,met,\n,Ходьба,5000,1h0m0s,3.94,3.94,210.00,met,\n", stdout)
+}
+
+func (suite *MainTestSuite) TestRecordErrors() {
+	code, stdout, stderr := suite.run("678,50m\n678\n", "day", "--weight", "75", "--height", "1.75", "--format", "json")
+	assert.Equal(suite.T(), exitFailed, code)
+	assert.Equal(suite.T(), 1, strings.Count(stdout, "\n"))
+	assert.Contains(suite.T(), stderr, "-:2: ")
+	assert.Contains(suite.T(), stderr, "обработано 2 записи, с ошибками: 1")
+}
+
+func (suite *MainTestSuite) TestMissingFileKeepsResults() {
+	good := suite.file("day.txt", "678,50m\n")
+	missing := filepath.Join(suite.dir, "missing.txt")
+
+	// Результаты файлов до и после недоступного печатаются, а код завершения сообщает об ошибке
+	code, stdout, stderr := suite.run("", "day", "--weight", "75", "--height", "1.75", "--format", "json", good, missing, good)
+	assert.Equal(suite.T(), exitFailed, code)
+	assert.Equal(suite.T(), 2, strings.Count(stdout, `"steps":678`))
+	assert.Contains(suite.T(), stderr, missing)
+}
+
+func (suite *MainTestSuite) TestHealthMissingFileKeepsResults() {
+	export := suite.file("export.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<HealthData locale="ru_RU">
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" unit="count" startDate="2024-03-05 07:00:00 +0300" endDate="2024-03-05 08:00:00 +0300" value="6000"/>
+</HealthData>
+`)
+	missing := filepath.Join(suite.dir, "missing.xml")
+
+	code, stdout, stderr := suite.run("", "health", "--weight", "75", "--height", "1.75", "--format", "json", missing, export)
+	assert.Equal(suite.T(), exitFailed, code)
+	assert.Contains(suite.T(), stdout, `"steps":6000`)
+	assert.Contains(suite.T(), stderr, missing)
+}
+
+func (suite *MainTestSuite) TestProfile() {
+	p := suite.file("profile.json", `{"weight":75,"height":1.75}`)
+
+	code, fromProfile, _ := suite.run("678,50m\n", "day", "--profile", p)
+	assert.Equal(suite.T(), exitOK, code)
+	_, fromFlags, _ := suite.run("678,50m\n", "day", "--weight", "75", "--height", "1.75")
+	assert.Equal(suite.T(), fromFlags, fromProfile)
+
+	// Явные флаги переопределяют профиль
+	code, stdout, _ := suite.run("678,50m\n", "day", "--profile", p, "--weight", "80")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Contains(suite.T(), stdout, "21.36 ккал")
+
+	code, _, stderr := suite.run("", "day", "--profile", filepath.Join(suite.dir, "missing.json"))
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.NotEmpty(suite.T(), stderr)
+}
+
+func (suite *MainTestSuite) TestProfilePrecedence() {
+	p := suite.file("profile.json", `{"weight":75,"height":1.75,"locale":"en","units":"imperial"}`)
+
+	// Язык и единицы берутся из профиля
+	code, stdout, _ := suite.run("678,50m\n", "day", "--profile", p)
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Equal(suite.T(), "678 steps.\nDistance: 0.33 mi.\nBurned: 20.02 kcal.\n\n", stdout)
+
+	// Явные флаги переопределяют профиль
+	code, stdout, _ = suite.run("678,50m\n", "day", "--profile", p, "--locale", "ru", "--units", "metric", "--weight", "80")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Equal(suite.T(), "Количество шагов: 678.\nДистанция составила 0.53 км.\nВы сожгли 21.36 ккал.\n\n", stdout)
+}
+
+func (suite *MainTestSuite) TestCalibrateUnits() {
+	metric := filepath.Join(suite.dir, "metric.json")
+	imperial := filepath.Join(suite.dir, "imperial.json")
+
+	code, stdout, stderr := suite.run("", "calibrate", "--steps", "520", "--distance", "402.336", "--profile", metric)
+	assert.Equal(suite.T(), exitOK, code, stderr)
+	assert.Contains(suite.T(), stdout, "0.77 m")
+
+	// Четверть мили в ярдах без единиц дает тот же шаг, выведенный в дюймах
+	code, stdout, stderr = suite.run("", "calibrate", "--steps", "520", "--distance", "440", "--units", "imperial", "--profile", imperial)
+	assert.Equal(suite.T(), exitOK, code, stderr)
+	assert.Contains(suite.T(), stdout, "30.5 in")
+
+	// Выведенная длина шага принимается флагом --stride в тех же единицах
+	code, fromMetric, _ := suite.run("6000,1h\n", "day", "--profile", metric, "--weight", "75", "--height", "1.75", "--format", "json")
+	assert.Equal(suite.T(), exitOK, code)
+	code, fromImperial, _ := suite.run("6000,1h\n", "day", "--profile", imperial, "--weight", "75", "--height", "1.75", "--format", "json")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Equal(suite.T(), fromMetric, fromImperial)
+
+	code, _, stderr = suite.run("", "calibrate", "--steps", "520", "--distance", "far", "--profile", metric)
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.Contains(suite.T(), stderr, "дистанцию")
+}
+
+func (suite *MainTestSuite) TestStrideUnits() {
+	day := func(flags ...string) (int, string, string) {
+		args := append([]string{"day", "--weight", "75kg", "--height", "175cm", "--format", "csv"}, flags...)
+		return suite.run("6000,1h\n", args...)
+	}
+
+	code, metric, _ := day("--stride", "0.762")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Contains(suite.T(), metric, ",4.57,")
+	for _, stride := range []string{"30in", "76.2cm", `2'6"`} {
+		code, got, stderr := day("--stride", stride)
+		assert.Equal(suite.T(), exitOK, code, stderr)
+		assert.Equal(suite.T(), metric, got)
+	}
+
+	// В имперской системе число без единиц - дюймы, а дистанция выводится в милях
+	code, imperial, stderr := day("--stride", "30", "--units", "imperial")
+	assert.Equal(suite.T(), exitOK, code, stderr)
+	assert.Contains(suite.T(), imperial, ",2.84,")
+
+	code, _, stderr = day("--stride", "1yd")
+	assert.Equal(suite.T(), exitUsage, code)
+	assert.Contains(suite.T(), stderr, "длины шага")
+}
+
+func (suite *MainTestSuite) TestJournal() {
+	path := filepath.Join(suite.dir, "activity.journal")
+	code, _, stderr := suite.run("08:00,678,50m\n", "day", "--weight", "75", "--height", "1.75", "--date", "2024-03-05", "--journal", path)
+	assert.Equal(suite.T(), exitOK, code, stderr)
+
+	// Просмотр открывает журнал только для чтения и не создает его
+	code, stdout, _ := suite.run("", "journal", "list", "--journal", path, "--format", "csv")
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Contains(suite.T(), stdout, "\n1,day,")
+	code, _, _ = suite.run("", "journal", "list", "--journal", filepath.Join(suite.dir, "missing.journal"))
+	assert.Equal(suite.T(), exitFailed, code)
+	_, err := os.Stat(filepath.Join(suite.dir, "missing.journal"))
+	assert.ErrorIs(suite.T(), err, os.ErrNotExist)
+
+	code, _, _ = suite.run("", "journal", "delete", "--journal", path, "1")
+	assert.Equal(suite.T(), exitOK, code)
+	code, stdout, _ = suite.run("", "journal", "compact", "--journal", path)
+	assert.Equal(suite.T(), exitOK, code)
+	assert.Equal(suite.T(), "Записей в журнале: 0\n", stdout)
+}
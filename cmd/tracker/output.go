@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/tcx"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Поддерживаемые форматы вывода
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
+	// tcx поддерживается только для тренировок, документ пишется целиком при flush
+	formatTCX = "tcx"
+)
+
+func validFormat(format string) bool {
+	return format == formatText || format == formatJSON || format == formatCSV || format == formatTCX
+}
+
+// formatter - результат, который умеет форматировать себя на нужном языке и в нужных единицах
+type formatter interface {
+	Format(loc i18n.Locale, sys units.System) string
+}
+
+// output печатает результаты в выбранном формате.
+// json выводит по одному объекту на строку, csv - заголовок и строки с колонками columns
+type output[T formatter] struct {
+	format  string
+	loc     i18n.Locale
+	sys     units.System
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	columns []string
+	row     func(T, units.System) []string
+	started bool
+	// export пишет все результаты одним документом, для форматов вроде tcx
+	export  func(io.Writer, []T) error
+	pending []T
+}
+
+func newOutput[T formatter](format string, loc i18n.Locale, sys units.System, w io.Writer, columns []string, row func(T, units.System) []string) *output[T] {
+	buf := bufio.NewWriter(w)
+	return &output[T]{format: format, loc: loc, sys: sys, buf: buf, csv: csv.NewWriter(buf), columns: columns, row: row}
+}
+
+func (o *output[T]) write(v T) error {
+	switch o.format {
+	case formatTCX:
+		if o.export == nil {
+			return fmt.Errorf("формат %s не поддерживается для этих записей", o.format)
+		}
+		o.pending = append(o.pending, v)
+		return nil
+	case formatJSON:
+		data, err := marshalJSON(v, o.sys)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(o.buf, "%s\n", data)
+		return err
+	case formatCSV:
+		if !o.started {
+			o.started = true
+			columns := make([]string, len(o.columns))
+			for i, c := range o.columns {
+				columns[i] = o.sys.FieldName(c)
+			}
+			if err := o.csv.Write(columns); err != nil {
+				return err
+			}
+		}
+		return o.csv.Write(o.row(v, o.sys))
+	default:
+		_, err := fmt.Fprintln(o.buf, v.Format(o.loc, o.sys))
+		return err
+	}
+}
+
+func (o *output[T]) flush() error {
+	if o.export != nil && o.format == formatTCX {
+		if err := o.export(o.buf, o.pending); err != nil {
+			return err
+		}
+	}
+	o.csv.Flush()
+	if err := o.csv.Error(); err != nil {
+		return err
+	}
+	return o.buf.Flush()
+}
+
+// marshalJSON кодирует результат с продолжительностями в виде строк, как в csv,
+// а в имперской системе переименовывает и пересчитывает поля вида distance_km
+// и speed_kmh. Порядок полей одинаков в обеих системах
+func marshalJSON(v any, sys units.System) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return rewriteJSON(data, jsonField(sys))
+}
+
+// jsonField возвращает преобразование числовых полей для системы sys:
+// продолжительность в наносекундах заменяется строкой вида 1h0m0s,
+// а поля с метрическими единицами пересчитываются
+func jsonField(sys units.System) func(string, json.Number) (string, any) {
+	return func(name string, v json.Number) (string, any) {
+		if name == "duration" {
+			if ns, err := v.Int64(); err == nil {
+				return name, time.Duration(ns).String()
+			}
+			return name, v
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return name, v
+		}
+		if converted, value := sys.Field(name, f); converted != name {
+			return converted, value
+		}
+		return name, v
+	}
+}
+
+// rewriteJSON передает числовые поля объекта data, включая вложенные, в fn
+// и записывает возвращенные имя и значение. Порядок полей сохраняется
+func rewriteJSON(data []byte, fn func(name string, v json.Number) (string, any)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	_, out, err := rewriteValue(dec, "", fn)
+	return out, err
+}
+
+func rewriteValue(dec *json.Decoder, name string, fn func(string, json.Number) (string, any)) (string, []byte, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		var buf bytes.Buffer
+		buf.WriteString(t.String())
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			field := ""
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return "", nil, err
+				}
+				field, _ = key.(string)
+			}
+			field, value, err := rewriteValue(dec, field, fn)
+			if err != nil {
+				return "", nil, err
+			}
+			if t == '{' {
+				key, err := json.Marshal(field)
+				if err != nil {
+					return "", nil, err
+				}
+				buf.Write(key)
+				buf.WriteByte(':')
+			}
+			buf.Write(value)
+		}
+		end, err := dec.Token()
+		if err != nil {
+			return "", nil, err
+		}
+		buf.WriteString(end.(json.Delim).String())
+		return name, buf.Bytes(), nil
+	case json.Number:
+		name, value := fn(name, t)
+		out, err := json.Marshal(value)
+		return name, out, err
+	default:
+		out, err := json.Marshal(t)
+		return name, out, err
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// formatTime возвращает пустую строку для записей без времени начала
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var dayColumns = []string{"start", "steps", "duration", "distance_km", "speed_kmh", "calories"}
+
+func dayRow(r daysteps.DayActionResult, sys units.System) []string {
+	return []string{
+		formatTime(r.Start),
+		strconv.Itoa(r.Steps),
+		r.Duration.String(),
+		formatFloat(sys.Distance(r.Distance)),
+		formatFloat(sys.Speed(r.Speed)),
+		formatFloat(r.Calories),
+	}
+}
+
+// newTrainingOutput создает вывод тренировок, в том числе в формате tcx
+func newTrainingOutput(format string, set settings, w io.Writer) *output[spentcalories.TrainingResult] {
+	out := newOutput(format, set.loc, set.sys, w, trainingColumns, trainingRow)
+	out.export = tcx.Export
+	return out
+}
+
+var daySummaryColumns = []string{"date", "steps", "duration", "distance_km", "calories", "packets", "rejected"}
+
+func daySummaryRow(s daysteps.DaySummary, sys units.System) []string {
+	return []string{
+		s.Date.Format(time.DateOnly),
+		strconv.Itoa(s.Steps),
+		s.Duration.String(),
+		formatFloat(sys.Distance(s.Distance)),
+		formatFloat(s.Calories),
+		strconv.Itoa(s.Packets),
+		strconv.Itoa(s.Rejected),
+	}
+}
+
+var trainingColumns = []string{"start", "activity", "steps", "duration", "distance_km", "speed_kmh", "calories", "model", "zones"}
+
+func trainingRow(r spentcalories.TrainingResult, sys units.System) []string {
+	return []string{
+		formatTime(r.Start),
+		r.Activity,
+		strconv.Itoa(r.Steps),
+		r.Duration.String(),
+		formatFloat(sys.Distance(r.Distance)),
+		formatFloat(sys.Speed(r.Speed)),
+		formatFloat(r.Calories),
+		r.Model.String(),
+		formatZones(r.Zones),
+	}
+}
+
+// formatZones выводит время в зонах с 1 по 5 через "/", например 0s/5m0s/20m0s/5m0s/0s
+func formatZones(zones []hrzone.ZoneTime) string {
+	parts := make([]string, 0, len(zones))
+	for _, z := range zones {
+		if z.Zone > 0 {
+			parts = append(parts, z.Duration.String())
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/batch"
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/journal"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/Yandex-Practicum/tracker/internal/weight"
+)
+
+// commonFlags - флаги, общие для команд обработки записей
+type commonFlags struct {
+	weight  string
+	height  string
+	units   string
+	profile string
+	format  string
+	workers int
+	date    string
+	sex     string
+	stride  string
+	locale  string
+	age     int
+	maxHR   float64
+	restHR  float64
+	zones   string
+	// Дата рождения ГГГГ-ММ-ДД
+	birthDate string
+	// История взвешиваний и способ выбора веса из нее
+	weightLog    string
+	weightMethod string
+	// Журнал, в который сохраняются результаты
+	journal string
+	// База SQLite и пользователь в ней, под которым сохраняются результаты
+	db   string
+	user string
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.weight, "weight", "", "вес в кг или фунтах для --units imperial, переопределяет значение из профиля")
+	fs.StringVar(&c.height, "height", "", `рост в м или дюймах для --units imperial, например 1.87, 187cm, 5'11", переопределяет значение из профиля`)
+	fs.StringVar(&c.units, "units", string(units.Metric), "система единиц ввода и вывода: metric или imperial")
+	fs.StringVar(&c.profile, "profile", "", "путь к файлу профиля пользователя в JSON или YAML (.yaml, .yml)")
+	fs.StringVar(&c.format, "format", formatText, "формат вывода: text, json, csv или tcx для тренировок")
+	fs.IntVar(&c.workers, "workers", 0, "количество параллельных обработчиков, 0 - по числу CPU")
+	fs.StringVar(&c.sex, "sex", "", "пол male или female, переопределяет значение из профиля")
+	fs.StringVar(&c.stride, "stride", "", `измеренная длина шага в м или дюймах для --units imperial, например 0.78, 78cm, 2'7", переопределяет значение из профиля`)
+	fs.StringVar(&c.locale, "locale", string(i18n.Default), "язык вывода: ru или en")
+	fs.IntVar(&c.age, "age", 0, "возраст в полных годах, переопределяет возраст по дате рождения из профиля")
+	fs.StringVar(&c.birthDate, "birth-date", "", "дата рождения ГГГГ-ММ-ДД, переопределяет значение из профиля")
+	fs.Float64Var(&c.maxHR, "max-hr", 0, "максимальный пульс, переопределяет значение из профиля")
+	fs.Float64Var(&c.restHR, "rest-hr", 0, "пульс покоя, переопределяет значение из профиля")
+	fs.StringVar(&c.zones, "zones", "", "способ расчета пульсовых зон: max или hrr, переопределяет значение из профиля")
+	fs.StringVar(&c.weightLog, "weight-log", "", "JSON-файл истории веса: тренировки считаются по весу на свою дату")
+	fs.StringVar(&c.weightMethod, "weight-method", weight.LastKnown.String(), "вес между взвешиваниями: last - последний известный, interpolate - интерполяция")
+	fs.StringVar(&c.journal, "journal", "", "файл журнала активности, в который добавляются результаты")
+	fs.StringVar(&c.db, "db", "", "файл базы SQLite, в которую сохраняются профиль и результаты")
+	fs.StringVar(&c.user, "user", defaultUser, "пользователь, под которым результаты сохраняются в базу --db")
+	fs.StringVar(&c.date, "date", "", "дата ГГГГ-ММ-ДД для записей, где указано только время суток")
+}
+
+// day возвращает дату из флага --date в локальном часовом поясе
+func (c *commonFlags) day() (time.Time, error) {
+	if c.date == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseInLocation(time.DateOnly, c.date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата %q: ожидается ГГГГ-ММ-ДД", c.date)
+	}
+	return d, nil
+}
+
+// settings - проверенные параметры запуска команды
+type settings struct {
+	profile profile.Profile
+	loc     i18n.Locale
+	sys     units.System
+	date    time.Time
+	age     int
+	// История веса, nil если не задана
+	weights *weight.Log
+	method  weight.Method
+}
+
+// resolve собирает профиль из файла и явно заданных флагов и проверяет остальные флаги
+func (c *commonFlags) resolve(fs *flag.FlagSet) (settings, error) {
+	var s settings
+	var err error
+
+	if s.date, err = c.day(); err != nil {
+		return s, err
+	}
+	if !validFormat(c.format) {
+		return s, fmt.Errorf("неизвестный формат вывода %q", c.format)
+	}
+
+	p := &s.profile
+	if c.profile != "" {
+		if *p, err = profile.Load(c.profile); err != nil {
+			return s, err
+		}
+	}
+
+	// Единицы и язык берутся из профиля, если не заданы флагами
+	visited := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { visited[f.Name] = true })
+	unitsName, localeName := c.units, c.locale
+	if !visited["units"] && p.Units != "" {
+		unitsName = string(p.Units)
+	}
+	if !visited["locale"] && p.Locale != "" {
+		localeName = string(p.Locale)
+	}
+	if s.sys, err = units.ParseSystem(unitsName); err != nil {
+		return s, err
+	}
+	if s.loc, err = i18n.Parse(localeName); err != nil {
+		return s, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "weight":
+			p.Weight, err = units.ParseWeight(c.weight, s.sys)
+		case "height":
+			p.Height, err = units.ParseHeight(c.height, s.sys)
+		case "birth-date":
+			p.BirthDate, err = profile.ParseDate(c.birthDate)
+		case "sex":
+			p.Sex = profile.Sex(c.sex)
+		case "stride":
+			p.Stride, err = units.ParseStride(c.stride, s.sys)
+		case "max-hr":
+			p.MaxHeartRate = c.maxHR
+		case "rest-hr":
+			p.RestingHeartRate = c.restHR
+		case "zones":
+			cfg := hrzone.Config{Method: hrzone.Method(c.zones)}
+			if p.Zones != nil {
+				cfg.Bounds = p.Zones.Bounds
+			}
+			p.Zones = &cfg
+		}
+	})
+	if err != nil {
+		return s, err
+	}
+
+	if c.weightLog != "" {
+		if s.method, err = weight.ParseMethod(c.weightMethod); err != nil {
+			return s, err
+		}
+		if s.weights, err = weight.Load(c.weightLog); err != nil {
+			return s, err
+		}
+		// Без --weight текущим весом считается вес из истории на дату записей
+		if kg, ok := s.weights.At(s.at(), s.method); ok && !visited["weight"] {
+			p.Weight = kg
+		}
+	}
+
+	if p.Weight == 0 {
+		return s, fmt.Errorf("не задан вес: задайте --weight, --profile или --weight-log")
+	}
+	if p.Height == 0 {
+		return s, fmt.Errorf("не задан рост: задайте --height или --profile")
+	}
+	if err := p.Validate(); err != nil {
+		return s, errors.New(i18n.Localize(err, s.loc))
+	}
+
+	// Возраст из флага переопределяет возраст по дате рождения на дату записей
+	if c.age < 0 {
+		return s, fmt.Errorf("возраст не может быть отрицательным")
+	}
+	s.age = c.age
+	if !visited["age"] {
+		s.age = p.Age(s.at())
+	}
+	return s, nil
+}
+
+// at возвращает дату записей из --date или текущий момент
+func (s settings) at() time.Time {
+	if s.date.IsZero() {
+		return time.Now()
+	}
+	return s.date
+}
+
+func runDay(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("day", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags commonFlags
+	flags.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	set, err := flags.resolve(fs)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if flags.format == formatTCX {
+		fmt.Fprintln(stderr, "формат tcx поддерживается только для тренировок")
+		return exitUsage
+	}
+
+	p := set.profile
+	// Дневная активность и тренировки считают дистанцию по одной модели шага из профиля
+	opts := []daysteps.Option{daysteps.WithDate(set.date), daysteps.WithStride(stride.FromProfile(p))}
+	out := newOutput(flags.format, set.loc, set.sys, stdout, dayColumns, dayRow)
+	return runRecords(fs.Args(), stdin, stderr, flags.workers, func(record string) (daysteps.DayActionResult, error) {
+		return daysteps.DayAction(record, p.Weight, p.Height, opts...)
+	}, out, &flags, set, journal.Day)
+}
+
+func runTraining(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("training", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags commonFlags
+	flags.register(fs)
+	model := fs.String("model", spentcalories.SpeedModel.String(), "модель расчета калорий: speed, met или hr - по пульсу, если он есть в данных")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	set, err := flags.resolve(fs)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	p := set.profile
+	opts, err := trainingOptions(set, *model)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	out := newTrainingOutput(flags.format, set, stdout)
+	return runRecords(fs.Args(), stdin, stderr, flags.workers, func(record string) (spentcalories.TrainingResult, error) {
+		return spentcalories.Training(record, p.Weight, p.Height, opts...)
+	}, out, &flags, set, journal.Training)
+}
+
+// trainingOptions возвращает опции расчета тренировок для профиля и модели калорий
+func trainingOptions(set settings, model string) ([]spentcalories.Option, error) {
+	m, err := spentcalories.ParseModel(model)
+	if err != nil {
+		return nil, err
+	}
+	opts := []spentcalories.Option{
+		spentcalories.WithDate(set.date),
+		spentcalories.WithStride(stride.FromProfile(set.profile)),
+		spentcalories.WithModel(m),
+		spentcalories.WithAge(set.age),
+		spentcalories.WithSex(set.profile.Sex),
+	}
+
+	if set.weights != nil {
+		opts = append(opts, spentcalories.WithWeightHistory(set.weights.Source(set.method)))
+	}
+
+	zones, ok, err := set.profile.HeartRateZones(set.age)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, spentcalories.WithHeartRateZones(zones))
+	}
+	return opts, nil
+}
+
+// runRecords обрабатывает все входные файлы и печатает сводку об ошибках.
+// Если заданы журнал или база, результаты сохраняются в них в виде записей entry
+func runRecords[T formatter](files []string, stdin io.Reader, stderr io.Writer, workers int, fn batch.Func[T], out *output[T],
+	flags *commonFlags, set settings, entry func(T) journal.Entry) int {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	sv, err := openSaver(flags, set, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	// Ошибка чтения одного файла не отменяет результаты остальных: сообщаем о ней
+	// и переходим к следующему, а код завершения выставляем в конце
+	var processed, failed int
+	broken := false
+	for _, name := range files {
+		err := withInput(name, stdin, func(r io.Reader) error {
+			return batch.Process(context.Background(), r, workers, fn, func(item batch.Item[T]) error {
+				processed++
+				if item.Err != nil {
+					failed++
+					fmt.Fprintf(stderr, "%s:%d: %s\n", name, item.Line, i18n.Localize(item.Err, out.loc))
+					return nil
+				}
+				if err := out.write(item.Value); err != nil {
+					return err
+				}
+				return sv.add(entry(item.Value))
+			})
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			broken = true
+		}
+	}
+
+	if err := sv.close(); err != nil {
+		fmt.Fprintln(stderr, err)
+		broken = true
+	}
+	if err := out.flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	if failed > 0 {
+		fmt.Fprintln(stderr, out.loc.T("cli.summary", out.loc.Count(processed, "noun.records"), failed))
+		return exitFailed
+	}
+	if broken {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// withInput открывает файл или использует stdin для имени "-"
+func withInput(name string, stdin io.Reader, fn func(io.Reader) error) error {
+	if name == "-" {
+		return fn(stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return fn(f)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/server"
+	"github.com/Yandex-Practicum/tracker/internal/storage"
+)
+
+// Время на завершение обрабатываемых запросов при остановке сервера
+const shutdownTimeout = 10 * time.Second
+
+// runServe запускает HTTP JSON API с результатами в базе SQLite и работает до SIGINT или SIGTERM
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "адрес, на котором сервер принимает запросы")
+	db := fs.String("db", "tracker.db", "файл базы SQLite с профилями и результатами")
+	tz := fs.String("tz", "", "часовой пояс для дат запросов и сводок, например Europe/Moscow, по умолчанию местный")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	loc := time.Local
+	if *tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(*tz); err != nil {
+			fmt.Fprintf(stderr, "неизвестный часовой пояс %q\n", *tz)
+			return exitUsage
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.Open(ctx, *db)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	defer store.Close()
+
+	logger := log.New(stderr, "", log.LstdFlags)
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(store, server.WithLocation(loc), server.WithLogger(logger)),
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          logger,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(stdout, "Сервер слушает %s, API: %s\n", *addr, server.Prefix)
+
+	select {
+	case err := <-errs:
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
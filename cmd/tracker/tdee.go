@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/batch"
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/tdee"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+var tdeeColumns = []string{"date", "bmr_formula", "bmr", "steps", "walking_calories", "trainings", "training_calories", "total_calories"}
+
+func tdeeRow(r tdee.Report, _ units.System) []string {
+	return []string{
+		r.Date.Format(time.DateOnly),
+		r.Formula.String(),
+		formatFloat(r.BMR),
+		strconv.Itoa(r.Steps),
+		formatFloat(r.Walking),
+		strconv.Itoa(r.Trainings),
+		formatFloat(r.Training),
+		formatFloat(r.Total),
+	}
+}
+
+// runTDEE считает суммарный расход энергии за день: основной обмен по профилю,
+// пакеты шагов из файлов и тренировки из файла --trainings
+func runTDEE(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tdee", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var flags commonFlags
+	flags.register(fs)
+	formula := fs.String("bmr", spentcalories.MifflinStJeor.String(), "формула основного обмена: mifflin или harris")
+	trainings := fs.String("trainings", "", `файл тренировок "шаги,активность,продолжительность" за этот день`)
+	model := fs.String("model", spentcalories.SpeedModel.String(), "модель расчета калорий тренировок: speed, met или hr")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	set, err := flags.resolve(fs)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	if flags.format == formatTCX {
+		fmt.Fprintln(stderr, "формат tcx поддерживается только для тренировок")
+		return exitUsage
+	}
+	f, err := spentcalories.ParseBMRFormula(*formula)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	topts, err := trainingOptions(set, *model)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if set.age == 0 {
+		fmt.Fprintln(stderr, "для основного обмена нужен возраст: задайте --age или --birth-date")
+		return exitUsage
+	}
+	p := set.profile
+	bmr, err := spentcalories.BMR(f, p.Weight, p.Height, set.age, p.Sex)
+	if err != nil {
+		fmt.Fprintln(stderr, i18n.Localize(err, set.loc))
+		return exitUsage
+	}
+
+	// Без --date отчет строится за сегодня
+	date := set.date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	agg := daysteps.NewAggregator(date, p.Weight, p.Height, daysteps.WithStride(stride.FromProfile(p)))
+
+	failed := 0
+	report := func(name string, line int, err error) {
+		failed++
+		fmt.Fprintf(stderr, "%s:%d: %s\n", name, line, i18n.Localize(err, set.loc))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		err := withInput(name, stdin, func(r io.Reader) error {
+			// Агрегатор не рассчитан на параллельную работу, поэтому пакеты передаются ему по порядку
+			return batch.Process(context.Background(), r, 1, func(record string) (string, error) {
+				return record, nil
+			}, func(item batch.Item[string]) error {
+				if err := agg.Add(item.Value); err != nil {
+					report(name, item.Line, err)
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			return exitFailed
+		}
+	}
+
+	var results []spentcalories.TrainingResult
+	if *trainings != "" {
+		topts = append(topts, spentcalories.WithDate(agg.Summary().Date))
+		err := withInput(*trainings, stdin, func(r io.Reader) error {
+			return batch.Trainings(context.Background(), r, p.Weight, p.Height, flags.workers, func(item batch.Item[spentcalories.TrainingResult]) error {
+				if item.Err != nil {
+					report(*trainings, item.Line, item.Err)
+					return nil
+				}
+				results = append(results, item.Value)
+				return nil
+			}, topts...)
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", *trainings, err)
+			return exitFailed
+		}
+	}
+
+	out := newOutput(flags.format, set.loc, set.sys, stdout, tdeeColumns, tdeeRow)
+	if err := out.write(tdee.Day(f, bmr, agg.Summary(), results)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	if err := out.flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	if failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
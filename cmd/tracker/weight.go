@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/Yandex-Practicum/tracker/internal/weight"
+)
+
+var weightColumns = []string{"date", "weight_kg", "trend_kg"}
+
+func weightRow(e weight.TrendEntry, sys units.System) []string {
+	return []string{e.Date.String(), formatFloat(sys.Weight(e.Weight)), formatFloat(sys.Weight(e.Trend))}
+}
+
+// runWeight ведет историю взвешиваний: weight add добавляет вес за день,
+// weight show выводит историю со сглаженным трендом
+func runWeight(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "Использование: tracker weight add|show --log weights.json [флаги]")
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("weight "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("log", "", "JSON-файл истории веса")
+	date := fs.String("date", "", "дата взвешивания ГГГГ-ММ-ДД, по умолчанию сегодня")
+	alpha := fs.Float64("alpha", 0.1, "коэффициент сглаживания тренда от 0 до 1")
+	unitsName := fs.String("units", string(units.Metric), "система единиц: metric или imperial")
+	locale := fs.String("locale", string(i18n.Default), "язык вывода: ru или en")
+	format := fs.String("format", formatText, "формат вывода: text, json или csv")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(stderr, "не задан --log с историей веса")
+		return exitUsage
+	}
+	sys, err := units.ParseSystem(*unitsName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	loc, err := i18n.Parse(*locale)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	history, err := weight.Load(*path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	switch args[0] {
+	case "add":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, "ожидается один вес, например tracker weight add --log weights.json 84.6")
+			return exitUsage
+		}
+		kg, err := units.ParseWeight(fs.Arg(0), sys)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		day := profile.NewDate(time.Now().Date())
+		if *date != "" {
+			if day, err = profile.ParseDate(*date); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitUsage
+			}
+		}
+		if err := history.Add(day, kg); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		if err := weight.Save(*path, history); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	case "show":
+		if *format != formatText && *format != formatJSON && *format != formatCSV {
+			fmt.Fprintf(stderr, "неизвестный формат вывода %q\n", *format)
+			return exitUsage
+		}
+		trend, err := history.Trend(*alpha)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		out := newOutput(*format, loc, sys, stdout, weightColumns, weightRow)
+		for _, e := range trend {
+			if err := out.write(e); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitFailed
+			}
+		}
+		if err := out.flush(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "неизвестная команда weight %q: ожидается add или show\n", args[0])
+		return exitUsage
+	}
+}
@@ -0,0 +1,161 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Максимальная длина одной строки входных данных
+const maxLineSize = 1024 * 1024
+
+// Item - результат обработки одной строки входных данных
+type Item[T any] struct {
+	// Номер строки во входном потоке начиная с единицы
+	Line int
+	// Исходная строка
+	Raw string
+	// Результат обработки, заполнен если Err равна nil
+	Value T
+	// Ошибка обработки строки
+	Err error
+}
+
+// Func обрабатывает одну запись
+type Func[T any] func(record string) (T, error)
+
+// Process читает записи по одной на строку, обрабатывает их в workers горутинах
+// и передает результаты в emit в порядке следования строк. Пустые строки пропускаются.
+// Одновременно в обработке находится не больше 2*workers строк, поэтому память
+// не растет с размером входа. Ошибка emit или ctx останавливает обработку.
+func Process[T any](ctx context.Context, r io.Reader, workers int, fn Func[T], emit func(Item[T]) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		seq  int
+		item Item[T]
+	}
+
+	jobs := make(chan job)
+	results := make(chan job, workers)
+	// Слоты ограничивают количество строк, которые прочитаны, но еще не переданы в emit
+	slots := make(chan struct{}, 2*workers)
+
+	// Читаем строки и раздаем их воркерам. read закрывается после выхода из горутины,
+	// тогда readErr, total и complete можно читать
+	var readErr error
+	var total int
+	var complete bool
+	read := make(chan struct{})
+	go func() {
+		defer close(read)
+		defer close(jobs)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+		line, seq := 0, 0
+		for scanner.Scan() {
+			line++
+			raw := strings.TrimRight(scanner.Text(), "\r")
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{seq: seq, item: Item[T]{Line: line, Raw: raw}}:
+				seq++
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr = scanner.Err()
+		total, complete = seq, true
+	}()
+
+	// Воркеры обрабатывают записи
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.item.Value, j.item.Err = fn(j.item.Raw)
+				select {
+				case results <- j:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Восстанавливаем исходный порядок строк
+	pending := make(map[int]Item[T])
+	next := 0
+	var emitErr error
+	for j := range results {
+		if emitErr != nil {
+			continue
+		}
+		pending[j.seq] = j.item
+		for {
+			item, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-slots
+			if err := emit(item); err != nil {
+				emitErr = err
+				cancel()
+				break
+			}
+		}
+	}
+
+	<-read
+	if emitErr != nil {
+		return emitErr
+	}
+	// Отмена после того, как все строки прочитаны и переданы в emit, не ошибка
+	if err := ctx.Err(); err != nil && (!complete || next < total) {
+		return err
+	}
+	return readErr
+}
+
+// Days обрабатывает пакеты дневной активности через daysteps.DayAction
+func Days(ctx context.Context, r io.Reader, weight, height float64, workers int, emit func(Item[daysteps.DayActionResult]) error, opts ...daysteps.Option) error {
+	return Process(ctx, r, workers, func(record string) (daysteps.DayActionResult, error) {
+		return daysteps.DayAction(record, weight, height, opts...)
+	}, emit)
+}
+
+// Trainings обрабатывает записи тренировок через spentcalories.Training
+func Trainings(ctx context.Context, r io.Reader, weight, height float64, workers int, emit func(Item[spentcalories.TrainingResult]) error, opts ...spentcalories.Option) error {
+	return Process(ctx, r, workers, func(record string) (spentcalories.TrainingResult, error) {
+		return spentcalories.Training(record, weight, height, opts...)
+	}, emit)
+}
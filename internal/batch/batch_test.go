@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchTestSuite struct {
+	suite.Suite
+}
+
+func TestBatchSuite(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
+
+func (suite *BatchTestSuite) TestProcessKeepsOrder() {
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&input, "%d\n", i)
+	}
+
+	var got []int
+	err := Process(context.Background(), strings.NewReader(input.String()), 8,
+		func(record string) (int, error) {
+			var n int
+			_, err := fmt.Sscan(record, &n)
+			// Перемешиваем время обработки, чтобы результаты приходили не по порядку
+			time.Sleep(time.Duration(n%7) * time.Microsecond)
+			return n, err
+		},
+		func(item Item[int]) error {
+			assert.Equal(suite.T(), item.Value+1, item.Line)
+			got = append(got, item.Value)
+			return nil
+		})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), got, 1000)
+	for i, v := range got {
+		assert.Equal(suite.T(), i, v)
+	}
+}
+
+func (suite *BatchTestSuite) TestDaysReportsLineErrors() {
+	input := "678,0h50m\n\nsomething is wrong\r\n1078,1h30m\n"
+
+	var items []Item[daysteps.DayActionResult]
+	err := Days(context.Background(), strings.NewReader(input), 84.6, 1.87, 2, func(item Item[daysteps.DayActionResult]) error {
+		items = append(items, item)
+		return nil
+	})
+
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), items, 3) {
+		assert.Equal(suite.T(), 1, items[0].Line)
+		assert.NoError(suite.T(), items[0].Err)
+		assert.Equal(suite.T(), 678, items[0].Value.Steps)
+
+		// Пустая строка пропущена, но нумерация строк сохранена
+		assert.Equal(suite.T(), 3, items[1].Line)
+		assert.Equal(suite.T(), "something is wrong", items[1].Raw)
+		assert.ErrorIs(suite.T(), items[1].Err, daysteps.ErrFieldCount)
+
+		assert.Equal(suite.T(), 4, items[2].Line)
+		assert.Equal(suite.T(), 1078, items[2].Value.Steps)
+	}
+}
+
+func (suite *BatchTestSuite) TestTrainings() {
+	input := "3456,Ходьба,3h00m\n678,Бег,0h5m\n"
+
+	var results []spentcalories.TrainingResult
+	err := Trainings(context.Background(), strings.NewReader(input), 84.6, 1.87, 0, func(item Item[spentcalories.TrainingResult]) error {
+		results = append(results, item.Value)
+		return item.Err
+	})
+
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), results, 2) {
+		assert.Equal(suite.T(), "Ходьба", results[0].Activity)
+		assert.Equal(suite.T(), "Бег", results[1].Activity)
+	}
+}
+
+func (suite *BatchTestSuite) TestEmitErrorStops() {
+	stop := errors.New("stop")
+	input := strings.Repeat("1\n", 10000)
+
+	calls := 0
+	err := Process(context.Background(), strings.NewReader(input), 4,
+		func(record string) (string, error) { return record, nil },
+		func(item Item[string]) error {
+			calls++
+			if calls == 10 {
+				return stop
+			}
+			return nil
+		})
+
+	assert.ErrorIs(suite.T(), err, stop)
+	assert.Equal(suite.T(), 10, calls)
+}
+
+func (suite *BatchTestSuite) TestContextCancel() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Process(ctx, strings.NewReader("1\n2\n"), 1,
+		func(record string) (string, error) { return record, nil },
+		func(item Item[string]) error { return nil })
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+}
+
+func (suite *BatchTestSuite) TestLateCancelKeepsResult() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []string
+	err := Process(ctx, strings.NewReader("1\n2\n3\n"), 2,
+		func(record string) (string, error) { return record, nil },
+		func(item Item[string]) error {
+			got = append(got, item.Value)
+			// Отмена после последней записи не должна превращать прогон в ошибку
+			if item.Value == "3" {
+				cancel()
+			}
+			return nil
+		})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"1", "2", "3"}, got)
+}
@@ -0,0 +1,115 @@
+package daysteps
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// DaySummary содержит итоги дневной активности за календарный день
+type DaySummary struct {
+	// Начало дня в часовом поясе, с которым был создан агрегатор
+	Date     time.Time     `json:"date"`
+	Steps    int           `json:"steps"`
+	Duration time.Duration `json:"duration"`
+	Distance float64       `json:"distance_km"`
+	Calories float64       `json:"calories"`
+	// Количество принятых пакетов
+	Packets int `json:"packets"`
+	// Количество отклоненных пакетов
+	Rejected int `json:"rejected"`
+}
+
+// Format форматирует итоги дня на языке loc, дистанция выводится в единицах sys
+func (s DaySummary) Format(loc i18n.Locale, sys units.System) string {
+	return loc.T("day.summary", s.Date.Format(time.DateOnly), s.Steps, s.Duration.Hours(),
+		sys.Distance(s.Distance), s.Calories, loc.T(sys.DistanceKey()))
+}
+
+// Aggregator собирает пакеты одного дня в DaySummary.
+// Каждый пакет рассчитывается так же, как в DayAction, поэтому итог равен сумме пакетов
+type Aggregator struct {
+	weight  float64
+	height  float64
+	opts    []Option
+	o       options
+	summary DaySummary
+}
+
+// NewAggregator создает агрегатор для дня, в который попадает date.
+// Опции применяются к каждому пакету так же, как в DayAction
+func NewAggregator(date time.Time, weight, height float64, opts ...Option) *Aggregator {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+	a := &Aggregator{
+		weight:  weight,
+		height:  height,
+		opts:    append(append([]Option(nil), opts...), WithDate(day)),
+		summary: DaySummary{Date: day},
+	}
+	a.o = newOptions(a.opts)
+	return a
+}
+
+// Add обрабатывает пакет "[время,]шаги,продолжительность". Если пакет отклонен,
+// он учитывается в Rejected, а ошибка возвращается вызывающему коду
+func (a *Aggregator) Add(data string) error {
+	result, err := DayAction(data, a.weight, a.height, a.opts...)
+	return a.accept(result, err)
+}
+
+// AddSteps обрабатывает уже разобранную запись: время начала, шаги и продолжительность.
+// В отличие от Add, нулевая продолжительность допускается: шаги мгновенной записи,
+// например из Apple Health, учитываются в итогах дня
+func (a *Aggregator) AddSteps(start time.Time, steps int, duration time.Duration) error {
+	switch {
+	case steps <= 0:
+		a.summary.Rejected++
+		return spentcalories.NewParseError(FieldSteps, strconv.Itoa(steps), -1, ErrSteps, "reason.steps_positive")
+	case duration < 0:
+		a.summary.Rejected++
+		return spentcalories.NewParseError(FieldDuration, duration.String(), -1, ErrDuration, "reason.duration_negative")
+	}
+	result, err := dayAction(packet{start: start, steps: steps, duration: duration}, a.weight, a.height, a.o)
+	return a.accept(result, err)
+}
+
+// accept добавляет рассчитанный пакет в итоги или учитывает его как отклоненный
+func (a *Aggregator) accept(result DayActionResult, err error) error {
+	if err != nil {
+		a.summary.Rejected++
+		return err
+	}
+
+	// Пакет с временем начала должен относиться к дню агрегатора
+	if !result.Start.IsZero() && !a.sameDay(result.Start) {
+		a.summary.Rejected++
+		return spentcalories.NewParseError(FieldTime, result.Start.Format(time.RFC3339), 0, ErrTime,
+			"reason.time_other_day", a.summary.Date.Format(time.DateOnly))
+	}
+
+	a.add(result)
+	return nil
+}
+
+func (a *Aggregator) sameDay(t time.Time) bool {
+	y, m, d := t.In(a.summary.Date.Location()).Date()
+	ay, am, ad := a.summary.Date.Date()
+	return y == ay && m == am && d == ad
+}
+
+func (a *Aggregator) add(r DayActionResult) {
+	a.summary.Steps += r.Steps
+	a.summary.Duration += r.Duration
+	a.summary.Distance += r.Distance
+	a.summary.Calories += r.Calories
+	a.summary.Packets++
+}
+
+// Summary возвращает накопленные итоги дня
+func (a *Aggregator) Summary() DaySummary {
+	return a.summary
+}
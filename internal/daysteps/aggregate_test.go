@@ -0,0 +1,97 @@
+package daysteps
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *DayStepsTestSuite) TestAggregator() {
+	date := time.Date(2024, time.March, 5, 18, 30, 0, 0, time.UTC)
+	agg := NewAggregator(date, 75.0, 1.75)
+
+	packets := []string{"6000,1h00m", "3000,30m", "not valid", "0,1h", "1000,2h00m"}
+	var want DayActionResult
+	for _, p := range packets {
+		err := agg.Add(p)
+		if r, derr := DayAction(p, 75.0, 1.75); derr == nil {
+			assert.NoError(suite.T(), err)
+			want.Steps += r.Steps
+			want.Duration += r.Duration
+			want.Distance += r.Distance
+			want.Calories += r.Calories
+		} else {
+			assert.Error(suite.T(), err)
+		}
+	}
+
+	got := agg.Summary()
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), got.Date)
+	assert.Equal(suite.T(), 10000, got.Steps)
+	assert.Equal(suite.T(), 3*time.Hour+30*time.Minute, got.Duration)
+	assert.InDelta(suite.T(), 6.5, got.Distance, 1e-9)
+	assert.InDelta(suite.T(), want.Calories, got.Calories, 1e-9)
+	assert.InDelta(suite.T(), 177.19+88.59+29.53, got.Calories, 0.02)
+	assert.Equal(suite.T(), 3, got.Packets)
+	assert.Equal(suite.T(), 2, got.Rejected)
+}
+
+func (suite *DayStepsTestSuite) TestAggregatorTimestamps() {
+	loc := time.FixedZone("MSK", 3*60*60)
+	agg := NewAggregator(time.Date(2024, time.March, 5, 0, 0, 0, 0, loc), 75.0, 1.75)
+
+	assert.NoError(suite.T(), agg.Add("08:15,1000,10m"))
+	assert.NoError(suite.T(), agg.Add("2024-03-05T20:00:00Z,1000,10m"))
+
+	// В часовом поясе агрегатора это уже 6 марта
+	err := agg.Add("2024-03-05T22:00:00Z,1000,10m")
+	assert.ErrorIs(suite.T(), err, ErrTime)
+
+	got := agg.Summary()
+	assert.Equal(suite.T(), 2000, got.Steps)
+	assert.Equal(suite.T(), 2, got.Packets)
+	assert.Equal(suite.T(), 1, got.Rejected)
+}
+
+func (suite *DayStepsTestSuite) TestAggregatorOptions() {
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	agg := NewAggregator(date, 75.0, 1.75, WithStride(stride.Calibrated(0.8)))
+	assert.NoError(suite.T(), agg.Add("09:00,5000,1h"))
+
+	got := agg.Summary()
+	assert.InDelta(suite.T(), 4.0, got.Distance, 1e-9)
+	assert.Equal(suite.T(), "Дата: 2024-03-05\nКоличество шагов: 5000.\nАктивное время: 1.00 ч.\nДистанция: 4.00 км.\nВы сожгли 150.00 ккал.\n",
+		got.Format(i18n.Russian, units.Metric))
+	assert.Equal(suite.T(), "Date: 2024-03-05\nSteps: 5000.\nActive time: 1.00 h.\nDistance: 2.49 mi.\nBurned: 150.00 kcal.\n",
+		got.Format(i18n.English, units.Imperial))
+}
+
+func (suite *DayStepsTestSuite) TestAggregatorAddSteps() {
+	loc := time.FixedZone("MSK", 3*60*60)
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, loc)
+	typed := NewAggregator(date, 75.0, 1.75, WithStride(stride.Calibrated(0.8)))
+	parsed := NewAggregator(date, 75.0, 1.75, WithStride(stride.Calibrated(0.8)))
+
+	// Разобранные значения дают те же итоги, что и строки
+	assert.NoError(suite.T(), typed.AddSteps(time.Date(2024, time.March, 5, 8, 0, 0, 0, loc), 5000, time.Hour))
+	assert.NoError(suite.T(), parsed.Add("08:00,5000,1h"))
+	assert.Equal(suite.T(), parsed.Summary(), typed.Summary())
+
+	// Мгновенная запись добавляет шаги, дистанцию и калории, но не время
+	assert.NoError(suite.T(), typed.AddSteps(time.Date(2024, time.March, 5, 9, 0, 0, 0, loc), 500, 0))
+	got := typed.Summary()
+	assert.Equal(suite.T(), 5500, got.Steps)
+	assert.Equal(suite.T(), time.Hour, got.Duration)
+	assert.InDelta(suite.T(), 4.4, got.Distance, 1e-9)
+	// Калории ходьбы пропорциональны дистанции: 150 ккал на 4 км
+	assert.InDelta(suite.T(), 165.0, got.Calories, 1e-9)
+	assert.Equal(suite.T(), 2, got.Packets)
+
+	assert.ErrorIs(suite.T(), typed.AddSteps(time.Time{}, 0, time.Minute), ErrSteps)
+	assert.ErrorIs(suite.T(), typed.AddSteps(time.Time{}, 100, -time.Minute), ErrDuration)
+	assert.ErrorIs(suite.T(), typed.AddSteps(time.Date(2024, time.March, 6, 9, 0, 0, 0, loc), 100, time.Minute), ErrTime)
+	assert.Equal(suite.T(), 3, typed.Summary().Rejected)
+}
@@ -1,89 +1,198 @@
 package daysteps
 
 import (
-	"fmt"
-	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
 	"log"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
 )
 
 // Константы для расчетов дистанции
 const (
-	// Длина одного шага в метрах
-	stepLength = 0.65
 	// Количество метров в одном километре
 	mInKm = 1000
 )
 
 func parsePackage(data string) (int, time.Duration, error) {
-	delstr := strings.Split(data, ",")
-	if len(delstr) != 2 {
-		return 0, 0, fmt.Errorf("Ошибка: ожидалось 2 значения, получено %d", len(delstr))
+	pkg, err := parseTimedPackage(data, time.Time{})
+	if err != nil {
+		return 0, 0, err
 	}
+	return pkg.steps, pkg.duration, nil
+}
 
-	// Проверяем наличие пробелов в начале или конце чисел
-	trSteps := delstr[0]
-	trDuration := delstr[1]
+// packet - разобранный пакет "[время,]шаги,продолжительность"
+type packet struct {
+	start    time.Time
+	steps    int
+	duration time.Duration
+}
 
-	// Если есть пробелы в начале или конце шагов - возвращаем ошибку
-	if strings.HasPrefix(trSteps, " ") || strings.HasSuffix(trSteps, " ") {
-		return 0, 0, fmt.Errorf("Ошибка: пробелы в количестве шагов не допускаются")
+func parseTimedPackage(data string, date time.Time) (packet, error) {
+	delstr := strings.Split(data, ",")
+	if len(delstr) != 2 && len(delstr) != 3 {
+		return packet{}, spentcalories.NewParseError(FieldRecord, data, -1, ErrFieldCount, "reason.fields_day", len(delstr))
 	}
 
-	// Если есть пробелы в начале или конце продолжительности - возвращаем ошибку
-	if strings.HasPrefix(trDuration, " ") || strings.HasSuffix(trDuration, " ") {
-		return 0, 0, fmt.Errorf("Ошибка: пробелы в продолжительности не допускаются")
+	// Проверяем наличие пробелов в начале или конце значений
+	for i, v := range delstr {
+		if strings.TrimSpace(v) != v {
+			return packet{}, spaceError(len(delstr), i, v)
+		}
 	}
 
-	// Убираем знак + если есть
-	if strings.HasPrefix(trSteps, "+") {
-		trSteps = trSteps[1:]
+	var pkg packet
+
+	// Если значений три, первое из них - время начала
+	col := 0
+	if len(delstr) == 3 {
+		start, err := spentcalories.ParseTime(delstr[0], 0, date)
+		if err != nil {
+			return packet{}, err
+		}
+		pkg.start = start
+		col = 1
 	}
 
-	steps, err := strconv.Atoi(trSteps)
+	steps, err := spentcalories.ParseSteps(delstr[col], col)
 	if err != nil {
-		return 0, 0, fmt.Errorf("Ошибка при парсинге шагов: %v", err)
-	}
-	if steps <= 0 {
-		return 0, 0, fmt.Errorf("Ошибка: кол-во шагов должно быть положительное %d", steps)
+		return packet{}, err
 	}
 
-	duration, err := time.ParseDuration(trDuration)
+	duration, err := spentcalories.ParseDuration(delstr[col+1], col+1)
 	if err != nil {
-		return 0, 0, fmt.Errorf("Ошибка при парсинге продолжительности: %v", err)
+		return packet{}, err
 	}
-	if duration <= 0 {
-		return 0, 0, fmt.Errorf("Ошибка: продолжительность должна быть положительная, получено %s", duration)
+
+	pkg.steps = steps
+	pkg.duration = duration
+	return pkg, nil
+}
+
+// spaceError возвращает ошибку о пробелах для колонки column записи из count значений
+func spaceError(count, column int, value string) error {
+	switch {
+	case count == 3 && column == 0:
+		return spentcalories.NewParseError(FieldTime, value, column, ErrTime, "reason.time_space")
+	case column == count-1:
+		return spentcalories.NewParseError(FieldDuration, value, column, ErrDuration, "reason.duration_space")
+	default:
+		return spentcalories.NewParseError(FieldSteps, value, column, ErrSteps, "reason.steps_space")
 	}
-	return steps, duration, nil
 }
 
-func DayActionInfo(data string, weight, height float64) string {
+// DayActionResult содержит рассчитанные показатели одного пакета дневной активности
+type DayActionResult struct {
+	// Время начала, нулевое если в пакете его не было
+	Start    time.Time     `json:"start,omitzero"`
+	Steps    int           `json:"steps"`
+	Duration time.Duration `json:"duration"`
+	Distance float64       `json:"distance_km"`
+	Speed    float64       `json:"speed_kmh"`
+	Calories float64       `json:"calories"`
+}
+
+// String форматирует результат в виде текста, который возвращает DayActionInfo
+func (r DayActionResult) String() string {
+	return r.Format(i18n.Default, units.Metric)
+}
+
+// Format форматирует результат на языке loc, дистанция выводится в единицах sys
+func (r DayActionResult) Format(loc i18n.Locale, sys units.System) string {
+	return loc.T("day.result", r.Steps, loc.Count(r.Steps, "noun.steps"), sys.Distance(r.Distance), r.Calories,
+		loc.T(sys.DistanceKey()))
+}
+
+func DayAction(data string, weight, height float64, opts ...Option) (DayActionResult, error) {
+	o := newOptions(opts)
+
 	// Парсим данные о шагах и продолжительности
-	steps, duration, err := parsePackage(data)
+	pkg, err := parseTimedPackage(data, o.date)
 	if err != nil {
-		log.Printf("Err: %v", err)
-		return ""
+		return DayActionResult{}, err
 	}
+	return dayAction(pkg, weight, height, o)
+}
 
-	// Дополнительная проверка на положительное количество шагов
-	if steps <= 0 {
-		return ""
+// ComputeDay рассчитывает пакет по уже разобранным значениям так же, как DayAction.
+// Время начала может быть нулевым, шаги и продолжительность должны быть положительными
+func ComputeDay(start time.Time, steps int, duration time.Duration, weight, height float64, opts ...Option) (DayActionResult, error) {
+	switch {
+	case steps <= 0:
+		return DayActionResult{}, spentcalories.NewParseError(FieldSteps, strconv.Itoa(steps), -1, ErrSteps, "reason.steps_positive")
+	case duration <= 0:
+		return DayActionResult{}, spentcalories.NewParseError(FieldDuration, duration.String(), -1, ErrDuration, "reason.duration_positive")
 	}
+	return dayAction(packet{start: start, steps: steps, duration: duration}, weight, height, newOptions(opts))
+}
+
+// dayAction рассчитывает разобранный пакет. Продолжительность может быть нулевой
+// у мгновенных записей, которые передаются в Aggregator.AddSteps
+func dayAction(pkg packet, weight, height float64, o options) (DayActionResult, error) {
+	steps, duration := pkg.steps, pkg.duration
 
-	// Рассчитываем пройденную дистанцию в километрах
-	distance := float64(steps) * stepLength / mInKm
+	// Рассчитываем пройденную дистанцию в километрах. Без модели шаг считается
+	// равным средней длине stride.DefaultLength
+	var model stride.Model = stride.Fixed(stride.DefaultLength)
+	if o.stride != nil {
+		model = o.stride
+	}
+	length := model.Length(height)
+	distance := float64(steps) * length / mInKm
+
+	// С моделью калории считаются по той же длине шага, без нее - по росту
+	calorieHeight := height
+	if o.stride != nil {
+		calorieHeight = stride.EquivalentHeight(length)
+	}
+
+	// Калории ходьбы пропорциональны дистанции: скорость умножается на время.
+	// Поэтому для мгновенной записи подставляем любую положительную продолжительность
+	calorieDuration := duration
+	if calorieDuration == 0 {
+		calorieDuration = time.Minute
+	}
 
 	// Рассчитываем потраченные калории используя функцию из пакета spentcalories
-	calories, err := spentcalories.WalkingSpentCalories(steps, weight, height, duration)
+	calories, err := spentcalories.WalkingSpentCalories(steps, weight, calorieHeight, calorieDuration)
+	if err != nil {
+		return DayActionResult{}, err
+	}
+
+	var speed float64
+	if duration > 0 {
+		speed = distance / duration.Hours()
+	}
+	return DayActionResult{
+		Start:    pkg.start,
+		Steps:    steps,
+		Duration: duration,
+		Distance: distance,
+		Speed:    speed,
+		Calories: calories,
+	}, nil
+}
+
+func DayActionInfo(data string, weight, height float64) string {
+	// Вес и рост проверяются по тем же границам, что и в профиле
+	p := profile.Profile{Weight: weight, Height: height}
+	if err := p.Validate(); err != nil {
+		log.Printf("Err: %v", err)
+		return ""
+	}
+
+	result, err := DayAction(data, weight, height)
 	if err != nil {
 		log.Printf("Err: %v", err)
 		return ""
 	}
 
 	// Форматируем и возвращаем результат
-	return fmt.Sprintf("Количество шагов: %d.\nДистанция составила %.2f км.\nВы сожгли %.2f ккал.\n",
-		steps, distance, calories)
+	return result.String()
 }
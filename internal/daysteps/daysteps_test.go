@@ -7,6 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -327,6 +332,14 @@ func (suite *DayStepsTestSuite) TestDayActionInfo() {
 			want:          "",
 			wantLogOutput: true,
 		},
+		{
+			name:          "вес вне допустимого диапазона",
+			input:         "6000,1h00m",
+			weight:        8460,
+			height:        1.75,
+			want:          "",
+			wantLogOutput: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -346,3 +359,78 @@ func (suite *DayStepsTestSuite) TestDayActionInfo() {
 		})
 	}
 }
+
+func (suite *DayStepsTestSuite) TestDayAction() {
+	got, err := DayAction("6000,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 6000, got.Steps)
+	assert.Equal(suite.T(), time.Hour, got.Duration)
+	assert.InDelta(suite.T(), 3.9, got.Distance, 1e-9)
+	assert.InDelta(suite.T(), 3.9, got.Speed, 1e-9)
+	assert.InDelta(suite.T(), 177.19, got.Calories, 0.01)
+	assert.Equal(suite.T(), DayActionInfo("6000,1h00m", 75.0, 1.75), got.String())
+
+	_, err = DayAction("not valid", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DayStepsTestSuite) TestDayActionTimestamp() {
+	got, err := DayAction("2024-03-05T08:15:00+03:00,6000,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 5, 15, 0, 0, time.UTC), got.Start.UTC())
+	assert.Equal(suite.T(), 6000, got.Steps)
+
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	got, err = DayAction("12:40:00,3456,30m", 75.0, 1.75, WithDate(date))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 12, 40, 0, 0, time.UTC), got.Start)
+
+	// Время суток без даты не принимается
+	_, err = DayAction("12:40:00,3456,30m", 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, ErrTime)
+
+	var perr *ParseError
+	_, err = DayAction("12:40:00, 3456,30m", 75.0, 1.75, WithDate(date))
+	if assert.ErrorAs(suite.T(), err, &perr) {
+		assert.Equal(suite.T(), FieldSteps, perr.Field)
+		assert.Equal(suite.T(), 1, perr.Column)
+	}
+}
+
+func (suite *DayStepsTestSuite) TestDayActionStrideMatchesTraining() {
+	models := []stride.Model{
+		stride.Fixed(0.65),
+		stride.HeightBased(stride.HeightCoefficient),
+		stride.SexAdjusted{Sex: profile.Female},
+		stride.Calibrated(0.8),
+	}
+
+	for _, m := range models {
+		day, err := DayAction("6000,1h00m", 75.0, 1.75, WithStride(m))
+		assert.NoError(suite.T(), err)
+		training, err := spentcalories.Training("6000,Ходьба,1h00m", 75.0, 1.75, spentcalories.WithStride(m))
+		assert.NoError(suite.T(), err)
+
+		assert.InDelta(suite.T(), training.Distance, day.Distance, 1e-9)
+		assert.InDelta(suite.T(), training.Speed, day.Speed, 1e-9)
+		assert.InDelta(suite.T(), training.Calories, day.Calories, 1e-9)
+		assert.InDelta(suite.T(), 6*m.Length(1.75), day.Distance, 1e-9)
+	}
+}
+
+func (suite *DayStepsTestSuite) TestDayActionFormat() {
+	got, err := DayAction("6000,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "6000 steps.\nDistance: 3.90 km.\nBurned: 177.19 kcal.\n", got.Format(i18n.English, units.Metric))
+	assert.Equal(suite.T(), got.String(), got.Format(i18n.Russian, units.Metric))
+
+	got, err = DayAction("1,1m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), got.Format(i18n.English, units.Metric), "1 step.\n")
+}
+
+func (suite *DayStepsTestSuite) TestDayActionFormatImperial() {
+	got, err := DayAction("6000,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "6000 steps.\nDistance: 2.42 mi.\nBurned: 177.19 kcal.\n", got.Format(i18n.English, units.Imperial))
+}
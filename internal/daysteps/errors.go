@@ -0,0 +1,22 @@
+package daysteps
+
+import "github.com/Yandex-Practicum/tracker/internal/spentcalories"
+
+// Ошибки разбора общие с пакетом spentcalories, поэтому errors.Is и errors.As
+// работают одинаково для пакетов дневной активности и тренировок
+
+type ParseError = spentcalories.ParseError
+
+const (
+	FieldRecord   = spentcalories.FieldRecord
+	FieldTime     = spentcalories.FieldTime
+	FieldSteps    = spentcalories.FieldSteps
+	FieldDuration = spentcalories.FieldDuration
+)
+
+var (
+	ErrFieldCount = spentcalories.ErrFieldCount
+	ErrTime       = spentcalories.ErrTime
+	ErrSteps      = spentcalories.ErrSteps
+	ErrDuration   = spentcalories.ErrDuration
+)
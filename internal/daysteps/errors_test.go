@@ -0,0 +1,34 @@
+package daysteps
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *DayStepsTestSuite) TestParsePackageErrors() {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    error
+		wantField  string
+		wantColumn int
+	}{
+		{name: "одно значение", input: "678", wantErr: ErrFieldCount, wantField: FieldRecord, wantColumn: -1},
+		{name: "пробел в шагах", input: " 678,1h", wantErr: ErrSteps, wantField: FieldSteps, wantColumn: 0},
+		{name: "ноль шагов", input: "0,1h", wantErr: ErrSteps, wantField: FieldSteps, wantColumn: 0},
+		{name: "пробел в продолжительности", input: "678,1h ", wantErr: ErrDuration, wantField: FieldDuration, wantColumn: 1},
+		{name: "отрицательная продолжительность", input: "678,-1h", wantErr: ErrDuration, wantField: FieldDuration, wantColumn: 1},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, _, err := parsePackage(tt.input)
+			assert.ErrorIs(suite.T(), err, tt.wantErr)
+
+			var perr *ParseError
+			if assert.ErrorAs(suite.T(), err, &perr) {
+				assert.Equal(suite.T(), tt.wantField, perr.Field)
+				assert.Equal(suite.T(), tt.wantColumn, perr.Column)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package daysteps
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// Option настраивает обработку пакета в DayAction
+type Option func(*options)
+
+type options struct {
+	date   time.Time
+	stride stride.Model
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDate задает день, к которому относится время суток в пакете, например "12:40,678,50m"
+func WithDate(date time.Time) Option {
+	return func(o *options) {
+		o.date = date
+	}
+}
+
+// WithStride задает модель длины шага для дистанции и калорий.
+// Без нее дистанция считается по шагу stride.DefaultLength, а калории - по росту
+func WithStride(m stride.Model) Option {
+	return func(o *options) {
+		o.stride = m
+	}
+}
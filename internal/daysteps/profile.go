@@ -0,0 +1,26 @@
+package daysteps
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// DayActionFor рассчитывает пакет для пользователя с профилем p: дистанция и калории
+// считаются по модели шага из профиля. Профиль проверяется целиком, поэтому
+// недопустимый вес или рост дают ошибку
+func DayActionFor(data string, p profile.Profile, opts ...Option) (DayActionResult, error) {
+	if err := p.Validate(); err != nil {
+		return DayActionResult{}, err
+	}
+	return DayAction(data, p.Weight, p.Height, append([]Option{WithStride(stride.FromProfile(p))}, opts...)...)
+}
+
+// ComputeDayFor рассчитывает разобранный пакет для пользователя с профилем p, как DayActionFor
+func ComputeDayFor(start time.Time, steps int, duration time.Duration, p profile.Profile, opts ...Option) (DayActionResult, error) {
+	if err := p.Validate(); err != nil {
+		return DayActionResult{}, err
+	}
+	return ComputeDay(start, steps, duration, p.Weight, p.Height, append([]Option{WithStride(stride.FromProfile(p))}, opts...)...)
+}
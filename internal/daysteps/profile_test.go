@@ -0,0 +1,45 @@
+package daysteps
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *DayStepsTestSuite) TestDayActionFor() {
+	p := profile.Profile{Weight: 75.0, Height: 1.75, Stride: 0.8}
+
+	got, err := DayActionFor("6000,1h00m", p)
+	assert.NoError(suite.T(), err)
+	want, _ := DayAction("6000,1h00m", 75.0, 1.75, WithStride(stride.Calibrated(0.8)))
+	assert.Equal(suite.T(), want, got)
+	assert.InDelta(suite.T(), 4.8, got.Distance, 1e-9)
+
+	// Вес 8460 вместо 84.60 отклоняется проверкой профиля
+	_, err = DayActionFor("6000,1h00m", profile.Profile{Weight: 8460, Height: 1.75})
+	var verr *profile.ValidationError
+	assert.ErrorAs(suite.T(), err, &verr)
+	assert.Equal(suite.T(), profile.FieldWeight, verr.Field)
+}
+
+func (suite *DayStepsTestSuite) TestComputeDayFor() {
+	p := profile.Profile{Weight: 75.0, Height: 1.75, Stride: 0.8}
+	start := time.Date(2024, time.March, 5, 8, 0, 0, 0, time.UTC)
+
+	got, err := ComputeDayFor(start, 6000, time.Hour, p)
+	assert.NoError(suite.T(), err)
+	want, _ := DayActionFor("2024-03-05T08:00:00Z,6000,1h", p)
+	assert.Equal(suite.T(), want, got)
+
+	_, err = ComputeDayFor(start, 0, time.Hour, p)
+	var perr *ParseError
+	if assert.ErrorAs(suite.T(), err, &perr) {
+		assert.Equal(suite.T(), FieldSteps, perr.Field)
+	}
+	_, err = ComputeDayFor(start, 6000, 0, p)
+	if assert.ErrorAs(suite.T(), err, &perr) {
+		assert.Equal(suite.T(), FieldDuration, perr.Field)
+	}
+}
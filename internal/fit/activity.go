@@ -0,0 +1,388 @@
+package fit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Номера полей сообщений session и lap. Большинство совпадает, отличаются
+// только поля после total_calories
+const (
+	fieldStartTime     byte = 2
+	fieldElapsedTime   byte = 7
+	fieldTimerTime     byte = 8
+	fieldTotalDistance byte = 9
+	fieldTotalCycles   byte = 10
+	fieldTotalCalories byte = 11
+
+	fieldSessionSport     byte = 5
+	fieldSessionHeartRate byte = 16
+	fieldSessionMaxHR     byte = 17
+	fieldSessionCadence   byte = 18
+	fieldSessionAscent    byte = 22
+
+	fieldLapHeartRate byte = 15
+	fieldLapMaxHR     byte = 16
+	fieldLapCadence   byte = 17
+	fieldLapAscent    byte = 21
+	fieldLapSport     byte = 25
+)
+
+// Номера полей сообщения record
+const (
+	fieldRecordLat       byte = 0
+	fieldRecordLon       byte = 1
+	fieldRecordAltitude  byte = 2
+	fieldRecordHeartRate byte = 3
+	fieldRecordCadence   byte = 4
+	fieldRecordDistance  byte = 5
+	fieldRecordSpeed     byte = 6
+)
+
+// Масштабы значений из профиля FIT
+const (
+	scaleTime     = 1000 // total_timer_time в миллисекундах
+	scaleDistance = 100  // дистанция в сантиметрах
+	scaleSpeed    = 1000 // скорость в мм/с
+	scaleAltitude = 5    // высота в 1/5 м со смещением 500 м
+	offsetAlt     = 500
+	mInKm         = 1000
+	// Полукружности в градусы: 180 / 2^31
+	semicircles = 180.0 / (1 << 31)
+)
+
+// Sport - вид спорта из FIT
+type Sport byte
+
+const (
+	SportGeneric  Sport = 0
+	SportRunning  Sport = 1
+	SportCycling  Sport = 2
+	SportSwimming Sport = 5
+	SportWalking  Sport = 11
+	SportHiking   Sport = 17
+)
+
+// sportNames - английские названия, по которым активность ищется в реестре spentcalories
+var sportNames = map[Sport]string{
+	SportRunning:  "Running",
+	SportCycling:  "Cycling",
+	SportSwimming: "Swimming",
+	SportWalking:  "Walking",
+	SportHiking:   "Hiking",
+}
+
+func (s Sport) String() string {
+	if name, ok := sportNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("sport %d", byte(s))
+}
+
+// steps возвращает true для видов спорта, где циклы - это пары шагов
+func (s Sport) steps() bool {
+	return s == SportRunning || s == SportWalking || s == SportHiking
+}
+
+// Record - точка трека
+type Record struct {
+	Time time.Time
+	// Координаты в градусах, NaN если не записаны
+	Lat float64
+	Lon float64
+	// Высота в метрах
+	Altitude float64
+	// Пройденная от начала дистанция в метрах
+	Distance float64
+	// Скорость в км/ч
+	Speed     float64
+	HeartRate int
+	// Каденс как его записало устройство: в циклах (парах шагов или оборотах) в минуту
+	Cadence int
+}
+
+// Lap - круг или вся сессия тренировки
+type Lap struct {
+	Sport    Sport
+	Start    time.Time
+	Duration time.Duration
+	// Дистанция в километрах
+	Distance float64
+	// Количество шагов, 0 если не записано
+	Steps    int
+	Calories float64
+	// Набор высоты в метрах
+	Elevation    float64
+	HeartRate    float64
+	MaxHeartRate float64
+	// Средний каденс в шагах в минуту для бега и ходьбы
+	Cadence float64
+	// Циклы и каденс в циклах, как их записало устройство. Шаги из них
+	// получаются, только когда известен вид спорта
+	cycles, cycleCadence float64
+	// Окончание круга с учетом пауз. Duration - время таймера без пауз,
+	// поэтому Start+Duration раньше настоящего окончания, если была пауза
+	end time.Time
+}
+
+// End возвращает время окончания круга с учетом пауз
+func (l Lap) End() time.Time {
+	if l.end.IsZero() {
+		return l.Start.Add(l.Duration)
+	}
+	return l.end
+}
+
+// resolve пересчитывает циклы в шаги для бега и ходьбы, где цикл - это два шага
+func (l *Lap) resolve() {
+	if !l.Sport.steps() {
+		return
+	}
+	l.Steps = int(l.cycles) * 2
+	l.Cadence = l.cycleCadence * 2
+}
+
+// Session - тренировка из FIT-файла с кругами и точками трека
+type Session struct {
+	Lap
+	Laps    []Lap
+	Records []Record
+}
+
+// Sessions собирает тренировки из сообщений session, lap и record. Круги и
+// точки относятся к сессии по времени. Если сообщений session нет, тренировка
+// строится по кругам, а если нет и кругов - по точкам трека
+func (f *File) Sessions() ([]Session, error) {
+	var sessions []Session
+	var laps []Lap
+	var records []Record
+	for _, m := range f.Messages {
+		switch m.Global {
+		case MesgSession:
+			sessions = append(sessions, Session{Lap: lap(m, fieldSessionSport, fieldSessionHeartRate,
+				fieldSessionMaxHR, fieldSessionCadence, fieldSessionAscent)})
+		case MesgLap:
+			laps = append(laps, lap(m, fieldLapSport, fieldLapHeartRate,
+				fieldLapMaxHR, fieldLapCadence, fieldLapAscent))
+		case MesgRecord:
+			records = append(records, record(m))
+		}
+	}
+
+	if len(sessions) == 0 {
+		s, err := summarize(laps, records)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	for i := range sessions {
+		s := &sessions[i]
+		end := s.End()
+		for _, l := range laps {
+			if within(l.Start, s.Start, end) {
+				// Круг без вида спорта относится к виду спорта сессии
+				if l.Sport == SportGeneric {
+					l.Sport = s.Sport
+					l.resolve()
+				}
+				s.Laps = append(s.Laps, l)
+			}
+		}
+		for _, r := range records {
+			if within(r.Time, s.Start, end) {
+				s.Records = append(s.Records, r)
+			}
+		}
+	}
+	return sessions, nil
+}
+
+func within(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+// summarize строит сессию без сообщения session
+func summarize(laps []Lap, records []Record) (Session, error) {
+	var s Session
+	switch {
+	case len(laps) > 0:
+		s.Start = laps[0].Start
+		s.Sport = laps[0].Sport
+		var heartRate, minutes float64
+		for _, l := range laps {
+			if end := l.End(); end.After(s.end) {
+				s.end = end
+			}
+			s.Duration += l.Duration
+			s.Distance += l.Distance
+			s.Steps += l.Steps
+			s.Calories += l.Calories
+			s.Elevation += l.Elevation
+			s.MaxHeartRate = math.Max(s.MaxHeartRate, l.MaxHeartRate)
+			if l.HeartRate > 0 {
+				heartRate += l.HeartRate * l.Duration.Minutes()
+				minutes += l.Duration.Minutes()
+			}
+		}
+		if minutes > 0 {
+			s.HeartRate = heartRate / minutes
+		}
+	case len(records) > 0:
+		first, last := records[0], records[len(records)-1]
+		s.Start, s.end = first.Time, last.Time
+		s.Duration = last.Time.Sub(first.Time)
+		s.Distance = last.Distance / mInKm
+	default:
+		return Session{}, errors.New("в файле нет тренировок")
+	}
+	return s, nil
+}
+
+func lap(m Message, sport, heartRate, maxHR, cadence, ascent byte) Lap {
+	l := Lap{Start: m.Time}
+	if v, ok := m.Float(sport, 1, 0); ok {
+		l.Sport = Sport(v)
+	}
+	if v, ok := m.Float(fieldStartTime, 1, 0); ok {
+		l.Start = Time(uint32(v))
+	}
+	// Время таймера не учитывает паузы, а если его нет - берем полное время
+	elapsed, hasElapsed := m.Float(fieldElapsedTime, scaleTime, 0)
+	if v, ok := m.Float(fieldTimerTime, scaleTime, 0); ok {
+		l.Duration = time.Duration(math.Round(v * float64(time.Second)))
+	} else if hasElapsed {
+		l.Duration = time.Duration(math.Round(elapsed * float64(time.Second)))
+	}
+	// Окончание круга - по полному времени, а если его нет - по времени
+	// записи сообщения, которое устройство пишет в конце круга
+	switch {
+	case hasElapsed:
+		l.end = l.Start.Add(time.Duration(math.Round(elapsed * float64(time.Second))))
+	case m.Time.After(l.Start):
+		l.end = m.Time
+	}
+	if v, ok := m.Float(fieldTotalDistance, scaleDistance, 0); ok {
+		l.Distance = v / mInKm
+	}
+	if v, ok := m.Float(fieldTotalCalories, 1, 0); ok {
+		l.Calories = v
+	}
+	if v, ok := m.Float(heartRate, 1, 0); ok {
+		l.HeartRate = v
+	}
+	if v, ok := m.Float(maxHR, 1, 0); ok {
+		l.MaxHeartRate = v
+	}
+	if v, ok := m.Float(ascent, 1, 0); ok {
+		l.Elevation = v
+	}
+	if v, ok := m.Float(fieldTotalCycles, 1, 0); ok {
+		l.cycles = v
+	}
+	if v, ok := m.Float(cadence, 1, 0); ok {
+		l.cycleCadence = v
+	}
+	l.resolve()
+	return l
+}
+
+func record(m Message) Record {
+	r := Record{Time: m.Time, Lat: math.NaN(), Lon: math.NaN()}
+	if v, ok := m.Float(fieldRecordLat, 1, 0); ok {
+		r.Lat = v * semicircles
+	}
+	if v, ok := m.Float(fieldRecordLon, 1, 0); ok {
+		r.Lon = v * semicircles
+	}
+	if v, ok := m.Float(fieldRecordAltitude, scaleAltitude, offsetAlt); ok {
+		r.Altitude = v
+	}
+	if v, ok := m.Float(fieldRecordDistance, scaleDistance, 0); ok {
+		r.Distance = v
+	}
+	if v, ok := m.Float(fieldRecordSpeed, scaleSpeed, 0); ok {
+		r.Speed = v * 3600 / mInKm
+	}
+	if v, ok := m.Float(fieldRecordHeartRate, 1, 0); ok {
+		r.HeartRate = int(v)
+	}
+	if v, ok := m.Float(fieldRecordCadence, 1, 0); ok {
+		r.Cadence = int(v)
+	}
+	return r
+}
+
+// workout переводит круг в тренировку spentcalories
+func (l Lap) workout(activity string) spentcalories.Workout {
+	return spentcalories.Workout{
+		Start:     l.Start,
+		Activity:  activity,
+		Steps:     l.Steps,
+		Duration:  l.Duration,
+		Distance:  l.Distance,
+		Elevation: l.Elevation,
+		HeartRate: l.HeartRate,
+		Cadence:   l.Cadence,
+	}
+}
+
+// Workout переводит сессию в тренировку spentcalories, круги становятся отрезками.
+// Активность определяется по виду спорта, activity используется, только если
+// вид спорта не указан. Незарегистрированный вид спорта, например велосипед,
+// не подменяется ходьбой: Compute отклонит такую тренировку с ErrUnknownActivity
+func (s Session) Workout(activity string) spentcalories.Workout {
+	if s.Sport != SportGeneric {
+		activity = s.Sport.String()
+	}
+
+	w := s.Lap.workout(activity)
+	w.HeartRateSamples = heartRateSamples(s.Records, s.Start, s.End())
+	for _, l := range s.Laps {
+		if l.Duration > 0 {
+			seg := l.workout(activity)
+			seg.HeartRateSamples = heartRateSamples(s.Records, l.Start, l.End())
+			w.Segments = append(w.Segments, seg)
+		}
+	}
+	return w
+}
+
+// heartRateSamples возвращает измерения пульса из точек трека между start и end
+func heartRateSamples(records []Record, start, end time.Time) []spentcalories.HeartRateSample {
+	var samples []spentcalories.HeartRateSample
+	for _, r := range records {
+		if r.HeartRate > 0 && !r.Time.Before(start) && r.Time.Before(end) {
+			samples = append(samples, spentcalories.HeartRateSample{Time: r.Time, BPM: float64(r.HeartRate)})
+		}
+	}
+	return samples
+}
+
+// Import декодирует FIT-файл и рассчитывает каждую тренировку из него
+func Import(r io.Reader, activity string, weight, height float64, opts ...spentcalories.Option) ([]spentcalories.TrainingResult, error) {
+	file, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := file.Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]spentcalories.TrainingResult, 0, len(sessions))
+	for i, s := range sessions {
+		result, err := spentcalories.Compute(s.Workout(activity), weight, height, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("тренировка %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,145 @@
+package fit
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *FITTestSuite) TestSessions() {
+	file, err := Decode(bytes.NewReader(sampleFile()))
+	if !assert.NoError(suite.T(), err) {
+		return
+	}
+	sessions, err := file.Sessions()
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), sessions, 1) {
+		return
+	}
+
+	s := sessions[0]
+	assert.Equal(suite.T(), SportRunning, s.Sport)
+	assert.Equal(suite.T(), Time(start), s.Start)
+	assert.Equal(suite.T(), 30*time.Minute, s.Duration)
+	assert.InDelta(suite.T(), 5.0, s.Distance, 1e-9)
+	assert.Equal(suite.T(), 4800, s.Steps)
+	assert.Equal(suite.T(), 400.0, s.Calories)
+	assert.Equal(suite.T(), 35.0, s.Elevation)
+	assert.Len(suite.T(), s.Records, 3)
+
+	// Круги наследуют вид спорта сессии, поэтому циклы пересчитаны в шаги
+	if assert.Len(suite.T(), s.Laps, 2) {
+		assert.Equal(suite.T(), 2400, s.Laps[1].Steps)
+		assert.Equal(suite.T(), 160.0, s.Laps[1].HeartRate)
+		assert.Equal(suite.T(), Time(start+900), s.Laps[1].Start)
+	}
+}
+
+// pausedFile - ходьба с паузой 10 минут: таймер 20 минут, полное время 30 минут.
+// Второй круг и последняя точка начинаются после start+20m
+func pausedFile(elapsed bool) []byte {
+	e := newEncoder()
+	e.define(0, MesgRecord, []fieldDefinition{{FieldTimestamp, 4, byte(Uint32)}, {fieldRecordHeartRate, 1, byte(Uint8)}})
+	e.message(0, start, uint8(100))
+	e.message(0, start+600, uint8(110))
+	e.message(0, start+1700, uint8(120))
+
+	e.define(1, MesgLap, []fieldDefinition{
+		{FieldTimestamp, 4, byte(Uint32)}, {fieldStartTime, 4, byte(Uint32)}, {fieldTimerTime, 4, byte(Uint32)},
+	})
+	e.message(1, start+600, start, uint32(600000))
+	e.message(1, start+1800, start+1250, uint32(550000))
+
+	fields := []fieldDefinition{
+		{FieldTimestamp, 4, byte(Uint32)}, {fieldStartTime, 4, byte(Uint32)}, {fieldSessionSport, 1, byte(Enum)},
+		{fieldTimerTime, 4, byte(Uint32)}, {fieldTotalDistance, 4, byte(Uint32)},
+	}
+	values := []any{start + 1800, start, uint8(SportWalking), uint32(1200000), uint32(200000)}
+	if elapsed {
+		fields = append(fields, fieldDefinition{fieldElapsedTime, 4, byte(Uint32)})
+		values = append(values, uint32(1800000))
+	}
+	e.define(2, MesgSession, fields)
+	e.message(2, values...)
+	return e.bytes()
+}
+
+func (suite *FITTestSuite) TestPausedSession() {
+	for _, elapsed := range []bool{true, false} {
+		file, err := Decode(bytes.NewReader(pausedFile(elapsed)))
+		if !assert.NoError(suite.T(), err) {
+			return
+		}
+		sessions, err := file.Sessions()
+		assert.NoError(suite.T(), err)
+		if !assert.Len(suite.T(), sessions, 1) {
+			return
+		}
+
+		// Продолжительность - по таймеру, а круги и точки после паузы относятся к сессии
+		s := sessions[0]
+		assert.Equal(suite.T(), 20*time.Minute, s.Duration)
+		assert.Equal(suite.T(), Time(start+1800), s.End())
+		assert.Len(suite.T(), s.Laps, 2)
+		assert.Len(suite.T(), s.Records, 3)
+		assert.Len(suite.T(), s.Workout("Ходьба").HeartRateSamples, 3)
+	}
+}
+
+func (suite *FITTestSuite) TestImport() {
+	results, err := Import(bytes.NewReader(sampleFile()), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), results, 1) {
+		return
+	}
+
+	r := results[0]
+	assert.Equal(suite.T(), "Бег", r.Activity)
+	assert.Equal(suite.T(), 4800, r.Steps)
+	assert.InDelta(suite.T(), 5.0, r.Distance, 1e-9)
+	assert.InDelta(suite.T(), 10.0, r.Speed, 1e-9)
+	assert.Equal(suite.T(), 150.0, r.HeartRate)
+	// Калории считаются заново по скорости: 75 * 10 * 0.5
+	assert.InDelta(suite.T(), 375.0, r.Calories, 1e-9)
+	assert.Len(suite.T(), r.Segments, 2)
+}
+
+func (suite *FITTestSuite) TestImportRecordsOnly() {
+	e := newEncoder()
+	e.define(0, MesgRecord, []fieldDefinition{{FieldTimestamp, 4, byte(Uint32)}, {fieldRecordDistance, 4, byte(Uint32)}})
+	e.message(0, start, uint32(0))
+	e.message(0, start+1200, uint32(200000))
+
+	// Вид спорта неизвестен, поэтому используется активность по умолчанию
+	results, err := Import(bytes.NewReader(e.bytes()), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), results, 1) {
+		assert.Equal(suite.T(), "Ходьба", results[0].Activity)
+		assert.Equal(suite.T(), 20*time.Minute, results[0].Duration)
+		assert.InDelta(suite.T(), 2.0, results[0].Distance, 1e-9)
+	}
+
+	_, err = Import(bytes.NewReader(newEncoder().bytes()), "Ходьба", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	_, err = Import(bytes.NewReader(e.bytes()), "Парусный спорт", 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, spentcalories.ErrUnknownActivity)
+}
+
+func (suite *FITTestSuite) TestImportUnknownSport() {
+	for _, sport := range []Sport{SportCycling, SportSwimming, Sport(10)} {
+		e := newEncoder()
+		e.define(0, MesgSession, []fieldDefinition{
+			{FieldTimestamp, 4, byte(Uint32)}, {fieldStartTime, 4, byte(Uint32)}, {fieldSessionSport, 1, byte(Enum)},
+			{fieldTimerTime, 4, byte(Uint32)}, {fieldTotalDistance, 4, byte(Uint32)},
+		})
+		e.message(0, start+1800, start, uint8(sport), uint32(1800000), uint32(1000000))
+
+		// Велосипед и плавание не считаются ходьбой по умолчанию
+		results, err := Import(bytes.NewReader(e.bytes()), "Ходьба", 75.0, 1.75)
+		assert.ErrorIs(suite.T(), err, spentcalories.ErrUnknownActivity, sport.String())
+		assert.Empty(suite.T(), results)
+	}
+}
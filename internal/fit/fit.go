@@ -0,0 +1,543 @@
+package fit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Ошибки декодирования, проверяются через errors.Is
+var (
+	ErrHeader = errors.New("неверный заголовок FIT")
+	ErrFormat = errors.New("неверный формат записи FIT")
+	ErrCRC    = errors.New("неверная контрольная сумма FIT")
+)
+
+// Глобальные номера сообщений, которые используются при импорте
+const (
+	MesgFileID           uint16 = 0
+	MesgSession          uint16 = 18
+	MesgLap              uint16 = 19
+	MesgRecord           uint16 = 20
+	MesgDeveloperDataID  uint16 = 207
+	MesgFieldDescription uint16 = 206
+)
+
+// FieldTimestamp - номер поля времени, общий для всех сообщений
+const FieldTimestamp byte = 253
+
+// Биты заголовка записи
+const (
+	headerCompressed   = 0x80
+	headerDefinition   = 0x40
+	headerDeveloper    = 0x20
+	localMask          = 0x0F
+	compressedLocal    = 0x60
+	compressedOffset   = 0x1F
+	compressedRollover = 0x20
+)
+
+// Время FIT отсчитывается в секундах от 31.12.1989 00:00 UTC
+var epoch = time.Date(1989, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Time переводит время FIT во время Go
+func Time(seconds uint32) time.Time {
+	return epoch.Add(time.Duration(seconds) * time.Second)
+}
+
+// BaseType - базовый тип поля FIT
+type BaseType byte
+
+const (
+	Enum    BaseType = 0x00
+	Sint8   BaseType = 0x01
+	Uint8   BaseType = 0x02
+	Sint16  BaseType = 0x83
+	Uint16  BaseType = 0x84
+	Sint32  BaseType = 0x85
+	Uint32  BaseType = 0x86
+	String  BaseType = 0x07
+	Float32 BaseType = 0x88
+	Float64 BaseType = 0x89
+	Uint8z  BaseType = 0x0A
+	Uint16z BaseType = 0x8B
+	Uint32z BaseType = 0x8C
+	Byte    BaseType = 0x0D
+	Sint64  BaseType = 0x8E
+	Uint64  BaseType = 0x8F
+	Uint64z BaseType = 0x90
+)
+
+// Size возвращает размер одного значения типа в байтах
+func (t BaseType) Size() int {
+	switch t {
+	case Sint16, Uint16, Uint16z:
+		return 2
+	case Sint32, Uint32, Uint32z, Float32:
+		return 4
+	case Sint64, Uint64, Uint64z, Float64:
+		return 8
+	default:
+		return 1
+	}
+}
+
+func (t BaseType) signed() bool {
+	return t == Sint8 || t == Sint16 || t == Sint32 || t == Sint64
+}
+
+// zero - типы, у которых недопустимое значение 0, а не все единичные биты
+func (t BaseType) zero() bool {
+	return t == Uint8z || t == Uint16z || t == Uint32z || t == Uint64z
+}
+
+// Header - заголовок FIT-файла
+type Header struct {
+	Size     byte
+	Protocol byte
+	Profile  uint16
+	DataSize uint32
+}
+
+// Field - значение поля сообщения
+type Field struct {
+	Num  byte
+	Type BaseType
+	Data []byte
+	// Порядок байт из определения сообщения
+	order binary.ByteOrder
+}
+
+// raw возвращает первое значение поля как беззнаковое целое
+func (f Field) raw() (uint64, bool) {
+	size := f.Type.Size()
+	if len(f.Data) < size {
+		return 0, false
+	}
+	switch size {
+	case 1:
+		return uint64(f.Data[0]), true
+	case 2:
+		return uint64(f.order.Uint16(f.Data)), true
+	case 4:
+		return uint64(f.order.Uint32(f.Data)), true
+	default:
+		return f.order.Uint64(f.Data), true
+	}
+}
+
+// valid проверяет, что значение не является недопустимым значением типа
+func (f Field) valid(v uint64) bool {
+	// Максимальное значение без знака для размера типа, все биты единичные
+	ones := uint64(math.MaxUint64) >> (64 - 8*f.Type.Size())
+	switch {
+	case f.Type.zero():
+		return v != 0
+	case f.Type.signed():
+		return v != ones>>1
+	default:
+		return v != ones
+	}
+}
+
+// Float возвращает первое значение числового поля, false для недопустимого значения
+func (f Field) Float() (float64, bool) {
+	v, ok := f.raw()
+	if !ok || f.Type == String || f.Type == Byte {
+		return 0, false
+	}
+	switch f.Type {
+	case Float32:
+		x := math.Float32frombits(uint32(v))
+		return float64(x), v != math.MaxUint32 && !math.IsNaN(float64(x))
+	case Float64:
+		x := math.Float64frombits(v)
+		return x, v != math.MaxUint64 && !math.IsNaN(x)
+	}
+	if !f.valid(v) {
+		return 0, false
+	}
+	if f.Type.signed() {
+		shift := 64 - uint(f.Type.Size()*8)
+		return float64(int64(v<<shift) >> shift), true
+	}
+	return float64(v), true
+}
+
+// Uint возвращает первое значение целого поля, false для недопустимого значения
+func (f Field) Uint() (uint64, bool) {
+	v, ok := f.raw()
+	if !ok || f.Type == String || f.Type == Float32 || f.Type == Float64 || !f.valid(v) {
+		return 0, false
+	}
+	return v, true
+}
+
+// String возвращает значение строкового поля до первого нулевого байта
+func (f Field) String() string {
+	if i := bytes.IndexByte(f.Data, 0); i >= 0 {
+		return string(f.Data[:i])
+	}
+	return string(f.Data)
+}
+
+// DevField - поле разработчика, описанное сообщением field_description
+type DevField struct {
+	Field
+	// Индекс приложения-разработчика
+	DevIndex byte
+	Name     string
+	Units    string
+}
+
+// Message - сообщение с данными
+type Message struct {
+	Global uint16
+	Fields []Field
+	// Поля разработчика в порядке определения
+	Developer []DevField
+	// Время сообщения из поля timestamp или сжатого заголовка
+	Time time.Time
+}
+
+// Field возвращает поле с номером num
+func (m Message) Field(num byte) (Field, bool) {
+	for _, f := range m.Fields {
+		if f.Num == num {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Float возвращает значение числового поля num с учетом масштаба и смещения
+// из профиля FIT: value / scale - offset
+func (m Message) Float(num byte, scale, offset float64) (float64, bool) {
+	f, ok := m.Field(num)
+	if !ok {
+		return 0, false
+	}
+	v, ok := f.Float()
+	if !ok {
+		return 0, false
+	}
+	return v/scale - offset, true
+}
+
+// DevFieldByName возвращает поле разработчика по имени из его описания
+func (m Message) DevFieldByName(name string) (DevField, bool) {
+	for _, f := range m.Developer {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return DevField{}, false
+}
+
+// File - декодированный FIT-файл
+type File struct {
+	Header   Header
+	Messages []Message
+}
+
+// definition - определение локального типа сообщения
+type definition struct {
+	global    uint16
+	order     binary.ByteOrder
+	fields    []fieldDef
+	developer []fieldDef
+}
+
+type fieldDef struct {
+	num  byte
+	size byte
+	// Базовый тип, а для полей разработчика - индекс разработчика
+	typ byte
+}
+
+// devKey - поле разработчика определяется индексом разработчика и номером поля
+type devKey struct {
+	index, num byte
+}
+
+// devDesc - описание поля разработчика из сообщения field_description
+type devDesc struct {
+	typ         BaseType
+	name, units string
+}
+
+// decoder читает записи, одновременно считая контрольную сумму
+type decoder struct {
+	r           *bufio.Reader
+	crc         uint16
+	remain      uint32
+	defs        [localMask + 1]*definition
+	devs        map[devKey]devDesc
+	lastTime    uint32
+	hasLastTime bool
+}
+
+func (d *decoder) read(buf []byte) error {
+	if uint32(len(buf)) > d.remain {
+		return fmt.Errorf("%w: запись выходит за границу данных", ErrFormat)
+	}
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		return fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	d.remain -= uint32(len(buf))
+	d.crc = crc16(d.crc, buf)
+	return nil
+}
+
+func (d *decoder) readByte() (byte, error) {
+	var b [1]byte
+	err := d.read(b[:])
+	return b[0], err
+}
+
+// Decode читает FIT-файл целиком и проверяет контрольные суммы заголовка и данных
+func Decode(r io.Reader) (*File, error) {
+	d := &decoder{r: bufio.NewReader(r), devs: make(map[devKey]devDesc)}
+
+	header, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	file := &File{Header: header}
+
+	d.remain = header.DataSize
+	for d.remain > 0 {
+		msg, ok, err := d.record()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			file.Messages = append(file.Messages, msg)
+		}
+	}
+
+	var sum [2]byte
+	if _, err := io.ReadFull(d.r, sum[:]); err != nil {
+		return nil, fmt.Errorf("%w: нет контрольной суммы файла", ErrCRC)
+	}
+	if got := binary.LittleEndian.Uint16(sum[:]); got != d.crc {
+		return nil, fmt.Errorf("%w: ожидается %#04x, записано %#04x", ErrCRC, d.crc, got)
+	}
+	return file, nil
+}
+
+func (d *decoder) header() (Header, error) {
+	var size [1]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		return Header{}, fmt.Errorf("%w: %v", ErrHeader, err)
+	}
+	if size[0] != 12 && size[0] != 14 {
+		return Header{}, fmt.Errorf("%w: размер заголовка %d", ErrHeader, size[0])
+	}
+
+	buf := make([]byte, size[0])
+	buf[0] = size[0]
+	if _, err := io.ReadFull(d.r, buf[1:]); err != nil {
+		return Header{}, fmt.Errorf("%w: %v", ErrHeader, err)
+	}
+	if string(buf[8:12]) != ".FIT" {
+		return Header{}, fmt.Errorf("%w: нет сигнатуры .FIT", ErrHeader)
+	}
+
+	// Контрольная сумма заголовка необязательна, 0 означает, что она не посчитана
+	if size[0] == 14 {
+		want := binary.LittleEndian.Uint16(buf[12:])
+		if got := crc16(0, buf[:12]); want != 0 && want != got {
+			return Header{}, fmt.Errorf("%w: заголовок", ErrCRC)
+		}
+	}
+
+	// Контрольная сумма файла считается начиная с заголовка
+	d.crc = crc16(0, buf)
+	return Header{
+		Size:     size[0],
+		Protocol: buf[1],
+		Profile:  binary.LittleEndian.Uint16(buf[2:]),
+		DataSize: binary.LittleEndian.Uint32(buf[4:]),
+	}, nil
+}
+
+// record читает одну запись. Для записей определения ok равен false
+func (d *decoder) record() (Message, bool, error) {
+	h, err := d.readByte()
+	if err != nil {
+		return Message{}, false, err
+	}
+
+	switch {
+	case h&headerCompressed != 0:
+		return d.data(int(h&compressedLocal>>5), h&compressedOffset, true)
+	case h&headerDefinition != 0:
+		return Message{}, false, d.definition(int(h&localMask), h&headerDeveloper != 0)
+	default:
+		return d.data(int(h&localMask), 0, false)
+	}
+}
+
+func (d *decoder) definition(local int, developer bool) error {
+	var fixed [5]byte
+	if err := d.read(fixed[:]); err != nil {
+		return err
+	}
+
+	def := &definition{order: binary.LittleEndian}
+	switch fixed[1] {
+	case 0:
+	case 1:
+		def.order = binary.BigEndian
+	default:
+		return fmt.Errorf("%w: неизвестный порядок байт %d", ErrFormat, fixed[1])
+	}
+	def.global = def.order.Uint16(fixed[2:])
+
+	var err error
+	if def.fields, err = d.fieldDefs(int(fixed[4])); err != nil {
+		return err
+	}
+	if developer {
+		n, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		if def.developer, err = d.fieldDefs(int(n)); err != nil {
+			return err
+		}
+	}
+
+	d.defs[local] = def
+	return nil
+}
+
+func (d *decoder) fieldDefs(n int) ([]fieldDef, error) {
+	buf := make([]byte, 3*n)
+	if err := d.read(buf); err != nil {
+		return nil, err
+	}
+	defs := make([]fieldDef, n)
+	for i := range defs {
+		defs[i] = fieldDef{num: buf[3*i], size: buf[3*i+1], typ: buf[3*i+2]}
+	}
+	return defs, nil
+}
+
+func (d *decoder) data(local int, offset byte, compressed bool) (Message, bool, error) {
+	def := d.defs[local]
+	if def == nil {
+		return Message{}, false, fmt.Errorf("%w: нет определения для локального сообщения %d", ErrFormat, local)
+	}
+
+	msg := Message{Global: def.global}
+	for _, fd := range def.fields {
+		buf := make([]byte, fd.size)
+		if err := d.read(buf); err != nil {
+			return Message{}, false, err
+		}
+		msg.Fields = append(msg.Fields, Field{Num: fd.num, Type: BaseType(fd.typ), Data: buf, order: def.order})
+	}
+	for _, fd := range def.developer {
+		buf := make([]byte, fd.size)
+		if err := d.read(buf); err != nil {
+			return Message{}, false, err
+		}
+		// Без описания поле остается набором байт
+		desc, ok := d.devs[devKey{fd.typ, fd.num}]
+		if !ok {
+			desc.typ = Byte
+		}
+		msg.Developer = append(msg.Developer, DevField{
+			Field:    Field{Num: fd.num, Type: desc.typ, Data: buf, order: def.order},
+			DevIndex: fd.typ,
+			Name:     desc.name,
+			Units:    desc.units,
+		})
+	}
+
+	// Время берется из поля timestamp, а у сжатого заголовка - из последнего
+	// известного времени с заменой младших пяти бит
+	if f, ok := msg.Field(FieldTimestamp); ok {
+		if ts, ok := f.Uint(); ok {
+			d.lastTime, d.hasLastTime = uint32(ts), true
+		}
+		if d.hasLastTime {
+			msg.Time = Time(d.lastTime)
+		}
+	} else if compressed {
+		if !d.hasLastTime {
+			return Message{}, false, fmt.Errorf("%w: сжатое время без предыдущего timestamp", ErrFormat)
+		}
+		ts := d.lastTime&^compressedOffset | uint32(offset)
+		if uint32(offset) < d.lastTime&compressedOffset {
+			ts += compressedRollover
+		}
+		d.lastTime = ts
+		msg.Time = Time(ts)
+	}
+
+	if msg.Global == MesgFieldDescription {
+		d.describe(msg)
+	}
+	return msg, true, nil
+}
+
+// Номера полей сообщения field_description
+const (
+	fieldDevIndex  byte = 0
+	fieldDevNum    byte = 1
+	fieldDevType   byte = 2
+	fieldDevName   byte = 3
+	fieldDevUnits  byte = 8
+	fieldDevUnused byte = 0xFF
+)
+
+// describe запоминает описание поля разработчика для следующих сообщений
+func (d *decoder) describe(msg Message) {
+	index, num, typ := fieldDevUnused, fieldDevUnused, byte(Byte)
+	var desc devDesc
+	for _, f := range msg.Fields {
+		v, _ := f.Uint()
+		switch f.Num {
+		case fieldDevIndex:
+			index = byte(v)
+		case fieldDevNum:
+			num = byte(v)
+		case fieldDevType:
+			typ = byte(v)
+		case fieldDevName:
+			desc.name = f.String()
+		case fieldDevUnits:
+			desc.units = f.String()
+		}
+	}
+	desc.typ = BaseType(typ)
+	d.devs[devKey{index, num}] = desc
+}
+
+// Таблица для контрольной суммы FIT (CRC-16, по четыре бита за шаг)
+var crcTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// crc16 продолжает контрольную сумму crc по байтам data
+func crc16(crc uint16, data []byte) uint16 {
+	for _, b := range data {
+		tmp := crcTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ crcTable[b&0xF]
+
+		tmp = crcTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ crcTable[(b>>4)&0xF]
+	}
+	return crc
+}
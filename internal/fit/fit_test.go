@@ -0,0 +1,238 @@
+package fit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FITTestSuite struct {
+	suite.Suite
+}
+
+func TestFITSuite(t *testing.T) {
+	suite.Run(t, new(FITTestSuite))
+}
+
+// Начало тестовой тренировки в секундах FIT
+var start = uint32(time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC).Sub(epoch) / time.Second)
+
+// encoder собирает FIT-файл для тестов
+type encoder struct {
+	data  bytes.Buffer
+	order interface {
+		binary.ByteOrder
+		binary.AppendByteOrder
+	}
+}
+
+type fieldDefinition struct {
+	num, size, typ byte
+}
+
+func newEncoder() *encoder {
+	return &encoder{order: binary.LittleEndian}
+}
+
+func (e *encoder) define(local byte, global uint16, fields []fieldDefinition, developer ...fieldDefinition) {
+	header := headerDefinition | local
+	if len(developer) > 0 {
+		header |= headerDeveloper
+	}
+	arch := byte(0)
+	if e.order == binary.BigEndian {
+		arch = 1
+	}
+	e.data.WriteByte(header)
+	e.data.Write([]byte{0, arch})
+	e.data.Write(e.order.AppendUint16(nil, global))
+	e.data.WriteByte(byte(len(fields)))
+	for _, f := range fields {
+		e.data.Write([]byte{f.num, f.size, f.typ})
+	}
+	if len(developer) > 0 {
+		e.data.WriteByte(byte(len(developer)))
+		for _, f := range developer {
+			e.data.Write([]byte{f.num, f.size, f.typ})
+		}
+	}
+}
+
+func (e *encoder) message(header byte, values ...any) {
+	e.data.WriteByte(header)
+	for _, v := range values {
+		switch v := v.(type) {
+		case uint8:
+			e.data.WriteByte(v)
+		case uint16:
+			e.data.Write(e.order.AppendUint16(nil, v))
+		case uint32:
+			e.data.Write(e.order.AppendUint32(nil, v))
+		case int32:
+			e.data.Write(e.order.AppendUint32(nil, uint32(v)))
+		case string:
+			e.data.WriteString(v)
+		}
+	}
+}
+
+func (e *encoder) bytes() []byte {
+	header := []byte{14, 0x20}
+	header = binary.LittleEndian.AppendUint16(header, 2132)
+	header = binary.LittleEndian.AppendUint32(header, uint32(e.data.Len()))
+	header = append(header, ".FIT"...)
+	header = binary.LittleEndian.AppendUint16(header, crc16(0, header))
+
+	file := append(header, e.data.Bytes()...)
+	return binary.LittleEndian.AppendUint16(file, crc16(0, file))
+}
+
+// sampleFile - пробежка с двумя кругами, точками трека и полем разработчика
+func sampleFile() []byte {
+	e := newEncoder()
+
+	// Описание поля разработчика: индекс 0, поле 0, uint16, "power", единицы "W"
+	e.define(0, MesgFieldDescription, []fieldDefinition{
+		{fieldDevIndex, 1, byte(Uint8)}, {fieldDevNum, 1, byte(Uint8)}, {fieldDevType, 1, byte(Uint8)},
+		{fieldDevName, 6, byte(String)}, {fieldDevUnits, 2, byte(String)},
+	})
+	e.message(0, uint8(0), uint8(0), uint8(Uint16), "power\x00", "W\x00")
+
+	// Точки трека: время, пульс, дистанция и мощность из поля разработчика
+	e.define(1, MesgRecord, []fieldDefinition{
+		{FieldTimestamp, 4, byte(Uint32)}, {fieldRecordHeartRate, 1, byte(Uint8)}, {fieldRecordDistance, 4, byte(Uint32)},
+	}, fieldDefinition{0, 2, 0})
+	e.message(1, start, uint8(130), uint32(0), uint16(250))
+	e.message(1, start+900, uint8(0xFF), uint32(250000), uint16(260))
+	// Сжатый заголовок: локальное сообщение 2, через 10 с после предыдущей точки
+	e.define(2, MesgRecord, []fieldDefinition{{fieldRecordHeartRate, 1, byte(Uint8)}})
+	offset := byte((start + 910) & compressedOffset)
+	e.message(headerCompressed|2<<5|offset, uint8(150))
+
+	// Круги без вида спорта, он берется из сессии
+	e.define(3, MesgLap, []fieldDefinition{
+		{FieldTimestamp, 4, byte(Uint32)}, {fieldStartTime, 4, byte(Uint32)}, {fieldTimerTime, 4, byte(Uint32)},
+		{fieldTotalDistance, 4, byte(Uint32)}, {fieldTotalCycles, 4, byte(Uint32)}, {fieldLapHeartRate, 1, byte(Uint8)},
+	})
+	e.message(3, start+900, start, uint32(900000), uint32(250000), uint32(1200), uint8(140))
+	e.message(3, start+1800, start+900, uint32(900000), uint32(250000), uint32(1200), uint8(160))
+
+	// Сессия записана в обратном порядке байт
+	e.order = binary.BigEndian
+	e.define(4, MesgSession, []fieldDefinition{
+		{FieldTimestamp, 4, byte(Uint32)}, {fieldStartTime, 4, byte(Uint32)}, {fieldSessionSport, 1, byte(Enum)},
+		{fieldTimerTime, 4, byte(Uint32)}, {fieldTotalDistance, 4, byte(Uint32)}, {fieldTotalCycles, 4, byte(Uint32)},
+		{fieldSessionHeartRate, 1, byte(Uint8)}, {fieldSessionAscent, 2, byte(Uint16)}, {fieldTotalCalories, 2, byte(Uint16)},
+	})
+	e.message(4, start+1800, start, uint8(SportRunning), uint32(1800000), uint32(500000), uint32(2400), uint8(150), uint16(35), uint16(400))
+	return e.bytes()
+}
+
+func (suite *FITTestSuite) TestCRC() {
+	// Контрольное значение CRC-16/ARC
+	assert.Equal(suite.T(), uint16(0xBB3D), crc16(0, []byte("123456789")))
+	// Расчет по частям дает тот же результат
+	assert.Equal(suite.T(), uint16(0xBB3D), crc16(crc16(0, []byte("1234")), []byte("56789")))
+}
+
+func (suite *FITTestSuite) TestDecode() {
+	file, err := Decode(bytes.NewReader(sampleFile()))
+	if !assert.NoError(suite.T(), err) {
+		return
+	}
+	assert.Equal(suite.T(), byte(14), file.Header.Size)
+	assert.Equal(suite.T(), uint16(2132), file.Header.Profile)
+	if !assert.Len(suite.T(), file.Messages, 7) {
+		return
+	}
+
+	first := file.Messages[1]
+	assert.Equal(suite.T(), MesgRecord, first.Global)
+	assert.Equal(suite.T(), Time(start), first.Time)
+	if assert.Len(suite.T(), first.Developer, 1) {
+		power := first.Developer[0]
+		assert.Equal(suite.T(), "power", power.Name)
+		assert.Equal(suite.T(), "W", power.Units)
+		v, ok := power.Uint()
+		assert.True(suite.T(), ok)
+		assert.Equal(suite.T(), uint64(250), v)
+	}
+	_, ok := first.DevFieldByName("cadence")
+	assert.False(suite.T(), ok)
+
+	// 0xFF - недопустимое значение uint8, пульс не записан
+	_, ok = file.Messages[2].Float(fieldRecordHeartRate, 1, 0)
+	assert.False(suite.T(), ok)
+	distance, ok := file.Messages[2].Float(fieldRecordDistance, scaleDistance, 0)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), 2500.0, distance)
+
+	// Время сжатого заголовка восстанавливается по предыдущему timestamp
+	assert.Equal(suite.T(), Time(start+910), file.Messages[3].Time)
+
+	session := file.Messages[6]
+	assert.Equal(suite.T(), MesgSession, session.Global)
+	cycles, ok := session.Float(fieldTotalCycles, 1, 0)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), 2400.0, cycles)
+}
+
+func (suite *FITTestSuite) TestFieldTypes() {
+	le := binary.LittleEndian
+	tests := []struct {
+		name  string
+		field Field
+		want  float64
+		ok    bool
+	}{
+		{"sint16", Field{Type: Sint16, Data: le.AppendUint16(nil, 0xFFFE), order: le}, -2, true},
+		{"sint16 недопустимое", Field{Type: Sint16, Data: le.AppendUint16(nil, 0x7FFF), order: le}, 0, false},
+		{"uint32z ноль", Field{Type: Uint32z, Data: le.AppendUint32(nil, 0), order: le}, 0, false},
+		{"uint64", Field{Type: Uint64, Data: le.AppendUint64(nil, 42), order: le}, 42, true},
+		{"float32", Field{Type: Float32, Data: le.AppendUint32(nil, 0x3FC00000), order: le}, 1.5, true},
+		{"короткое поле", Field{Type: Uint32, Data: []byte{1}, order: le}, 0, false},
+		{"строка", Field{Type: String, Data: []byte("ab\x00"), order: le}, 0, false},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, ok := tt.field.Float()
+			assert.Equal(suite.T(), tt.ok, ok)
+			assert.Equal(suite.T(), tt.want, got)
+		})
+	}
+}
+
+func (suite *FITTestSuite) TestErrors() {
+	data := sampleFile()
+
+	broken := append([]byte(nil), data...)
+	broken[40] ^= 0xFF
+	_, err := Decode(bytes.NewReader(broken))
+	assert.ErrorIs(suite.T(), err, ErrCRC)
+
+	header := append([]byte(nil), data...)
+	header[12] ^= 0xFF
+	_, err = Decode(bytes.NewReader(header))
+	assert.ErrorIs(suite.T(), err, ErrCRC)
+
+	signature := append([]byte(nil), data...)
+	copy(signature[8:], "FIT.")
+	_, err = Decode(bytes.NewReader(signature))
+	assert.ErrorIs(suite.T(), err, ErrHeader)
+
+	_, err = Decode(bytes.NewReader(data[:len(data)-20]))
+	assert.ErrorIs(suite.T(), err, ErrFormat)
+
+	_, err = Decode(bytes.NewReader(nil))
+	assert.ErrorIs(suite.T(), err, ErrHeader)
+
+	// Данные без определения локального сообщения
+	e := newEncoder()
+	e.message(5, uint8(1))
+	_, err = Decode(bytes.NewReader(e.bytes()))
+	assert.ErrorIs(suite.T(), err, ErrFormat)
+}
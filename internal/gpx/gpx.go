@@ -0,0 +1,211 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Константы для расчета расстояний
+const (
+	// Средний радиус Земли в метрах
+	earthRadius = 6371008.8
+	// Количество метров в одном километре
+	mInKm = 1000
+)
+
+// Point - точка трека
+type Point struct {
+	Lat  float64
+	Lon  float64
+	Ele  float64
+	Time time.Time
+	// Пульс из расширения Garmin TrackPointExtension, 0 если не записан
+	HeartRate float64
+}
+
+// Track - трек из GPX-файла. Сегменты хранятся отдельно, потому что
+// расстояние между концом одного сегмента и началом следующего не проходилось
+type Track struct {
+	Name     string
+	Type     string
+	Segments [][]Point
+}
+
+// Summary - показатели трека
+type Summary struct {
+	Start    time.Time
+	Duration time.Duration
+	// Дистанция в километрах
+	Distance float64
+	// Набор высоты в метрах
+	Elevation float64
+	// Средняя скорость в км/ч
+	Speed float64
+}
+
+// Структура GPX 1.1 в объеме, нужном для импорта
+type gpxFile struct {
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Lat  float64 `xml:"lat,attr"`
+				Lon  float64 `xml:"lon,attr"`
+				Ele  float64 `xml:"ele"`
+				Time string  `xml:"time"`
+				HR   float64 `xml:"extensions>TrackPointExtension>hr"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// Parse читает все треки из GPX-файла
+func Parse(r io.Reader) ([]Track, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать GPX: %w", err)
+	}
+
+	tracks := make([]Track, 0, len(file.Tracks))
+	for i, trk := range file.Tracks {
+		track := Track{Name: trk.Name, Type: trk.Type}
+		for _, seg := range trk.Segments {
+			points := make([]Point, 0, len(seg.Points))
+			for j, pt := range seg.Points {
+				var t time.Time
+				if pt.Time != "" {
+					var err error
+					if t, err = time.Parse(time.RFC3339, pt.Time); err != nil {
+						return nil, fmt.Errorf("трек %d, точка %d: неверное время %q", i+1, j+1, pt.Time)
+					}
+				}
+				points = append(points, Point{Lat: pt.Lat, Lon: pt.Lon, Ele: pt.Ele, Time: t, HeartRate: pt.HR})
+			}
+			if len(points) > 0 {
+				track.Segments = append(track.Segments, points)
+			}
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
+
+// Summary рассчитывает дистанцию, набор высоты, продолжительность и среднюю скорость трека.
+// Продолжительность складывается из продолжительностей сегментов: паузы между
+// сегментами, например при остановке записи, в нее не входят
+func (t Track) Summary() (Summary, error) {
+	var s Summary
+
+	for _, seg := range t.Segments {
+		var first, last time.Time
+		for i, p := range seg {
+			if p.Time.IsZero() {
+				return Summary{}, errors.New("в треке есть точки без времени")
+			}
+			if first.IsZero() || p.Time.Before(first) {
+				first = p.Time
+			}
+			if p.Time.After(last) {
+				last = p.Time
+			}
+			if i == 0 {
+				continue
+			}
+			prev := seg[i-1]
+			s.Distance += haversine(prev, p) / mInKm
+			if gain := p.Ele - prev.Ele; gain > 0 {
+				s.Elevation += gain
+			}
+		}
+		if first.IsZero() {
+			continue
+		}
+		if s.Start.IsZero() || first.Before(s.Start) {
+			s.Start = first
+		}
+		s.Duration += last.Sub(first)
+	}
+
+	if s.Start.IsZero() {
+		return Summary{}, errors.New("трек не содержит точек")
+	}
+	if s.Duration <= 0 {
+		return Summary{}, errors.New("продолжительность трека должна быть положительной")
+	}
+	s.Speed = s.Distance / s.Duration.Hours()
+	return s, nil
+}
+
+// Workout переводит трек в тренировку spentcalories. Активность берется из
+// элемента type трека, а если его нет - используется activity. Незарегистрированный
+// type не подменяется активностью по умолчанию: Compute отклонит такую тренировку
+// с ErrUnknownActivity. Шаги в GPX не записываются, поэтому Compute оценивает их
+// по модели шага
+func (t Track) Workout(activity string) (spentcalories.Workout, error) {
+	s, err := t.Summary()
+	if err != nil {
+		return spentcalories.Workout{}, err
+	}
+
+	if t.Type != "" {
+		activity = t.Type
+	}
+
+	var samples []spentcalories.HeartRateSample
+	for _, seg := range t.Segments {
+		for _, p := range seg {
+			if p.HeartRate > 0 {
+				samples = append(samples, spentcalories.HeartRateSample{Time: p.Time, BPM: p.HeartRate})
+			}
+		}
+	}
+
+	return spentcalories.Workout{
+		Start:            s.Start,
+		Activity:         activity,
+		Duration:         s.Duration,
+		Distance:         s.Distance,
+		Elevation:        s.Elevation,
+		HeartRateSamples: samples,
+	}, nil
+}
+
+// Import читает GPX-файл и рассчитывает тренировку для каждого трека
+func Import(r io.Reader, activity string, weight, height float64, opts ...spentcalories.Option) ([]spentcalories.TrainingResult, error) {
+	tracks, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]spentcalories.TrainingResult, 0, len(tracks))
+	for i, t := range tracks {
+		w, err := t.Workout(activity)
+		if err != nil {
+			return nil, fmt.Errorf("трек %d: %w", i+1, err)
+		}
+		result, err := spentcalories.Compute(w, weight, height, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("трек %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// haversine возвращает расстояние между точками по поверхности Земли в метрах
+func haversine(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,127 @@
+package gpx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// Точки идут по меридиану с шагом 0.01 градуса, это 1111.95 м
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk>
+    <name>Утренняя пробежка</name>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="55.00" lon="37.0"><ele>100</ele><time>2024-03-05T07:00:00Z</time></trkpt>
+      <trkpt lat="55.01" lon="37.0"><ele>105</ele><time>2024-03-05T07:06:40Z</time></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="55.01" lon="37.0"><ele>103</ele><time>2024-03-05T07:06:40Z</time></trkpt>
+      <trkpt lat="55.02" lon="37.0"><ele>103</ele><time>2024-03-05T07:13:20Z</time></trkpt>
+      <trkpt lat="55.03" lon="37.0"><ele>110</ele><time>2024-03-05T07:20:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+type GPXTestSuite struct {
+	suite.Suite
+}
+
+func TestGPXSuite(t *testing.T) {
+	suite.Run(t, new(GPXTestSuite))
+}
+
+func (suite *GPXTestSuite) TestSummary() {
+	tracks, err := Parse(strings.NewReader(sample))
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), tracks, 1) {
+		return
+	}
+	assert.Equal(suite.T(), "Утренняя пробежка", tracks[0].Name)
+	assert.Len(suite.T(), tracks[0].Segments, 2)
+
+	s, err := tracks[0].Summary()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC), s.Start)
+	assert.Equal(suite.T(), 20*time.Minute, s.Duration)
+	assert.InDelta(suite.T(), 3.33585, s.Distance, 1e-4)
+	assert.InDelta(suite.T(), 12, s.Elevation, 1e-9)
+	assert.InDelta(suite.T(), 10.0076, s.Speed, 1e-3)
+}
+
+func (suite *GPXTestSuite) TestSummarySkipsPause() {
+	// Второй сегмент начат после 10 минут паузы: она не входит в продолжительность
+	paused := strings.Replace(sample, `<trkpt lat="55.01" lon="37.0"><ele>103</ele><time>2024-03-05T07:06:40Z</time></trkpt>
+      <trkpt lat="55.02" lon="37.0"><ele>103</ele><time>2024-03-05T07:13:20Z</time></trkpt>
+      <trkpt lat="55.03" lon="37.0"><ele>110</ele><time>2024-03-05T07:20:00Z</time></trkpt>`,
+		`<trkpt lat="55.01" lon="37.0"><ele>103</ele><time>2024-03-05T07:16:40Z</time></trkpt>
+      <trkpt lat="55.02" lon="37.0"><ele>103</ele><time>2024-03-05T07:23:20Z</time></trkpt>
+      <trkpt lat="55.03" lon="37.0"><ele>110</ele><time>2024-03-05T07:30:00Z</time></trkpt>`, 1)
+	tracks, err := Parse(strings.NewReader(paused))
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), tracks, 1) {
+		return
+	}
+
+	s, err := tracks[0].Summary()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC), s.Start)
+	assert.Equal(suite.T(), 20*time.Minute, s.Duration)
+	// Скорость та же, что и без паузы
+	assert.InDelta(suite.T(), 10.0076, s.Speed, 1e-3)
+}
+
+func (suite *GPXTestSuite) TestImport() {
+	results, err := Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75, spentcalories.WithStride(stride.Calibrated(1.0)))
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), results, 1) {
+		return
+	}
+
+	r := results[0]
+	// Тип трека running найден в реестре, поэтому активность по умолчанию не используется
+	assert.Equal(suite.T(), "Бег", r.Activity)
+	assert.InDelta(suite.T(), 3.33585, r.Distance, 1e-4)
+	assert.InDelta(suite.T(), 10.0076, r.Speed, 1e-3)
+	// Шаги оценены по длине шага 1 м
+	assert.Equal(suite.T(), 3336, r.Steps)
+	assert.InDelta(suite.T(), 12, r.Elevation, 1e-9)
+	// Калории по скорости: вес * скорость * часы
+	assert.InDelta(suite.T(), 75.0*10.0076/3, r.Calories, 0.05)
+}
+
+func (suite *GPXTestSuite) TestErrors() {
+	_, err := Import(strings.NewReader("<gpx"), "Бег", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	noTime := `<gpx><trk><trkseg><trkpt lat="1" lon="1"/><trkpt lat="1.1" lon="1"/></trkseg></trk></gpx>`
+	_, err = Import(strings.NewReader(noTime), "Бег", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	empty := `<gpx><trk><name>пусто</name></trk></gpx>`
+	_, err = Import(strings.NewReader(empty), "Бег", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	// Незарегистрированный вид спорта из трека не считается активностью по умолчанию
+	unknown := strings.Replace(sample, "<type>running</type>", "<type>sailing</type>", 1)
+	results, err := Import(strings.NewReader(unknown), "Ходьба", 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, spentcalories.ErrUnknownActivity)
+	assert.Empty(suite.T(), results)
+
+	// Без type используется активность по умолчанию
+	untyped := strings.Replace(sample, "<type>running</type>", "", 1)
+	results, err = Import(strings.NewReader(untyped), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), results, 1) {
+		assert.Equal(suite.T(), "Ходьба", results[0].Activity)
+	}
+
+	_, err = Import(strings.NewReader(untyped), "Парусный спорт", 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, spentcalories.ErrUnknownActivity)
+}
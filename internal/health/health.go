@@ -0,0 +1,314 @@
+package health
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Тип записей с количеством шагов в export.xml
+const StepCountType = "HKQuantityTypeIdentifierStepCount"
+
+// Формат дат в export.xml, например "2024-03-05 07:00:00 +0300"
+const dateLayout = "2006-01-02 15:04:05 -0700"
+
+// Константы для пересчета единиц
+const (
+	mInKm     = 1000
+	kmInMile  = 1.609344
+	prefixHK  = "HKWorkoutActivityType"
+	statsDist = "HKQuantityTypeIdentifierDistance"
+)
+
+// StepSample - запись о количестве шагов за интервал
+type StepSample struct {
+	Source string
+	Start  time.Time
+	End    time.Time
+	Steps  int
+}
+
+// Workout - тренировка из export.xml
+type Workout struct {
+	Source string
+	// Вид тренировки без префикса HKWorkoutActivityType, например Running
+	Type     string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	// Дистанция в километрах, 0 если не записана
+	Distance float64
+}
+
+// Handler получает записи по мере чтения файла. Nil-функции пропускают
+// соответствующие записи, ошибка из функции прерывает чтение
+type Handler struct {
+	Steps   func(StepSample) error
+	Workout func(Workout) error
+}
+
+// xmlWorkout - элемент Workout. Старые выгрузки хранят дистанцию в атрибутах,
+// новые - в дочерних элементах WorkoutStatistics
+type xmlWorkout struct {
+	Source            string  `xml:"sourceName,attr"`
+	Type              string  `xml:"workoutActivityType,attr"`
+	Duration          float64 `xml:"duration,attr"`
+	DurationUnit      string  `xml:"durationUnit,attr"`
+	TotalDistance     float64 `xml:"totalDistance,attr"`
+	TotalDistanceUnit string  `xml:"totalDistanceUnit,attr"`
+	Start             string  `xml:"startDate,attr"`
+	End               string  `xml:"endDate,attr"`
+	Statistics        []struct {
+		Type string  `xml:"type,attr"`
+		Sum  float64 `xml:"sum,attr"`
+		Unit string  `xml:"unit,attr"`
+	} `xml:"WorkoutStatistics"`
+}
+
+// Scan читает export.xml потоком и передает записи о шагах и тренировки в h.
+// В памяти одновременно находится только один элемент, поэтому размер файла не ограничен
+func Scan(r io.Reader, h Handler) error {
+	d := xml.NewDecoder(r)
+	root := false
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			if !root {
+				return errors.New("файл не содержит данных Apple Health")
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("не удалось разобрать export.xml: %w", err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "HealthData":
+			root = true
+		case "Record":
+			if err := scanRecord(d, se, h); err != nil {
+				return err
+			}
+		case "Workout":
+			if err := scanWorkout(d, se, h); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func scanRecord(d *xml.Decoder, se xml.StartElement, h Handler) error {
+	// Вложенные MetadataEntry не нужны
+	if err := d.Skip(); err != nil {
+		return fmt.Errorf("не удалось разобрать export.xml: %w", err)
+	}
+	if h.Steps == nil || attr(se, "type") != StepCountType {
+		return nil
+	}
+
+	start, err := parseDate(attr(se, "startDate"))
+	if err != nil {
+		return err
+	}
+	end, err := parseDate(attr(se, "endDate"))
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseFloat(attr(se, "value"), 64)
+	if err != nil {
+		return fmt.Errorf("неверное количество шагов %q", attr(se, "value"))
+	}
+
+	return h.Steps(StepSample{
+		Source: attr(se, "sourceName"),
+		Start:  start,
+		End:    end,
+		Steps:  int(math.Round(value)),
+	})
+}
+
+func scanWorkout(d *xml.Decoder, se xml.StartElement, h Handler) error {
+	if h.Workout == nil {
+		return d.Skip()
+	}
+
+	var x xmlWorkout
+	if err := d.DecodeElement(&x, &se); err != nil {
+		return fmt.Errorf("не удалось разобрать export.xml: %w", err)
+	}
+
+	w := Workout{Source: x.Source, Type: strings.TrimPrefix(x.Type, prefixHK)}
+	var err error
+	if w.Start, err = parseDate(x.Start); err != nil {
+		return err
+	}
+	if w.End, err = parseDate(x.End); err != nil {
+		return err
+	}
+
+	w.Duration = w.End.Sub(w.Start)
+	if x.Duration > 0 {
+		if w.Duration, err = duration(x.Duration, x.DurationUnit); err != nil {
+			return err
+		}
+	}
+
+	if x.TotalDistance > 0 {
+		if w.Distance, err = kilometers(x.TotalDistance, x.TotalDistanceUnit); err != nil {
+			return err
+		}
+	}
+	for _, s := range x.Statistics {
+		if w.Distance == 0 && strings.HasPrefix(s.Type, statsDist) && s.Sum > 0 {
+			if w.Distance, err = kilometers(s.Sum, s.Unit); err != nil {
+				return err
+			}
+		}
+	}
+	return h.Workout(w)
+}
+
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseDate(value string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата %q", value)
+	}
+	return t, nil
+}
+
+func duration(value float64, unit string) (time.Duration, error) {
+	var scale time.Duration
+	switch unit {
+	case "s":
+		scale = time.Second
+	case "min", "":
+		scale = time.Minute
+	case "h", "hr":
+		scale = time.Hour
+	default:
+		return 0, fmt.Errorf("неизвестная единица времени %q", unit)
+	}
+	return time.Duration(math.Round(value * float64(scale))), nil
+}
+
+func kilometers(value float64, unit string) (float64, error) {
+	switch unit {
+	case "km", "":
+		return value, nil
+	case "m":
+		return value / mInKm, nil
+	case "mi":
+		return value * kmInMile, nil
+	default:
+		return 0, fmt.Errorf("неизвестная единица расстояния %q", unit)
+	}
+}
+
+// Result - итоги импорта export.xml
+type Result struct {
+	// Итоги дней по записям о шагах, по возрастанию даты
+	Days []daysteps.DaySummary
+	// Тренировки в порядке записи в файле
+	Trainings []spentcalories.TrainingResult
+	// Тренировки, которые не удалось рассчитать, например без дистанции
+	RejectedWorkouts int
+}
+
+// Import читает export.xml и собирает записи о шагах в итоги дней, а тренировки
+// рассчитывает через spentcalories. activity используется для тренировок без вида,
+// а тренировки незарегистрированных видов, например Cycling, учитываются в
+// RejectedWorkouts. Память растет только с количеством дней и тренировок, но не
+// с количеством записей
+func Import(r io.Reader, activity string, weight, height float64, opts ...Option) (Result, error) {
+	o := newOptions(opts)
+
+	// Все записи переводятся в один пояс, иначе запись после перехода на летнее
+	// время попадет в день по своему смещению, а агрегатор отклонит ее как чужую
+	loc := o.location
+	in := func(t time.Time) time.Time {
+		if loc == nil {
+			loc = t.Location()
+		}
+		return t.In(loc)
+	}
+
+	var result Result
+	days := make(map[string]*daysteps.Aggregator)
+	h := Handler{
+		Steps: func(s StepSample) error {
+			if !o.accept(s.Source) {
+				return nil
+			}
+			start := in(s.Start)
+			key := start.Format(time.DateOnly)
+			agg, ok := days[key]
+			if !ok {
+				agg = daysteps.NewAggregator(start, weight, height, o.day...)
+				days[key] = agg
+			}
+			// Записи с нулевой длительностью учитываются в шагах дня,
+			// а отклоненные, например без шагов, - в Rejected
+			_ = agg.AddSteps(start, s.Steps, s.End.Sub(s.Start))
+			return nil
+		},
+		Workout: func(w Workout) error {
+			if !o.accept(w.Source) {
+				return nil
+			}
+			// Вид тренировки совпадает с английским названием активности, например Running.
+			// Незарегистрированный вид не подменяется activity, а отклоняется в Compute
+			name := w.Type
+			if name == "" {
+				name = activity
+			}
+			t, err := spentcalories.Compute(spentcalories.Workout{
+				Start:    in(w.Start),
+				Activity: name,
+				Duration: w.Duration,
+				Distance: w.Distance,
+			}, weight, height, o.training...)
+			if err != nil {
+				result.RejectedWorkouts++
+				return nil
+			}
+			result.Trainings = append(result.Trainings, t)
+			return nil
+		},
+	}
+	if o.skipWorkouts {
+		h.Workout = nil
+	}
+	if err := Scan(r, h); err != nil {
+		return Result{}, err
+	}
+
+	for _, agg := range days {
+		result.Days = append(result.Days, agg.Summary())
+	}
+	sort.Slice(result.Days, func(i, j int) bool {
+		return result.Days[i].Date.Before(result.Days[j].Date)
+	})
+	return result, nil
+}
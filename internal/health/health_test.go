@@ -0,0 +1,171 @@
+package health
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Me,(Record|Workout)*)>
+<!ATTLIST HealthData locale CDATA #REQUIRED>
+]>
+<HealthData locale="ru_RU">
+ <ExportDate value="2024-03-07 10:00:00 +0300"/>
+ <Me HKCharacteristicTypeIdentifierBiologicalSex="HKBiologicalSexMale"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" unit="count" startDate="2024-03-05 07:00:00 +0300" endDate="2024-03-05 08:00:00 +0300" value="6000">
+  <MetadataEntry key="HKMetadataKeySyncVersion" value="2"/>
+ </Record>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-03-05 07:00:00 +0300" endDate="2024-03-05 08:00:00 +0300" value="5800"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Часы" unit="count/min" startDate="2024-03-05 07:10:00 +0300" endDate="2024-03-05 07:10:00 +0300" value="120"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" unit="count" startDate="2024-03-05 18:00:00 +0300" endDate="2024-03-05 18:30:00 +0300" value="3000"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" unit="count" startDate="2024-03-05 19:00:00 +0300" endDate="2024-03-05 19:00:00 +0300" value="10"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" unit="count" startDate="2024-03-04 12:00:00 +0300" endDate="2024-03-04 13:00:00 +0300" value="1000"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="30" durationUnit="min" totalDistance="5" totalDistanceUnit="km" sourceName="Часы" startDate="2024-03-05 07:00:00 +0300" endDate="2024-03-05 07:30:00 +0300">
+  <WorkoutEvent type="HKWorkoutEventTypeSegment" date="2024-03-05 07:10:00 +0300"/>
+ </Workout>
+ <Workout workoutActivityType="HKWorkoutActivityTypeWalking" duration="60" durationUnit="min" sourceName="Часы" startDate="2024-03-06 07:00:00 +0300" endDate="2024-03-06 08:00:00 +0300">
+  <WorkoutStatistics type="HKQuantityTypeIdentifierDistanceWalkingRunning" startDate="2024-03-06 07:00:00 +0300" endDate="2024-03-06 08:00:00 +0300" sum="2.5" unit="mi"/>
+ </Workout>
+ <Workout workoutActivityType="HKWorkoutActivityTypeYoga" duration="45" durationUnit="min" sourceName="Часы" startDate="2024-03-06 19:00:00 +0300" endDate="2024-03-06 19:45:00 +0300"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeCycling" duration="60" durationUnit="min" totalDistance="20" totalDistanceUnit="km" sourceName="Часы" startDate="2024-03-07 07:00:00 +0300" endDate="2024-03-07 08:00:00 +0300"/>
+</HealthData>`
+
+type HealthTestSuite struct {
+	suite.Suite
+}
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (suite *HealthTestSuite) TestScan() {
+	var samples []StepSample
+	var workouts []Workout
+	err := Scan(strings.NewReader(sample), Handler{
+		Steps:   func(s StepSample) error { samples = append(samples, s); return nil },
+		Workout: func(w Workout) error { workouts = append(workouts, w); return nil },
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), samples, 5)
+	assert.Equal(suite.T(), "Часы", samples[0].Source)
+	assert.Equal(suite.T(), 6000, samples[0].Steps)
+	assert.Equal(suite.T(), time.Hour, samples[0].End.Sub(samples[0].Start))
+
+	if assert.Len(suite.T(), workouts, 4) {
+		assert.Equal(suite.T(), "Running", workouts[0].Type)
+		assert.Equal(suite.T(), 30*time.Minute, workouts[0].Duration)
+		assert.Equal(suite.T(), 5.0, workouts[0].Distance)
+		// Дистанция из WorkoutStatistics в милях
+		assert.InDelta(suite.T(), 4.02336, workouts[1].Distance, 1e-9)
+		assert.Equal(suite.T(), 0.0, workouts[2].Distance)
+	}
+
+	// Обработчики без функций просто пропускают записи
+	assert.NoError(suite.T(), Scan(strings.NewReader(sample), Handler{}))
+}
+
+func (suite *HealthTestSuite) TestImport() {
+	msk := time.FixedZone("MSK", 3*60*60)
+	result, err := Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75, WithSources("Часы"))
+	assert.NoError(suite.T(), err)
+
+	if assert.Len(suite.T(), result.Days, 2) {
+		assert.True(suite.T(), result.Days[0].Date.Equal(time.Date(2024, time.March, 4, 0, 0, 0, 0, msk)))
+		assert.Equal(suite.T(), 1000, result.Days[0].Steps)
+
+		// Итог дня совпадает с агрегатором daysteps по тем же пакетам
+		agg := daysteps.NewAggregator(time.Date(2024, time.March, 5, 0, 0, 0, 0, msk), 75.0, 1.75)
+		assert.NoError(suite.T(), agg.Add("2024-03-05T07:00:00+03:00,6000,1h0m0s"))
+		assert.NoError(suite.T(), agg.Add("2024-03-05T18:00:00+03:00,3000,30m0s"))
+		// Шаги записи с нулевой длительностью тоже учитываются
+		assert.NoError(suite.T(), agg.AddSteps(time.Date(2024, time.March, 5, 19, 0, 0, 0, msk), 10, 0))
+		want := agg.Summary()
+		got := result.Days[1]
+		assert.Equal(suite.T(), 9010, got.Steps)
+		assert.Equal(suite.T(), want.Steps, got.Steps)
+		assert.Equal(suite.T(), want.Duration, got.Duration)
+		assert.InDelta(suite.T(), want.Distance, got.Distance, 1e-9)
+		assert.InDelta(suite.T(), want.Calories, got.Calories, 1e-9)
+		assert.Equal(suite.T(), 3, got.Packets)
+		assert.Equal(suite.T(), 0, got.Rejected)
+	}
+
+	// Йога без дистанции не может быть рассчитана, а велосипед не зарегистрирован
+	// и не считается ходьбой по умолчанию
+	assert.Equal(suite.T(), 2, result.RejectedWorkouts)
+	if assert.Len(suite.T(), result.Trainings, 2) {
+		assert.Equal(suite.T(), "Бег", result.Trainings[0].Activity)
+		assert.InDelta(suite.T(), 10.0, result.Trainings[0].Speed, 1e-9)
+		assert.Equal(suite.T(), "Ходьба", result.Trainings[1].Activity)
+		assert.Equal(suite.T(), time.Hour, result.Trainings[1].Duration)
+	}
+
+	// Без фильтра шаги iPhone и часов складываются
+	result, err = Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75, WithLocation(time.UTC))
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), result.Days, 2) {
+		assert.Equal(suite.T(), time.UTC, result.Days[1].Date.Location())
+		assert.Equal(suite.T(), 14810, result.Days[1].Steps)
+	}
+
+	// Только итоги дней: тренировки не рассчитываются
+	result, err = Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75, WithSources("Часы"), WithoutWorkouts())
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Days, 2)
+	assert.Empty(suite.T(), result.Trainings)
+	assert.Zero(suite.T(), result.RejectedWorkouts)
+}
+
+func (suite *HealthTestSuite) TestImportDaylightSaving() {
+	// 27 октября 2024 года в Берлине летнее время +0200 сменилось зимним +0100
+	export := `<HealthData>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" startDate="2024-10-27 01:00:00 +0200" endDate="2024-10-27 01:30:00 +0200" value="1000"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" startDate="2024-10-27 12:00:00 +0100" endDate="2024-10-27 13:00:00 +0100" value="2000"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Часы" startDate="2024-10-27 23:30:00 +0100" endDate="2024-10-27 23:45:00 +0100" value="500"/>
+</HealthData>`
+
+	// Дни всех записей считаются в поясе первой записи, поэтому ни одна не отклоняется
+	result, err := Import(strings.NewReader(export), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	steps, rejected := 0, 0
+	for _, day := range result.Days {
+		steps += day.Steps
+		rejected += day.Rejected
+	}
+	assert.Equal(suite.T(), 3500, steps)
+	assert.Zero(suite.T(), rejected)
+
+	// С явным поясом записи делятся на дни по местному времени
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if !assert.NoError(suite.T(), err) {
+		return
+	}
+	result, err = Import(strings.NewReader(export), "Ходьба", 75.0, 1.75, WithLocation(berlin))
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), result.Days, 1) {
+		assert.Equal(suite.T(), 3500, result.Days[0].Steps)
+		assert.Zero(suite.T(), result.Days[0].Rejected)
+	}
+}
+
+func (suite *HealthTestSuite) TestErrors() {
+	_, err := Import(strings.NewReader(""), "Ходьба", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	_, err = Import(strings.NewReader("<HealthData><Record"), "Ходьба", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	badDate := strings.Replace(sample, `startDate="2024-03-05 07:00:00 +0300"`, `startDate="вчера"`, 1)
+	_, err = Import(strings.NewReader(badDate), "Ходьба", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	badUnit := strings.Replace(sample, `unit="mi"`, `unit="furlong"`, 1)
+	_, err = Import(strings.NewReader(badUnit), "Ходьба", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Option настраивает Import
+type Option func(*options)
+
+type options struct {
+	sources      map[string]bool
+	location     *time.Location
+	skipWorkouts bool
+	day          []daysteps.Option
+	training     []spentcalories.Option
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// accept проверяет, что записи источника нужно учитывать
+func (o options) accept(source string) bool {
+	return len(o.sources) == 0 || o.sources[source]
+}
+
+// WithSources оставляет только записи указанных источников. iPhone и часы
+// записывают одни и те же шаги, и без фильтра они посчитаются дважды
+func WithSources(names ...string) Option {
+	return func(o *options) {
+		if o.sources == nil {
+			o.sources = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			o.sources[name] = true
+		}
+	}
+}
+
+// WithLocation задает часовой пояс, по которому записи делятся на дни.
+// По умолчанию используется пояс из даты первой записи: смещение в export.xml
+// меняется при переходе на летнее время, а дни всех записей должны считаться
+// в одном поясе
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// WithDayOptions задает опции расчета пакетов шагов, например модель шага
+func WithDayOptions(opts ...daysteps.Option) Option {
+	return func(o *options) {
+		o.day = append(o.day, opts...)
+	}
+}
+
+// WithoutWorkouts пропускает тренировки, когда нужны только итоги дней
+func WithoutWorkouts() Option {
+	return func(o *options) {
+		o.skipWorkouts = true
+	}
+}
+
+// WithTrainingOptions задает опции расчета тренировок
+func WithTrainingOptions(opts ...spentcalories.Option) Option {
+	return func(o *options) {
+		o.training = append(o.training, opts...)
+	}
+}
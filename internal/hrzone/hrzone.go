@@ -0,0 +1,153 @@
+package hrzone
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Count - количество пульсовых зон
+const Count = 5
+
+// Method - способ расчета границ зон
+type Method string
+
+const (
+	// MaxHR - границы как доля максимального пульса
+	MaxHR Method = "max"
+	// Reserve - границы как доля резерва пульса (формула Карвонена):
+	// пульс покоя + доля * (максимальный пульс - пульс покоя)
+	Reserve Method = "hrr"
+)
+
+// DefaultBounds - нижние границы пяти зон по умолчанию: 50, 60, 70, 80 и 90%
+var DefaultBounds = []float64{0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Config задает способ расчета и границы зон, хранится в профиле пользователя
+type Config struct {
+	// Способ расчета, по умолчанию MaxHR
+	Method Method `json:"method,omitempty" yaml:"method,omitempty"`
+	// Нижние границы зон в долях от 0 до 1 по возрастанию, по умолчанию DefaultBounds
+	Bounds []float64 `json:"bounds,omitempty" yaml:"bounds,omitempty,flow"`
+}
+
+// Validate проверяет способ расчета и границы зон
+func (c Config) Validate() error {
+	switch c.Method {
+	case "", MaxHR, Reserve:
+	default:
+		return fmt.Errorf("неизвестный способ расчета зон %q: ожидается max или hrr", c.Method)
+	}
+	if len(c.Bounds) == 0 {
+		return nil
+	}
+	if len(c.Bounds) != Count {
+		return fmt.Errorf("нужно %d границ зон, задано %d", Count, len(c.Bounds))
+	}
+	for i, b := range c.Bounds {
+		if b <= 0 || b >= 1 {
+			return fmt.Errorf("граница зоны %d должна быть между 0 и 1, задано %g", i+1, b)
+		}
+		if i > 0 && b <= c.Bounds[i-1] {
+			return errors.New("границы зон должны возрастать")
+		}
+	}
+	return nil
+}
+
+// EstimateMax оценивает максимальный пульс по возрасту формулой Танаки: 208 - 0.7 * возраст
+func EstimateMax(age int) float64 {
+	return 208 - 0.7*float64(age)
+}
+
+// Range - границы зоны в ударах в минуту, Low входит в зону, High - нет
+type Range struct {
+	Low  float64
+	High float64
+}
+
+// Zones - границы пяти зон пользователя в ударах в минуту
+type Zones [Count]Range
+
+// New рассчитывает границы зон по конфигурации, максимальному пульсу и пульсу покоя.
+// Пульс покоя нужен только для Reserve
+func New(cfg Config, maxHR, restHR float64) (Zones, error) {
+	if err := cfg.Validate(); err != nil {
+		return Zones{}, err
+	}
+	if maxHR <= 0 {
+		return Zones{}, errors.New("максимальный пульс должен быть положительным")
+	}
+
+	bounds := cfg.Bounds
+	if len(bounds) == 0 {
+		bounds = DefaultBounds
+	}
+
+	// От чего считаются доли: от нуля для MaxHR или от пульса покоя для Reserve
+	var base float64
+	if cfg.Method == Reserve {
+		if restHR <= 0 || restHR >= maxHR {
+			return Zones{}, errors.New("для зон по резерву пульса нужен пульс покоя меньше максимального")
+		}
+		base = restHR
+	}
+
+	var z Zones
+	for i, b := range bounds {
+		z[i].Low = base + b*(maxHR-base)
+		if i > 0 {
+			z[i-1].High = z[i].Low
+		}
+	}
+	z[Count-1].High = maxHR
+	return z, nil
+}
+
+// Zone возвращает номер зоны от 1 до 5 для пульса bpm или 0, если пульс ниже первой зоны.
+// Пульс выше максимального относится к пятой зоне
+func (z Zones) Zone(bpm float64) int {
+	for i := Count - 1; i >= 0; i-- {
+		if bpm >= z[i].Low {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ZoneTime - время в одной зоне
+type ZoneTime struct {
+	// Номер зоны, 0 - пульс ниже первой зоны
+	Zone     int           `json:"zone"`
+	Low      float64       `json:"low_bpm"`
+	High     float64       `json:"high_bpm"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Counter накапливает время по зонам
+type Counter struct {
+	zones Zones
+	time  [Count + 1]time.Duration
+}
+
+// NewCounter создает счетчик времени для зон z
+func NewCounter(z Zones) *Counter {
+	return &Counter{zones: z}
+}
+
+// Add учитывает интервал d с пульсом bpm
+func (c *Counter) Add(bpm float64, d time.Duration) {
+	if d > 0 {
+		c.time[c.zones.Zone(bpm)] += d
+	}
+}
+
+// Result возвращает время во всех зонах, начиная с нулевой
+func (c *Counter) Result() []ZoneTime {
+	result := make([]ZoneTime, 0, Count+1)
+	result = append(result, ZoneTime{Zone: 0, High: c.zones[0].Low, Duration: c.time[0]})
+	for i, r := range c.zones {
+		result = append(result, ZoneTime{Zone: i + 1, Low: r.Low, High: r.High, Duration: c.time[i+1]})
+	}
+	return result
+}
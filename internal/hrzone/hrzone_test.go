@@ -0,0 +1,115 @@
+package hrzone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HRZoneTestSuite struct {
+	suite.Suite
+}
+
+func TestHRZoneSuite(t *testing.T) {
+	suite.Run(t, new(HRZoneTestSuite))
+}
+
+func (suite *HRZoneTestSuite) TestNew() {
+	tests := []struct {
+		name   string
+		cfg    Config
+		maxHR  float64
+		restHR float64
+		want   Zones
+	}{
+		{
+			name:  "от максимального пульса",
+			maxHR: 200,
+			want:  Zones{{100, 120}, {120, 140}, {140, 160}, {160, 180}, {180, 200}},
+		},
+		{
+			name:   "по резерву пульса",
+			cfg:    Config{Method: Reserve},
+			maxHR:  200,
+			restHR: 60,
+			want:   Zones{{130, 144}, {144, 158}, {158, 172}, {172, 186}, {186, 200}},
+		},
+		{
+			name:  "свои границы",
+			cfg:   Config{Bounds: []float64{0.55, 0.7, 0.8, 0.87, 0.93}},
+			maxHR: 200,
+			want:  Zones{{110, 140}, {140, 160}, {160, 174}, {174, 186}, {186, 200}},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := New(tt.cfg, tt.maxHR, tt.restHR)
+			assert.NoError(suite.T(), err)
+			for i := range got {
+				assert.InDelta(suite.T(), tt.want[i].Low, got[i].Low, 1e-9, "зона %d", i+1)
+				assert.InDelta(suite.T(), tt.want[i].High, got[i].High, 1e-9, "зона %d", i+1)
+			}
+		})
+	}
+}
+
+func (suite *HRZoneTestSuite) TestErrors() {
+	tests := []struct {
+		name   string
+		cfg    Config
+		maxHR  float64
+		restHR float64
+	}{
+		{name: "неизвестный способ", cfg: Config{Method: "lthr"}, maxHR: 200},
+		{name: "мало границ", cfg: Config{Bounds: []float64{0.5, 0.6}}, maxHR: 200},
+		{name: "границы не возрастают", cfg: Config{Bounds: []float64{0.5, 0.7, 0.6, 0.8, 0.9}}, maxHR: 200},
+		{name: "граница больше 1", cfg: Config{Bounds: []float64{0.5, 0.6, 0.7, 0.8, 1.1}}, maxHR: 200},
+		{name: "нет максимального пульса"},
+		{name: "нет пульса покоя", cfg: Config{Method: Reserve}, maxHR: 200},
+		{name: "пульс покоя выше максимального", cfg: Config{Method: Reserve}, maxHR: 150, restHR: 160},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, err := New(tt.cfg, tt.maxHR, tt.restHR)
+			assert.Error(suite.T(), err)
+		})
+	}
+}
+
+func (suite *HRZoneTestSuite) TestZone() {
+	z, err := New(Config{}, 200, 0)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), 0, z.Zone(99))
+	assert.Equal(suite.T(), 1, z.Zone(100))
+	assert.Equal(suite.T(), 3, z.Zone(159.9))
+	assert.Equal(suite.T(), 5, z.Zone(180))
+	assert.Equal(suite.T(), 5, z.Zone(210))
+}
+
+func (suite *HRZoneTestSuite) TestCounter() {
+	z, err := New(Config{}, 200, 0)
+	assert.NoError(suite.T(), err)
+
+	c := NewCounter(z)
+	c.Add(90, 5*time.Minute)
+	c.Add(150, 20*time.Minute)
+	c.Add(155, 10*time.Minute)
+	c.Add(190, time.Minute)
+	c.Add(190, -time.Minute)
+
+	got := c.Result()
+	assert.Len(suite.T(), got, Count+1)
+	assert.Equal(suite.T(), ZoneTime{Zone: 0, High: 100, Duration: 5 * time.Minute}, got[0])
+	assert.Equal(suite.T(), ZoneTime{Zone: 3, Low: 140, High: 160, Duration: 30 * time.Minute}, got[3])
+	assert.Equal(suite.T(), time.Minute, got[5].Duration)
+	assert.Zero(suite.T(), got[1].Duration)
+}
+
+func (suite *HRZoneTestSuite) TestEstimateMax() {
+	assert.InDelta(suite.T(), 187.0, EstimateMax(30), 1e-9)
+}
@@ -0,0 +1,74 @@
+package i18n
+
+var english = Catalog{
+	// Результаты
+	"day.result":      "%[2]s.\nDistance: %.2[3]f %[5]s.\nBurned: %.2[4]f kcal.\n",
+	"day.summary":     "Date: %[1]s\nSteps: %[2]d.\nActive time: %.2[3]f h.\nDistance: %.2[4]f %[6]s.\nBurned: %.2[5]f kcal.\n",
+	"training.result": "Training type: %s\nDuration: %.2f h.\nDistance: %.2f %s.\nSpeed: %.2f %s\nCalories burned: %.2f\n",
+	"weight.entry":    "%[1]s: %.1[2]f %[4]s, trend %.1[3]f %[4]s",
+	"tdee.report":     "Date: %[1]s\nBasal metabolic rate (%[3]s): %.2[2]f kcal.\nWalking: %[4]s, %.2[5]f kcal.\nTrainings: %[6]d, %.2[7]f kcal.\nTotal for the day: %.2[8]f kcal.\n",
+
+	// Единицы измерения
+	"unit.km":  "km",
+	"unit.mi":  "mi",
+	"unit.kmh": "km/h",
+	"unit.mph": "mph",
+	"unit.kg":  "kg",
+	"unit.lb":  "lb",
+
+	// Формы слов
+	"noun.steps":   "step|steps",
+	"noun.records": "record|records",
+
+	// Ошибки разбора
+	"parse.record": "Error: %s: %s",
+	"parse.field":  "Error: %s in column %d (%q): %s",
+
+	"err.field_count":      "wrong number of values",
+	"err.time":             "invalid time",
+	"err.steps":            "invalid step count",
+	"err.activity":         "invalid activity",
+	"err.duration":         "invalid duration",
+	"err.unknown_activity": "unknown training type",
+
+	"reason.fields_day":                   "expected 2 values or 3 with a start time, got %d",
+	"reason.fields_training":              "expected 3 values or 4 with a start time, got %d",
+	"reason.steps_not_integer":            "an integer is expected",
+	"reason.steps_positive":               "step count must be > 0",
+	"reason.steps_space":                  "spaces around the step count are not allowed",
+	"reason.activity_empty":               "activity is empty",
+	"reason.activity_not_registered":      "activity is not registered",
+	"reason.activity_name_not_registered": "activity %q is not registered",
+	"reason.duration_format":              "a duration like 1h30m is expected",
+	"reason.duration_positive":            "duration must be positive",
+	"reason.duration_negative":            "duration must not be negative",
+	"reason.duration_space":               "spaces around the duration are not allowed",
+	"reason.time_format":                  "an RFC 3339 time or HH:MM[:SS] is expected",
+	"reason.time_no_date":                 "no date is set for a time of day",
+	"reason.time_space":                   "spaces around the time are not allowed",
+	"reason.time_other_day":               "time belongs to another day, expected %s",
+
+	// Ошибки расчета
+	"err.weight_positive":     "weight must be positive",
+	"err.height_positive":     "height must be positive",
+	"err.steps_positive":      "step count must be positive",
+	"err.duration_positive":   "duration must be positive",
+	"err.heart_rate_positive": "heart rate must be positive",
+	"err.age_positive":        "age must be positive",
+	"err.no_met":              "no MET table is set for activity %s",
+
+	// Командная строка
+	"cli.summary":     "processed %s, failed: %d",
+	"server.rejected": "records rejected: %d of %d failed",
+
+	// Проверка профиля
+	"profile.invalid":           "profile: %s: %s",
+	"profile.range":             "value %g is outside the allowed range %g to %g",
+	"profile.birth_date_future": "birth date %s is in the future",
+	"profile.age_range":         "age %d is over %d years",
+	"profile.sex":               "unknown sex %q: expected male or female",
+	"profile.resting_hr":        "resting heart rate %g must be below the maximum %g",
+	"profile.zones":             "%s",
+	"profile.units":             "unknown unit system %q: expected metric or imperial",
+	"profile.locale":            "unsupported language %q",
+}
@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Locale - язык вывода сообщений
+type Locale string
+
+const (
+	Russian Locale = "ru"
+	English Locale = "en"
+	// Default - язык, на котором написаны исходные сообщения трекера
+	Default = Russian
+)
+
+// Catalog хранит сообщения в формате fmt по ключам.
+// Формы множественного числа записываются через "|": "шаг|шага|шагов", "step|steps"
+type Catalog map[string]string
+
+var catalogs = map[Locale]Catalog{
+	Russian: russian,
+	English: english,
+}
+
+// Locales возвращает поддерживаемые языки
+func Locales() []Locale {
+	return []Locale{Russian, English}
+}
+
+// Parse разбирает обозначение языка вида "en", "en-US" или "ru_RU.UTF-8"
+func Parse(s string) (Locale, error) {
+	tag := strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(tag, "-_."); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[Locale(tag)]; !ok {
+		return "", fmt.Errorf("неподдерживаемый язык %q", s)
+	}
+	return Locale(tag), nil
+}
+
+// Lookup возвращает сообщение по ключу без подстановки аргументов.
+// Если в каталоге языка сообщения нет, используется каталог Default
+func (l Locale) Lookup(key string) (string, bool) {
+	if msg, ok := catalogs[l][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[Default][key]
+	return msg, ok
+}
+
+// T возвращает сообщение по ключу с подставленными аргументами, а если ключа нет - сам ключ
+func (l Locale) T(key string, args ...any) string {
+	msg, ok := l.Lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Plural выбирает форму слова по ключу для числа n: Plural(5, "noun.steps") = "шагов"
+func (l Locale) Plural(n int, key string) string {
+	msg, ok := l.Lookup(key)
+	if !ok {
+		return key
+	}
+	forms := strings.Split(msg, "|")
+	i := l.pluralForm(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return forms[i]
+}
+
+// Count возвращает число вместе с подходящей формой слова: "1 шаг", "2 шага", "5 шагов"
+func (l Locale) Count(n int, key string) string {
+	return fmt.Sprintf("%d %s", n, l.Plural(n, key))
+}
+
+// pluralForm возвращает номер формы множественного числа по правилам языка
+func (l Locale) pluralForm(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch l {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
+
+// Error - ошибка, текст которой берется из каталога. Error() возвращает текст на языке Default
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// NewError создает ошибку с сообщением из каталога
+func NewError(key string, args ...any) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return e.Localize(Default)
+}
+
+// Localize возвращает текст ошибки на языке loc
+func (e *Error) Localize(loc Locale) string {
+	return loc.T(e.Key, e.Args...)
+}
+
+// Localizer реализуют ошибки, текст которых можно перевести
+type Localizer interface {
+	Localize(loc Locale) string
+}
+
+// Localize возвращает текст ошибки на языке loc, если ошибка или обернутая
+// в ней ошибка поддерживает перевод, и err.Error() в остальных случаях
+func Localize(err error, loc Locale) string {
+	var l Localizer
+	if errors.As(err, &l) {
+		return l.Localize(loc)
+	}
+	return err.Error()
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type I18nTestSuite struct {
+	suite.Suite
+}
+
+func TestI18nSuite(t *testing.T) {
+	suite.Run(t, new(I18nTestSuite))
+}
+
+func (suite *I18nTestSuite) TestCatalogsHaveSameKeys() {
+	for key := range russian {
+		_, ok := english[key]
+		assert.True(suite.T(), ok, "в английском каталоге нет ключа %q", key)
+	}
+	for key := range english {
+		_, ok := russian[key]
+		assert.True(suite.T(), ok, "в русском каталоге нет ключа %q", key)
+	}
+}
+
+func (suite *I18nTestSuite) TestPlural() {
+	tests := []struct {
+		n      int
+		wantRu string
+		wantEn string
+	}{
+		{n: 0, wantRu: "0 шагов", wantEn: "0 steps"},
+		{n: 1, wantRu: "1 шаг", wantEn: "1 step"},
+		{n: 2, wantRu: "2 шага", wantEn: "2 steps"},
+		{n: 4, wantRu: "4 шага", wantEn: "4 steps"},
+		{n: 5, wantRu: "5 шагов", wantEn: "5 steps"},
+		{n: 11, wantRu: "11 шагов", wantEn: "11 steps"},
+		{n: 12, wantRu: "12 шагов", wantEn: "12 steps"},
+		{n: 21, wantRu: "21 шаг", wantEn: "21 steps"},
+		{n: 22, wantRu: "22 шага", wantEn: "22 steps"},
+		{n: 111, wantRu: "111 шагов", wantEn: "111 steps"},
+		{n: 1001, wantRu: "1001 шаг", wantEn: "1001 steps"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(fmt.Sprint(tt.n), func() {
+			assert.Equal(suite.T(), tt.wantRu, Russian.Count(tt.n, "noun.steps"))
+			assert.Equal(suite.T(), tt.wantEn, English.Count(tt.n, "noun.steps"))
+		})
+	}
+}
+
+func (suite *I18nTestSuite) TestParse() {
+	for _, s := range []string{"en", "EN", "en-US", "en_GB.UTF-8"} {
+		loc, err := Parse(s)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), English, loc)
+	}
+	loc, err := Parse("ru_RU")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), Russian, loc)
+
+	_, err = Parse("de")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *I18nTestSuite) TestT() {
+	assert.Equal(suite.T(), "weight must be positive", English.T("err.weight_positive"))
+	assert.Equal(suite.T(), "no such key", English.T("no such key"))
+	// Неизвестный язык берет сообщения из каталога по умолчанию
+	assert.Equal(suite.T(), "вес должен быть положительным", Locale("de").T("err.weight_positive"))
+}
+
+func (suite *I18nTestSuite) TestLocalizeError() {
+	base := NewError("err.no_met", "Бег")
+	wrapped := fmt.Errorf("расчет: %w", base)
+
+	assert.Equal(suite.T(), "для активности Бег не задана таблица MET", base.Error())
+	assert.Equal(suite.T(), "no MET table is set for activity Бег", Localize(wrapped, English))
+	assert.Equal(suite.T(), "plain", Localize(errors.New("plain"), English))
+}
@@ -0,0 +1,74 @@
+package i18n
+
+var russian = Catalog{
+	// Результаты
+	"day.result":      "Количество шагов: %[1]d.\nДистанция составила %.2[3]f %[5]s.\nВы сожгли %.2[4]f ккал.\n",
+	"day.summary":     "Дата: %[1]s\nКоличество шагов: %[2]d.\nАктивное время: %.2[3]f ч.\nДистанция: %.2[4]f %[6]s.\nВы сожгли %.2[5]f ккал.\n",
+	"training.result": "Тип тренировки: %s\nДлительность: %.2f ч.\nДистанция: %.2f %s.\nСкорость: %.2f %s\nСожгли калорий: %.2f\n",
+	"weight.entry":    "%[1]s: %.1[2]f %[4]s, тренд %.1[3]f %[4]s",
+	"tdee.report":     "Дата: %[1]s\nОсновной обмен (%[3]s): %.2[2]f ккал.\nХодьба: %[4]s, %.2[5]f ккал.\nТренировки: %[6]d, %.2[7]f ккал.\nВсего за день: %.2[8]f ккал.\n",
+
+	// Единицы измерения
+	"unit.km":  "км",
+	"unit.mi":  "миль",
+	"unit.kmh": "км/ч",
+	"unit.mph": "миль/ч",
+	"unit.kg":  "кг",
+	"unit.lb":  "фунтов",
+
+	// Формы слов
+	"noun.steps":   "шаг|шага|шагов",
+	"noun.records": "запись|записи|записей",
+
+	// Ошибки разбора
+	"parse.record": "Ошибка: %s: %s",
+	"parse.field":  "Ошибка: %s в колонке %d (%q): %s",
+
+	"err.field_count":      "неверное количество значений",
+	"err.time":             "некорректное время",
+	"err.steps":            "некорректное количество шагов",
+	"err.activity":         "некорректный вид активности",
+	"err.duration":         "некорректная продолжительность",
+	"err.unknown_activity": "неизвестный тип тренировки",
+
+	"reason.fields_day":                   "ожидалось 2 значения или 3 с временем начала, получено %d",
+	"reason.fields_training":              "ожидается 3 значения или 4 с временем начала, получено %d",
+	"reason.steps_not_integer":            "ожидается целое число",
+	"reason.steps_positive":               "кол-во шагов должно быть > 0",
+	"reason.steps_space":                  "пробелы в количестве шагов не допускаются",
+	"reason.activity_empty":               "вид активности не указан",
+	"reason.activity_not_registered":      "активность не зарегистрирована",
+	"reason.activity_name_not_registered": "активность %q не зарегистрирована",
+	"reason.duration_format":              "ожидается продолжительность вида 1h30m",
+	"reason.duration_positive":            "продолжительность должна быть положительная",
+	"reason.duration_negative":            "продолжительность не может быть отрицательной",
+	"reason.duration_space":               "пробелы в продолжительности не допускаются",
+	"reason.time_format":                  "ожидается время в формате RFC 3339 или ЧЧ:ММ[:СС]",
+	"reason.time_no_date":                 "для времени суток не задана дата",
+	"reason.time_space":                   "пробелы во времени не допускаются",
+	"reason.time_other_day":               "время относится к другому дню, ожидается %s",
+
+	// Ошибки расчета
+	"err.weight_positive":     "вес должен быть положительным",
+	"err.height_positive":     "рост должен быть положительным",
+	"err.steps_positive":      "количество шагов должно быть положительным",
+	"err.duration_positive":   "продолжительность должна быть положительной",
+	"err.heart_rate_positive": "пульс должен быть положительным",
+	"err.age_positive":        "возраст должен быть положительным",
+	"err.no_met":              "для активности %s не задана таблица MET",
+
+	// Командная строка
+	"cli.summary":     "обработано %s, с ошибками: %d",
+	"server.rejected": "записи не приняты: с ошибками %d из %d",
+
+	// Проверка профиля
+	"profile.invalid":           "профиль: %s: %s",
+	"profile.range":             "значение %g вне допустимого диапазона от %g до %g",
+	"profile.birth_date_future": "дата рождения %s в будущем",
+	"profile.age_range":         "возраст %d больше %d лет",
+	"profile.sex":               "неизвестный пол %q: ожидается male или female",
+	"profile.resting_hr":        "пульс покоя %g должен быть меньше максимального %g",
+	"profile.zones":             "%s",
+	"profile.units":             "неизвестная система единиц %q: ожидается metric или imperial",
+	"profile.locale":            "неподдерживаемый язык %q",
+}
@@ -0,0 +1,110 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Compact переписывает журнал без удаленных записей и отметок об удалении,
+// упорядочивая записи по времени. Новый файл пишется рядом и сбрасывается
+// на диск, а затем атомарно заменяет старый, поэтому сбой посреди сжатия
+// оставляет прежний журнал целым. Номера записей сохраняются, а последний
+// выданный номер записывается отметкой KindSeq, если его запись удалена, чтобы
+// номера удаленных записей не выдавались снова после повторного открытия
+func (j *Journal) Compact() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return os.ErrClosed
+	}
+	if j.readOnly {
+		return ErrReadOnly
+	}
+
+	tmp := j.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	// Новый файл блокируется до переименования, чтобы другой процесс
+	// не открыл его по прежнему пути, пока журнал открыт
+	if err := lock(f, lockExclusive); err != nil {
+		f.Close()
+		return err
+	}
+
+	index := make([]position, 0, len(j.index))
+	w := bufio.NewWriter(f)
+	var offset int64
+	var last uint64
+	for _, p := range j.index {
+		if j.deleted[p.seq] {
+			continue
+		}
+		// Строка копируется как есть вместе с контрольной суммой, но сначала проверяется
+		line := make([]byte, p.size)
+		if _, err := j.file.ReadAt(line, p.offset); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := decode(line); err != nil {
+			f.Close()
+			return fmt.Errorf("%w: запись %d: %v", ErrCorrupt, p.seq, err)
+		}
+		if _, err := w.Write(line); err != nil {
+			f.Close()
+			return err
+		}
+		index = append(index, position{time: p.time, seq: p.seq, offset: offset, size: p.size})
+		offset += int64(p.size)
+		last = max(last, p.seq)
+	}
+	if last+1 < j.next {
+		line, err := encode(Entry{Seq: j.next - 1, Kind: KindSeq, Time: time.Now()})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := w.Write(line); err != nil {
+			f.Close()
+			return err
+		}
+		offset += int64(len(line))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := os.Rename(tmp, j.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	// После переименования по пути журнала уже новый файл, и писать нужно в него,
+	// даже если каталог не удалось сбросить на диск: иначе записи уйдут в удаленный файл
+	j.file.Close()
+	j.file = f
+	j.size = offset
+	j.index = index
+	j.deleted = make(map[uint64]bool)
+	return syncDir(filepath.Dir(j.path))
+}
+
+// syncDir сбрасывает на диск каталог, чтобы переименование файла пережило сбой
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
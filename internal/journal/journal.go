@@ -0,0 +1,430 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Формат файла - JSON lines, перед каждой записью контрольная сумма:
+//
+//	<crc32c 8 hex> <JSON записи>\n
+//
+// Запись добавляется одним вызовом write в файл, открытый с O_APPEND, и сбрасывается
+// на диск через fsync. Если процесс упал посреди записи, последняя строка оказывается
+// без перевода строки или с неверной суммой - при открытии на запись такой хвост отрезается
+const (
+	crcLen    = 8
+	headerLen = crcLen + 1
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var (
+	// ErrCorrupt - повреждена запись не в конце журнала, такой журнал не открывается
+	ErrCorrupt = errors.New("журнал поврежден")
+	// ErrLocked - журнал уже открыт на запись другим процессом или, для Open, на чтение
+	ErrLocked = errors.New("журнал открыт другим процессом")
+	// ErrReadOnly - журнал открыт только для чтения
+	ErrReadOnly = errors.New("журнал открыт только для чтения")
+)
+
+// Kind - вид записи журнала
+type Kind string
+
+const (
+	KindDay      Kind = "day"
+	KindTraining Kind = "training"
+	// KindDelete - отметка об удалении записи Target, удаленные записи убирает Compact
+	KindDelete Kind = "delete"
+	// KindSeq - последний выданный номер. Его записывает Compact, если удалены
+	// записи с наибольшими номерами, чтобы эти номера не были выданы снова
+	KindSeq Kind = "seq"
+)
+
+// Entry - запись журнала
+type Entry struct {
+	// Порядковый номер, назначается журналом
+	Seq  uint64 `json:"seq"`
+	Kind Kind   `json:"kind"`
+	// Время, по которому запись ищется в Range: начало активности,
+	// а если оно неизвестно - момент добавления
+	Time     time.Time                     `json:"time"`
+	Day      *daysteps.DayActionResult     `json:"day,omitempty"`
+	Training *spentcalories.TrainingResult `json:"training,omitempty"`
+	// Номер удаляемой записи для KindDelete
+	Target uint64 `json:"target,omitempty"`
+}
+
+// Day создает запись о пакете дневной активности
+func Day(r daysteps.DayActionResult) Entry {
+	return Entry{Kind: KindDay, Time: r.Start, Day: &r}
+}
+
+// Training создает запись о тренировке
+func Training(r spentcalories.TrainingResult) Entry {
+	return Entry{Kind: KindTraining, Time: r.Start, Training: &r}
+}
+
+// Format форматирует результат записи так же, как команды day и training
+func (e Entry) Format(loc i18n.Locale, sys units.System) string {
+	switch {
+	case e.Day != nil:
+		return e.Day.Format(loc, sys)
+	case e.Training != nil:
+		return e.Training.Format(loc, sys)
+	default:
+		return ""
+	}
+}
+
+// position - положение записи в файле для индекса
+type position struct {
+	time   time.Time
+	seq    uint64
+	offset int64
+	size   int
+}
+
+// Journal - журнал активности в файле, в который записи только добавляются.
+// Методы безопасны для параллельного вызова. Между процессами файл защищен
+// блокировкой flock: на запись его открывает один процесс, на чтение - несколько
+type Journal struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	readOnly bool
+	size     int64
+	next     uint64
+	// Индекс живых записей по времени и номера удаленных
+	index   []position
+	deleted map[uint64]bool
+	// Отрезанный при открытии поврежденный хвост в байтах
+	truncated int64
+}
+
+// Open открывает журнал на запись или создает новый. Поврежденный хвост после сбоя
+// отрезается, размер отрезанного возвращает Truncated. Пока журнал открыт, другие
+// процессы не могут открыть его ни на запись, ни на чтение и получают ErrLocked
+func Open(path string) (*Journal, error) {
+	return open(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, lockExclusive)
+}
+
+// OpenReadOnly открывает существующий журнал только для чтения. Поврежденный
+// хвост пропускается, но файл не меняется. Читать журнал могут несколько
+// процессов одновременно, пока его никто не открыл на запись
+func OpenReadOnly(path string) (*Journal, error) {
+	return open(path, os.O_RDONLY, lockShared)
+}
+
+func open(path string, flag int, mode lockMode) (*Journal, error) {
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lock(f, mode); err != nil {
+		f.Close()
+		return nil, err
+	}
+	j := &Journal{path: path, file: f, readOnly: flag == os.O_RDONLY, next: 1, deleted: make(map[uint64]bool)}
+	if err := j.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// load читает журнал, строит индекс и отрезает недописанный хвост, если журнал открыт на запись
+func (j *Journal) load() error {
+	r := bufio.NewReader(j.file)
+	var offset int64
+	var torn error
+	for line := 1; ; line++ {
+		data, err := r.ReadBytes('\n')
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if torn != nil {
+			// После поврежденной строки есть данные - это не недописанный хвост
+			return torn
+		}
+		e, derr := decode(data)
+		var tornErr *tornError
+		if errors.As(derr, &tornErr) {
+			torn = fmt.Errorf("%w: строка %d: %v", ErrCorrupt, line, derr)
+			if err == nil {
+				continue
+			}
+			break
+		}
+		if derr != nil {
+			// Сумма сошлась, значит запись дописана целиком, но не читается - это не сбой записи
+			return fmt.Errorf("%w: строка %d: %v", ErrCorrupt, line, derr)
+		}
+		j.add(e, offset, len(data))
+		offset += int64(len(data))
+	}
+
+	j.size = offset
+	if j.readOnly {
+		return nil
+	}
+	info, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > offset {
+		j.truncated = info.Size() - offset
+		if err := j.file.Truncate(offset); err != nil {
+			return err
+		}
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add учитывает запись в индексе
+func (j *Journal) add(e Entry, offset int64, size int) {
+	if e.Seq >= j.next {
+		j.next = e.Seq + 1
+	}
+	switch e.Kind {
+	case KindDelete:
+		j.deleted[e.Target] = true
+		return
+	case KindSeq:
+		return
+	}
+	pos := position{time: e.Time, seq: e.Seq, offset: offset, size: size}
+	// Записи обычно добавляются по времени, поэтому вставка почти всегда в конец
+	i := sort.Search(len(j.index), func(i int) bool { return j.index[i].time.After(e.Time) })
+	j.index = append(j.index, position{})
+	copy(j.index[i+1:], j.index[i:])
+	j.index[i] = pos
+}
+
+func encode(e Entry) ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	line := make([]byte, 0, headerLen+len(payload)+1)
+	line = fmt.Appendf(line, "%08x ", crc32.Checksum(payload, crcTable))
+	line = append(line, payload...)
+	return append(line, '\n'), nil
+}
+
+// tornError - строка записана не полностью: нет перевода строки или не сошлась сумма
+type tornError struct {
+	reason string
+}
+
+func (e *tornError) Error() string {
+	return e.reason
+}
+
+// decode проверяет контрольную сумму строки и разбирает запись
+func decode(line []byte) (Entry, error) {
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		return Entry{}, &tornError{"запись не дописана"}
+	}
+	line = line[:len(line)-1]
+	if len(line) <= headerLen || line[crcLen] != ' ' {
+		return Entry{}, &tornError{"нет контрольной суммы"}
+	}
+
+	var sum [4]byte
+	if _, err := hex.Decode(sum[:], line[:crcLen]); err != nil {
+		return Entry{}, &tornError{"нет контрольной суммы"}
+	}
+	payload := line[headerLen:]
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(sum[:]) {
+		return Entry{}, &tornError{"неверная контрольная сумма"}
+	}
+
+	var e Entry
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Append добавляет записи и сбрасывает их на диск одним fsync. Журнал
+// назначает записям номера, а записям без времени - текущее время.
+// Возвращает записи с назначенными номерами
+func (j *Journal) Append(entries ...Entry) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.append(entries)
+}
+
+// append добавляет записи, вызывается под j.mu
+func (j *Journal) append(entries []Entry) ([]Entry, error) {
+	if j.file == nil {
+		return nil, os.ErrClosed
+	}
+	if j.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	var buf []byte
+	lines := make([]int, len(entries))
+	result := make([]Entry, len(entries))
+	now := time.Now()
+	for i, e := range entries {
+		switch e.Kind {
+		case KindDay, KindTraining, KindDelete:
+		default:
+			return nil, fmt.Errorf("неизвестный вид записи %q", e.Kind)
+		}
+		e.Seq = j.next + uint64(i)
+		if e.Time.IsZero() {
+			e.Time = now
+		}
+		line, err := encode(e)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, line...)
+		lines[i] = len(line)
+		result[i] = e
+	}
+
+	// Одна запись write на все строки в конец файла: при сбое теряется только хвост
+	if _, err := j.file.Write(buf); err != nil {
+		return nil, j.rollback(err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return nil, j.rollback(err)
+	}
+
+	offset := j.size
+	for i, e := range result {
+		j.add(e, offset, lines[i])
+		offset += int64(lines[i])
+	}
+	j.size = offset
+	return result, nil
+}
+
+// rollback отрезает частично записанные данные после ошибки записи
+func (j *Journal) rollback(err error) error {
+	if terr := j.file.Truncate(j.size); terr != nil {
+		return errors.Join(err, terr)
+	}
+	return err
+}
+
+// Delete отмечает запись seq удаленной. Место в файле освобождает Compact.
+// Проверка и отметка делаются под одной блокировкой, поэтому параллельные
+// Delete и Compact не оставляют повторных отметок или отметок об убранных записях
+func (j *Journal) Delete(seq uint64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	found := false
+	for _, p := range j.index {
+		if p.seq == seq {
+			found = !j.deleted[seq]
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("запись %d не найдена", seq)
+	}
+
+	_, err := j.append([]Entry{{Kind: KindDelete, Target: seq}})
+	return err
+}
+
+// Range возвращает живые записи со временем в [from, to) по возрастанию времени.
+// Нулевой from или to не ограничивает диапазон с этой стороны
+func (j *Journal) Range(from, to time.Time) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil, os.ErrClosed
+	}
+
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(j.index), func(i int) bool { return !j.index[i].time.Before(from) })
+	}
+	var entries []Entry
+	for _, p := range j.index[start:] {
+		if !to.IsZero() && !p.time.Before(to) {
+			break
+		}
+		if j.deleted[p.seq] {
+			continue
+		}
+		e, err := j.read(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (j *Journal) read(p position) (Entry, error) {
+	line := make([]byte, p.size)
+	if _, err := j.file.ReadAt(line, p.offset); err != nil {
+		return Entry{}, err
+	}
+	e, err := decode(line)
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: запись %d: %v", ErrCorrupt, p.seq, err)
+	}
+	return e, nil
+}
+
+// Len возвращает количество живых записей
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	n := 0
+	for _, p := range j.index {
+		if !j.deleted[p.seq] {
+			n++
+		}
+	}
+	return n
+}
+
+// Truncated возвращает количество байт поврежденного хвоста, отрезанного при открытии.
+// Для журнала, открытого только для чтения, хвост не отрезается и результат 0
+func (j *Journal) Truncated() int64 {
+	return j.truncated
+}
+
+// Close закрывает файл журнала и снимает блокировку
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
@@ -0,0 +1,390 @@
+package journal
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JournalTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestJournalSuite(t *testing.T) {
+	suite.Run(t, new(JournalTestSuite))
+}
+
+func (suite *JournalTestSuite) SetupTest() {
+	suite.path = filepath.Join(suite.T().TempDir(), "activity.journal")
+}
+
+var day0 = time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+// fill добавляет пакет 5 марта, тренировку 5 марта и пакет 6 марта, записанные не по порядку
+func (suite *JournalTestSuite) fill(j *Journal) []Entry {
+	entries, err := j.Append(
+		Day(daysteps.DayActionResult{Start: day0.Add(9 * time.Hour), Steps: 6000, Duration: time.Hour, Calories: 177}),
+		Day(daysteps.DayActionResult{Start: day0.Add(33 * time.Hour), Steps: 3000, Duration: 30 * time.Minute, Calories: 88}),
+	)
+	assert.NoError(suite.T(), err)
+	more, err := j.Append(Training(spentcalories.TrainingResult{
+		Start:    day0.Add(12 * time.Hour),
+		Activity: "Бег",
+		Steps:    8000,
+		Duration: time.Hour,
+		Calories: 472.5,
+		Model:    spentcalories.METModel,
+	}))
+	assert.NoError(suite.T(), err)
+	return append(entries, more...)
+}
+
+func seqs(entries []Entry) []uint64 {
+	result := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.Seq)
+	}
+	return result
+}
+
+func (suite *JournalTestSuite) TestAppendRange() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer j.Close()
+
+	added := suite.fill(j)
+	assert.Equal(suite.T(), []uint64{1, 2, 3}, seqs(added))
+	assert.Equal(suite.T(), 3, j.Len())
+
+	// Записи возвращаются по времени, а не по порядку добавления
+	all, err := j.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{1, 3, 2}, seqs(all))
+
+	march5, err := j.Range(day0, day0.AddDate(0, 0, 1))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{1, 3}, seqs(march5))
+	assert.Equal(suite.T(), spentcalories.METModel, march5[1].Training.Model)
+	assert.Equal(suite.T(), 8000, march5[1].Training.Steps)
+
+	// Конец диапазона не включается
+	none, err := j.Range(day0.Add(12*time.Hour+time.Second), day0.Add(33*time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), none)
+
+	// Запись без времени получает момент добавления
+	before := time.Now()
+	noTime, err := j.Append(Day(daysteps.DayActionResult{Steps: 100, Duration: time.Minute}))
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), noTime[0].Time.Before(before))
+
+	_, err = j.Append(Entry{Kind: "weight"})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *JournalTestSuite) TestReopen() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+	assert.NoError(suite.T(), j.Close())
+
+	j, err = Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer j.Close()
+	assert.Zero(suite.T(), j.Truncated())
+
+	all, err := j.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{1, 3, 2}, seqs(all))
+
+	// Нумерация продолжается после повторного открытия
+	next, err := j.Append(Day(daysteps.DayActionResult{Start: day0, Steps: 1, Duration: time.Minute}))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(4), next[0].Seq)
+
+	_, err = j.Append()
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *JournalTestSuite) TestTornWrite() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+	assert.NoError(suite.T(), j.Close())
+	info, err := os.Stat(suite.path)
+	assert.NoError(suite.T(), err)
+
+	tails := map[string]string{
+		"нет перевода строки":   `0123abcd {"seq":4,"kind":"day"`,
+		"неверная сумма":        "0123abcd {\"seq\":4,\"kind\":\"day\",\"time\":\"2024-03-07T00:00:00Z\"}\n",
+		"обрыв в сумме":         "01",
+		"только перевод строки": "\n",
+	}
+	for name, tail := range tails {
+		suite.Run(name, func() {
+			f, err := os.OpenFile(suite.path, os.O_APPEND|os.O_WRONLY, 0o644)
+			assert.NoError(suite.T(), err)
+			_, err = f.WriteString(tail)
+			assert.NoError(suite.T(), err)
+			assert.NoError(suite.T(), f.Close())
+
+			j, err := Open(suite.path)
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), int64(len(tail)), j.Truncated())
+			assert.Equal(suite.T(), 3, j.Len())
+			assert.NoError(suite.T(), j.Close())
+
+			after, err := os.Stat(suite.path)
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), info.Size(), after.Size())
+		})
+	}
+}
+
+func (suite *JournalTestSuite) TestCorrupt() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+	assert.NoError(suite.T(), j.Close())
+
+	data, err := os.ReadFile(suite.path)
+	assert.NoError(suite.T(), err)
+
+	// Испорченная запись в середине - это не сбой записи, журнал не открывается и не обрезается
+	broken := append([]byte(nil), data...)
+	broken[20] ^= 0xff
+	assert.NoError(suite.T(), os.WriteFile(suite.path, broken, 0o644))
+	_, err = Open(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrCorrupt)
+	after, err := os.ReadFile(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), broken, after)
+
+	// Сумма верна, но запись не разбирается - тоже ошибка, а не обрезка хвоста
+	payload := []byte(`{"seq":4,"kind":"day","day":{"start":42}}`)
+	line := fmt.Appendf(nil, "%08x %s\n", crc32.Checksum(payload, crcTable), payload)
+	assert.NoError(suite.T(), os.WriteFile(suite.path, append(data, line...), 0o644))
+	_, err = Open(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrCorrupt)
+}
+
+func (suite *JournalTestSuite) TestDeleteCompact() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer j.Close()
+	suite.fill(j)
+
+	assert.NoError(suite.T(), j.Delete(1))
+	assert.Error(suite.T(), j.Delete(1))
+	assert.Error(suite.T(), j.Delete(42))
+	assert.Equal(suite.T(), 2, j.Len())
+
+	all, err := j.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{3, 2}, seqs(all))
+
+	before, err := os.Stat(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), j.Compact())
+	after, err := os.Stat(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.Less(suite.T(), after.Size(), before.Size())
+
+	// После сжатия журнал читается и дописывается
+	all, err = j.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{3, 2}, seqs(all))
+	next, err := j.Append(Day(daysteps.DayActionResult{Start: day0, Steps: 1, Duration: time.Minute}))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(5), next[0].Seq)
+	assert.NoError(suite.T(), j.Close())
+
+	reopened, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer reopened.Close()
+	all, err = reopened.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{5, 3, 2}, seqs(all))
+	_, err = os.Stat(suite.path + ".compact")
+	assert.ErrorIs(suite.T(), err, os.ErrNotExist)
+}
+
+func (suite *JournalTestSuite) TestSeqNotReused() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+
+	// Удалена запись с наибольшим номером, отметка об удалении получает номер 4
+	assert.NoError(suite.T(), j.Delete(3))
+	assert.NoError(suite.T(), j.Compact())
+	assert.NoError(suite.T(), j.Close())
+
+	// После сжатия и повторного открытия номера 3 и 4 не выдаются снова
+	reopened, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer reopened.Close()
+	assert.Equal(suite.T(), 2, reopened.Len())
+	next, err := reopened.Append(Day(daysteps.DayActionResult{Start: day0, Steps: 1, Duration: time.Minute}))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(5), next[0].Seq)
+	all, err := reopened.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint64{5, 1, 2}, seqs(all))
+
+	// Отметка о номере не копится: следующее сжатие без удалений ее не пишет
+	assert.NoError(suite.T(), reopened.Compact())
+	data, err := os.ReadFile(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(data), `"kind":"seq"`)
+}
+
+func (suite *JournalTestSuite) TestConcurrentDelete() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+
+	// Одну запись удаляют параллельно с несколькими сжатиями
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	deleted := 0
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if j.Delete(1) == nil {
+				mu.Lock()
+				deleted++
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(suite.T(), j.Compact())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(suite.T(), 1, deleted)
+	assert.NoError(suite.T(), j.Close())
+
+	// В файле не больше одной отметки об удалении, и она относится к живой в файле записи
+	data, err := os.ReadFile(suite.path)
+	assert.NoError(suite.T(), err)
+	tombstones := strings.Count(string(data), `"kind":"delete"`)
+	assert.LessOrEqual(suite.T(), tombstones, 1)
+	if tombstones == 1 {
+		assert.Contains(suite.T(), string(data), `"seq":1,`)
+	}
+	reopened, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer reopened.Close()
+	assert.Equal(suite.T(), 2, reopened.Len())
+}
+
+func (suite *JournalTestSuite) TestConcurrentAppend() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range 10 {
+				_, err := j.Append(Day(daysteps.DayActionResult{Start: day0.Add(time.Duration(i*10+k) * time.Minute), Steps: 1, Duration: time.Minute}))
+				assert.NoError(suite.T(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(suite.T(), j.Close())
+
+	_, err = j.Append(Day(daysteps.DayActionResult{}))
+	assert.ErrorIs(suite.T(), err, os.ErrClosed)
+
+	reopened, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	defer reopened.Close()
+	assert.Equal(suite.T(), 80, reopened.Len())
+	assert.Zero(suite.T(), reopened.Truncated())
+}
+
+func (suite *JournalTestSuite) TestLock() {
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+
+	// Пока журнал открыт на запись, его нельзя открыть ни на запись, ни на чтение
+	_, err = Open(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrLocked)
+	_, err = OpenReadOnly(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrLocked)
+
+	// Блокировка переходит на сжатый файл
+	assert.NoError(suite.T(), j.Compact())
+	_, err = Open(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrLocked)
+	assert.NoError(suite.T(), j.Close())
+
+	// Читателей может быть несколько, но писатель ждет, пока они закроют журнал
+	r1, err := OpenReadOnly(suite.path)
+	assert.NoError(suite.T(), err)
+	r2, err := OpenReadOnly(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, r2.Len())
+	_, err = Open(suite.path)
+	assert.ErrorIs(suite.T(), err, ErrLocked)
+	assert.NoError(suite.T(), r1.Close())
+	assert.NoError(suite.T(), r2.Close())
+
+	j, err = Open(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), j.Close())
+}
+
+func (suite *JournalTestSuite) TestReadOnly() {
+	_, err := OpenReadOnly(suite.path)
+	assert.ErrorIs(suite.T(), err, os.ErrNotExist)
+
+	j, err := Open(suite.path)
+	assert.NoError(suite.T(), err)
+	suite.fill(j)
+	assert.NoError(suite.T(), j.Close())
+
+	// Недописанный хвост пропускается, но файл не меняется
+	tail := `0123abcd {"seq":4,"kind":"day"`
+	f, err := os.OpenFile(suite.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(suite.T(), err)
+	_, err = f.WriteString(tail)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), f.Close())
+	before, err := os.Stat(suite.path)
+	assert.NoError(suite.T(), err)
+
+	r, err := OpenReadOnly(suite.path)
+	assert.NoError(suite.T(), err)
+	entries, err := r.Range(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), entries, 3)
+	assert.Equal(suite.T(), int64(0), r.Truncated())
+
+	_, err = r.Append(Day(daysteps.DayActionResult{Steps: 100, Duration: time.Minute}))
+	assert.ErrorIs(suite.T(), err, ErrReadOnly)
+	assert.ErrorIs(suite.T(), r.Delete(entries[0].Seq), ErrReadOnly)
+	assert.ErrorIs(suite.T(), r.Compact(), ErrReadOnly)
+	assert.NoError(suite.T(), r.Close())
+
+	after, err := os.Stat(suite.path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), before.Size(), after.Size())
+}
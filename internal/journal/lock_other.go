@@ -0,0 +1,18 @@
+//go:build !unix
+
+package journal
+
+import "os"
+
+type lockMode int
+
+const (
+	lockShared lockMode = iota
+	lockExclusive
+)
+
+// lock на платформах без flock ничего не делает: журнал, как и раньше,
+// не должен одновременно открываться несколькими процессами
+func lock(*os.File, lockMode) error {
+	return nil
+}
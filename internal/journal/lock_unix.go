@@ -0,0 +1,26 @@
+//go:build unix
+
+package journal
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+type lockMode int
+
+const (
+	lockShared    lockMode = syscall.LOCK_SH
+	lockExclusive lockMode = syscall.LOCK_EX
+)
+
+// lock ставит на файл блокировку flock, не дожидаясь ее освобождения.
+// Блокировка снимается при закрытии файла
+func lock(f *os.File, mode lockMode) error {
+	err := syscall.Flock(int(f.Fd()), int(mode)|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+	return err
+}
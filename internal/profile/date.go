@@ -0,0 +1,66 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date - календарная дата без времени, в файлах профиля записывается как ГГГГ-ММ-ДД
+type Date struct {
+	time.Time
+}
+
+// NewDate возвращает дату year-month-day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate разбирает дату в формате ГГГГ-ММ-ДД
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("неверная дата %q: ожидается ГГГГ-ММ-ДД", s)
+	}
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.DateOnly)
+}
+
+// MarshalText кодирует дату в формате ГГГГ-ММ-ДД, используется для YAML
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText разбирает дату в формате ГГГГ-ММ-ДД, пустая строка - нулевая дата
+func (d *Date) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON заменяет метод time.Time, который записывает дату со временем
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON разбирает дату в формате ГГГГ-ММ-ДД
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,124 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Sex - пол пользователя, влияет на длину шага
+type Sex string
+
+const (
+	SexUnknown Sex = ""
+	Male       Sex = "male"
+	Female     Sex = "female"
+)
+
+// Profile хранит параметры пользователя, которые нужны для расчетов
+type Profile struct {
+	// Вес в килограммах
+	Weight float64 `json:"weight" yaml:"weight"`
+	// Рост в метрах
+	Height float64 `json:"height" yaml:"height"`
+	// Дата рождения, по ней считается возраст
+	BirthDate Date `json:"birth_date,omitzero" yaml:"birth_date,omitempty"`
+	// Пол, необязательный
+	Sex Sex `json:"sex,omitempty" yaml:"sex,omitempty"`
+	// Измеренная длина шага в метрах, 0 если не задана
+	Stride float64 `json:"stride,omitempty" yaml:"stride,omitempty"`
+	// Максимальный пульс, 0 если не задан: тогда он оценивается по возрасту
+	MaxHeartRate float64 `json:"max_heart_rate,omitempty" yaml:"max_heart_rate,omitempty"`
+	// Пульс покоя, нужен для зон по резерву пульса
+	RestingHeartRate float64 `json:"resting_heart_rate,omitempty" yaml:"resting_heart_rate,omitempty"`
+	// Способ расчета и границы пульсовых зон, nil - зоны по умолчанию
+	Zones *hrzone.Config `json:"zones,omitempty" yaml:"zones,omitempty"`
+	// Система единиц и язык вывода по умолчанию
+	Units  units.System `json:"units,omitempty" yaml:"units,omitempty"`
+	Locale i18n.Locale  `json:"locale,omitempty" yaml:"locale,omitempty"`
+}
+
+// Age возвращает возраст в полных годах на момент at или 0, если дата рождения не задана
+func (p Profile) Age(at time.Time) int {
+	if p.BirthDate.IsZero() {
+		return 0
+	}
+	by, bm, bd := p.BirthDate.Date()
+	y, m, d := at.Date()
+	age := y - by
+	if m < bm || m == bm && d < bd {
+		age--
+	}
+	return max(age, 0)
+}
+
+// HeartRateZones рассчитывает пульсовые зоны для возраста age. Если максимальный
+// пульс не задан, он оценивается по возрасту, а без возраста зоны не считаются
+func (p Profile) HeartRateZones(age int) (hrzone.Zones, bool, error) {
+	maxHR := p.MaxHeartRate
+	if maxHR == 0 {
+		if age <= 0 {
+			return hrzone.Zones{}, false, nil
+		}
+		maxHR = hrzone.EstimateMax(age)
+	}
+
+	var cfg hrzone.Config
+	if p.Zones != nil {
+		cfg = *p.Zones
+	}
+	zones, err := hrzone.New(cfg, maxHR, p.RestingHeartRate)
+	if err != nil {
+		return hrzone.Zones{}, false, err
+	}
+	return zones, true, nil
+}
+
+// Load читает профиль из файла. Файлы .yaml и .yml читаются как YAML, остальные - как JSON
+func Load(path string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	var p Profile
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, &p)
+	} else {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("профиль %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save записывает профиль в файл в формате по расширению, как в Load
+func Save(path string, p Profile) error {
+	var data []byte
+	var err error
+	if isYAML(path) {
+		data, err = yaml.Marshal(p)
+	} else {
+		data, err = json.MarshalIndent(p, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
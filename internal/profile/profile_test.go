@@ -0,0 +1,123 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProfileTestSuite struct {
+	suite.Suite
+}
+
+func TestProfileSuite(t *testing.T) {
+	suite.Run(t, new(ProfileTestSuite))
+}
+
+func (suite *ProfileTestSuite) TestSaveLoad() {
+	path := filepath.Join(suite.T().TempDir(), "profile.json")
+	want := Profile{Weight: 84.6, Height: 1.87}
+
+	assert.NoError(suite.T(), Save(path, want))
+	got, err := Load(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), want, got)
+}
+
+func (suite *ProfileTestSuite) TestLoadErrors() {
+	dir := suite.T().TempDir()
+
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.Error(suite.T(), err)
+
+	path := filepath.Join(dir, "broken.json")
+	assert.NoError(suite.T(), os.WriteFile(path, []byte("{"), 0o644))
+	_, err = Load(path)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ProfileTestSuite) TestHeartRateZones() {
+	path := filepath.Join(suite.T().TempDir(), "profile.json")
+	want := Profile{
+		Weight:           84.6,
+		Height:           1.87,
+		MaxHeartRate:     190,
+		RestingHeartRate: 55,
+		Zones:            &hrzone.Config{Method: hrzone.Reserve, Bounds: []float64{0.55, 0.7, 0.8, 0.87, 0.93}},
+	}
+
+	assert.NoError(suite.T(), Save(path, want))
+	got, err := Load(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), want, got)
+}
+
+func (suite *ProfileTestSuite) TestSaveLoadYAML() {
+	dir := suite.T().TempDir()
+	want := Profile{
+		Weight:       84.6,
+		Height:       1.87,
+		BirthDate:    NewDate(1990, time.May, 17),
+		Sex:          Male,
+		Stride:       0.78,
+		MaxHeartRate: 190,
+		Zones:        &hrzone.Config{Method: hrzone.MaxHR},
+		Units:        units.Imperial,
+		Locale:       i18n.English,
+	}
+
+	for _, name := range []string{"profile.yaml", "profile.yml", "profile.json"} {
+		suite.Run(name, func() {
+			path := filepath.Join(dir, name)
+			assert.NoError(suite.T(), Save(path, want))
+			got, err := Load(path)
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), want, got)
+
+			data, err := os.ReadFile(path)
+			assert.NoError(suite.T(), err)
+			assert.Contains(suite.T(), string(data), "1990-05-17")
+		})
+	}
+
+	path := filepath.Join(dir, "broken.yaml")
+	assert.NoError(suite.T(), os.WriteFile(path, []byte("weight: [\n"), 0o644))
+	_, err := Load(path)
+	assert.Error(suite.T(), err)
+
+	path = filepath.Join(dir, "date.json")
+	assert.NoError(suite.T(), os.WriteFile(path, []byte(`{"birth_date": "17.05.1990"}`), 0o644))
+	_, err = Load(path)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ProfileTestSuite) TestAge() {
+	p := Profile{BirthDate: NewDate(1990, time.May, 17)}
+
+	assert.Equal(suite.T(), 33, p.Age(time.Date(2024, time.May, 16, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(suite.T(), 34, p.Age(time.Date(2024, time.May, 17, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(suite.T(), 0, Profile{}.Age(time.Now()))
+}
+
+func (suite *ProfileTestSuite) TestHeartRateZonesFromProfile() {
+	// Без максимального пульса и возраста зон нет
+	_, ok, err := Profile{}.HeartRateZones(0)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	// Максимальный пульс оценивается по возрасту
+	zones, ok, err := Profile{}.HeartRateZones(30)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	assert.InDelta(suite.T(), 187.0, zones[hrzone.Count-1].High, 1e-9)
+
+	_, _, err = Profile{MaxHeartRate: 190, Zones: &hrzone.Config{Method: hrzone.Reserve}}.HeartRateZones(30)
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,127 @@
+package profile
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Допустимые значения профиля. Границы заведомо шире реальных, они отсекают
+// опечатки вроде веса 8460 вместо 84.60 или роста в сантиметрах вместо метров
+const (
+	MinWeight       = 20.0  // кг
+	MaxWeight       = 400.0 // кг
+	MinHeight       = 0.5   // м
+	MaxHeight       = 2.5   // м
+	MinStride       = 0.2   // м, те же границы использует stride.Calibrate
+	MaxStride       = 2.0   // м
+	MinMaxHeartRate = 100.0 // уд/мин
+	MaxMaxHeartRate = 250.0 // уд/мин
+	MinRestingHR    = 25.0  // уд/мин
+	MaxRestingHR    = 120.0 // уд/мин
+	MaxAge          = 120   // лет
+)
+
+// Поля профиля, к которым может относиться ошибка проверки
+const (
+	FieldWeight           = "weight"
+	FieldHeight           = "height"
+	FieldBirthDate        = "birth_date"
+	FieldSex              = "sex"
+	FieldStride           = "stride"
+	FieldMaxHeartRate     = "max_heart_rate"
+	FieldRestingHeartRate = "resting_heart_rate"
+	FieldZones            = "zones"
+	FieldUnits            = "units"
+	FieldLocale           = "locale"
+)
+
+// ValidationError описывает недопустимое значение поля профиля, извлекается через errors.As
+type ValidationError struct {
+	// Поле профиля, одна из констант Field*
+	Field string
+	// Ключ причины в каталоге i18n и аргументы для перевода
+	Code string
+	Args []any
+}
+
+func newValidationError(field, code string, args ...any) *ValidationError {
+	return &ValidationError{Field: field, Code: code, Args: args}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Localize(i18n.Default)
+}
+
+// Localize возвращает текст ошибки на языке loc
+func (e *ValidationError) Localize(loc i18n.Locale) string {
+	return loc.T("profile.invalid", e.Field, loc.T(e.Code, e.Args...))
+}
+
+// Validate проверяет профиль: вес и рост обязательны, остальные поля проверяются,
+// только если заданы. Возвращает первую найденную ошибку
+func (p Profile) Validate() error {
+	return p.validate(time.Now())
+}
+
+func (p Profile) validate(now time.Time) error {
+	if err := checkRange(FieldWeight, p.Weight, MinWeight, MaxWeight); err != nil {
+		return err
+	}
+	if err := checkRange(FieldHeight, p.Height, MinHeight, MaxHeight); err != nil {
+		return err
+	}
+	if !p.BirthDate.IsZero() {
+		if p.BirthDate.After(now) {
+			return newValidationError(FieldBirthDate, "profile.birth_date_future", p.BirthDate.String())
+		}
+		if age := p.Age(now); age > MaxAge {
+			return newValidationError(FieldBirthDate, "profile.age_range", age, MaxAge)
+		}
+	}
+	if p.Sex != SexUnknown && p.Sex != Male && p.Sex != Female {
+		return newValidationError(FieldSex, "profile.sex", string(p.Sex))
+	}
+	if p.Stride != 0 {
+		if err := checkRange(FieldStride, p.Stride, MinStride, MaxStride); err != nil {
+			return err
+		}
+	}
+	if p.MaxHeartRate != 0 {
+		if err := checkRange(FieldMaxHeartRate, p.MaxHeartRate, MinMaxHeartRate, MaxMaxHeartRate); err != nil {
+			return err
+		}
+	}
+	if p.RestingHeartRate != 0 {
+		if err := checkRange(FieldRestingHeartRate, p.RestingHeartRate, MinRestingHR, MaxRestingHR); err != nil {
+			return err
+		}
+		if p.MaxHeartRate != 0 && p.RestingHeartRate >= p.MaxHeartRate {
+			return newValidationError(FieldRestingHeartRate, "profile.resting_hr", p.RestingHeartRate, p.MaxHeartRate)
+		}
+	}
+	if p.Zones != nil {
+		if err := p.Zones.Validate(); err != nil {
+			return newValidationError(FieldZones, "profile.zones", err.Error())
+		}
+	}
+	if p.Units != "" {
+		if _, err := units.ParseSystem(string(p.Units)); err != nil {
+			return newValidationError(FieldUnits, "profile.units", string(p.Units))
+		}
+	}
+	if p.Locale != "" {
+		if _, err := i18n.Parse(string(p.Locale)); err != nil {
+			return newValidationError(FieldLocale, "profile.locale", string(p.Locale))
+		}
+	}
+	return nil
+}
+
+func checkRange(field string, value, lo, hi float64) error {
+	if value < lo || value > hi {
+		return newValidationError(field, "profile.range", value, lo, hi)
+	}
+	return nil
+}
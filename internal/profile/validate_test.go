@@ -0,0 +1,74 @@
+package profile
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *ProfileTestSuite) TestValidate() {
+	now := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	valid := Profile{Weight: 84.6, Height: 1.87}
+
+	tests := []struct {
+		name      string
+		change    func(p *Profile)
+		wantField string
+	}{
+		{name: "минимальный профиль", change: func(*Profile) {}},
+		{name: "полный профиль", change: func(p *Profile) {
+			p.BirthDate = NewDate(1990, time.May, 17)
+			p.Sex = Female
+			p.Stride = 0.7
+			p.MaxHeartRate = 185
+			p.RestingHeartRate = 55
+			p.Zones = &hrzone.Config{Method: hrzone.Reserve}
+			p.Units = units.Metric
+			p.Locale = "en-US"
+		}},
+		{name: "вес вместо 84.60", change: func(p *Profile) { p.Weight = 8460 }, wantField: FieldWeight},
+		{name: "нет веса", change: func(p *Profile) { p.Weight = 0 }, wantField: FieldWeight},
+		{name: "отрицательный вес", change: func(p *Profile) { p.Weight = -84.6 }, wantField: FieldWeight},
+		{name: "рост в сантиметрах", change: func(p *Profile) { p.Height = 187 }, wantField: FieldHeight},
+		{name: "дата рождения в будущем", change: func(p *Profile) { p.BirthDate = NewDate(2030, time.January, 1) }, wantField: FieldBirthDate},
+		{name: "слишком большой возраст", change: func(p *Profile) { p.BirthDate = NewDate(1850, time.January, 1) }, wantField: FieldBirthDate},
+		{name: "неизвестный пол", change: func(p *Profile) { p.Sex = "other" }, wantField: FieldSex},
+		{name: "шаг в сантиметрах", change: func(p *Profile) { p.Stride = 78 }, wantField: FieldStride},
+		{name: "максимальный пульс", change: func(p *Profile) { p.MaxHeartRate = 400 }, wantField: FieldMaxHeartRate},
+		{name: "пульс покоя", change: func(p *Profile) { p.RestingHeartRate = 10 }, wantField: FieldRestingHeartRate},
+		{name: "пульс покоя выше максимального", change: func(p *Profile) {
+			p.MaxHeartRate = 110
+			p.RestingHeartRate = 115
+		}, wantField: FieldRestingHeartRate},
+		{name: "зоны", change: func(p *Profile) { p.Zones = &hrzone.Config{Method: "lthr"} }, wantField: FieldZones},
+		{name: "единицы", change: func(p *Profile) { p.Units = "si" }, wantField: FieldUnits},
+		{name: "язык", change: func(p *Profile) { p.Locale = "de" }, wantField: FieldLocale},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			p := valid
+			tt.change(&p)
+			err := p.validate(now)
+			if tt.wantField == "" {
+				assert.NoError(suite.T(), err)
+				return
+			}
+			var verr *ValidationError
+			if assert.ErrorAs(suite.T(), err, &verr) {
+				assert.Equal(suite.T(), tt.wantField, verr.Field)
+			}
+		})
+	}
+}
+
+func (suite *ProfileTestSuite) TestValidationErrorLocalize() {
+	err := Profile{Weight: 8460, Height: 1.87}.Validate()
+
+	assert.EqualError(suite.T(), err, "профиль: weight: значение 8460 вне допустимого диапазона от 20 до 400")
+	assert.Equal(suite.T(), "profile: weight: value 8460 is outside the allowed range 20 to 400",
+		i18n.Localize(err, i18n.English))
+}
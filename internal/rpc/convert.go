@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/rpc/trackerpb"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+var (
+	errNoProfile = errors.New("не задан профиль")
+	errHeartRate = i18n.NewError("err.heart_rate_positive")
+)
+
+// toProfile переводит профиль из запроса и проверяет его
+func toProfile(pb *trackerpb.Profile) (profile.Profile, error) {
+	if pb == nil {
+		return profile.Profile{}, errNoProfile
+	}
+	p := profile.Profile{
+		Weight:           pb.GetWeightKg(),
+		Height:           pb.GetHeightM(),
+		Stride:           pb.GetStrideM(),
+		MaxHeartRate:     pb.GetMaxHeartRate(),
+		RestingHeartRate: pb.GetRestingHeartRate(),
+	}
+	switch pb.GetSex() {
+	case trackerpb.Sex_SEX_MALE:
+		p.Sex = profile.Male
+	case trackerpb.Sex_SEX_FEMALE:
+		p.Sex = profile.Female
+	}
+	if value := pb.GetBirthDate(); value != "" {
+		var err error
+		if p.BirthDate, err = profile.ParseDate(value); err != nil {
+			return profile.Profile{}, err
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return profile.Profile{}, err
+	}
+	return p, nil
+}
+
+var models = map[trackerpb.CalorieModel]spentcalories.CalorieModel{
+	trackerpb.CalorieModel_CALORIE_MODEL_UNSPECIFIED: spentcalories.SpeedModel,
+	trackerpb.CalorieModel_CALORIE_MODEL_SPEED:       spentcalories.SpeedModel,
+	trackerpb.CalorieModel_CALORIE_MODEL_MET:         spentcalories.METModel,
+	trackerpb.CalorieModel_CALORIE_MODEL_HEART_RATE:  spentcalories.HeartRateModel,
+}
+
+func toModel(m trackerpb.CalorieModel) (spentcalories.CalorieModel, error) {
+	model, ok := models[m]
+	if !ok {
+		return 0, fmt.Errorf("неизвестная модель расчета калорий %d", m)
+	}
+	return model, nil
+}
+
+func fromModel(m spentcalories.CalorieModel) trackerpb.CalorieModel {
+	for pb, model := range models {
+		if model == m && pb != trackerpb.CalorieModel_CALORIE_MODEL_UNSPECIFIED {
+			return pb
+		}
+	}
+	return trackerpb.CalorieModel_CALORIE_MODEL_UNSPECIFIED
+}
+
+// toWorkout переводит тренировку по полям. Значения проверяются здесь, чтобы
+// ошибки указывали поле и колонку так же, как при разборе строки
+func toWorkout(t *trackerpb.Training) (spentcalories.Workout, error) {
+	var w spentcalories.Workout
+	col := 0
+	if start := t.GetStart(); start != nil {
+		w.Start = start.AsTime()
+		col++
+	}
+
+	if t.GetSteps() <= 0 {
+		return w, spentcalories.NewParseError(spentcalories.FieldSteps, strconv.Itoa(int(t.GetSteps())), col,
+			spentcalories.ErrSteps, "reason.steps_positive")
+	}
+	w.Steps = int(t.GetSteps())
+	col++
+
+	if strings.TrimSpace(t.GetActivity()) == "" {
+		return w, spentcalories.NewParseError(spentcalories.FieldActivity, t.GetActivity(), col,
+			spentcalories.ErrActivity, "reason.activity_empty")
+	}
+	w.Activity = t.GetActivity()
+	col++
+
+	w.Duration = t.GetDuration().AsDuration()
+	if w.Duration <= 0 {
+		return w, spentcalories.NewParseError(spentcalories.FieldDuration, w.Duration.String(), col,
+			spentcalories.ErrDuration, "reason.duration_positive")
+	}
+
+	if t.GetHeartRateBpm() < 0 {
+		return w, errHeartRate
+	}
+	w.HeartRate = t.GetHeartRateBpm()
+	return w, nil
+}
+
+// startOf возвращает время начала или нулевое время, если оно не задано
+func startOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// timestamp возвращает время или nil для нулевого времени
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTraining(r spentcalories.TrainingResult) *trackerpb.TrainingResult {
+	return &trackerpb.TrainingResult{
+		Start:      timestamp(r.Start),
+		Activity:   r.Activity,
+		Steps:      int32(r.Steps),
+		Duration:   durationpb.New(r.Duration),
+		DistanceKm: r.Distance,
+		SpeedKmh:   r.Speed,
+		Calories:   r.Calories,
+		Model:      fromModel(r.Model),
+	}
+}
+
+func fromDay(r daysteps.DayActionResult) *trackerpb.DayActivityResult {
+	return &trackerpb.DayActivityResult{
+		Start:      timestamp(r.Start),
+		Steps:      int32(r.Steps),
+		Duration:   durationpb.New(r.Duration),
+		DistanceKm: r.Distance,
+		SpeedKmh:   r.Speed,
+		Calories:   r.Calories,
+	}
+}
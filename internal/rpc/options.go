@@ -0,0 +1,26 @@
+package rpc
+
+import "time"
+
+// Option настраивает Server
+type Option func(*options)
+
+type options struct {
+	location *time.Location
+}
+
+func newOptions(opts []Option) options {
+	o := options{location: time.Local}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLocation задает часовой пояс, в котором разбирается дата запроса.
+// По умолчанию используется time.Local
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
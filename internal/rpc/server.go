@@ -0,0 +1,193 @@
+package rpc
+
+//go:generate sh -c "cd ../.. && buf generate"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/rpc/trackerpb"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Server реализует сервис CalorieEngine. Записи считаются так же, как
+// командами day и training, по профилю из каждого запроса
+type Server struct {
+	trackerpb.UnimplementedCalorieEngineServer
+	opts options
+}
+
+var _ trackerpb.CalorieEngineServer = (*Server)(nil)
+
+// New создает реализацию сервиса
+func New(opts ...Option) *Server {
+	return &Server{opts: newOptions(opts)}
+}
+
+// Register регистрирует сервис на gRPC-сервере s
+func Register(s grpc.ServiceRegistrar, opts ...Option) {
+	trackerpb.RegisterCalorieEngineServer(s, New(opts...))
+}
+
+func (s *Server) ComputeTraining(ctx context.Context, req *trackerpb.ComputeTrainingRequest) (*trackerpb.ComputeTrainingResponse, error) {
+	r, err := s.training(req)
+	if err != nil {
+		return nil, invalidArgument(err, locale(ctx))
+	}
+	return &trackerpb.ComputeTrainingResponse{Result: fromTraining(r)}, nil
+}
+
+func (s *Server) ComputeDayActivity(ctx context.Context, req *trackerpb.ComputeDayActivityRequest) (*trackerpb.ComputeDayActivityResponse, error) {
+	r, err := s.day(req)
+	if err != nil {
+		return nil, invalidArgument(err, locale(ctx))
+	}
+	return &trackerpb.ComputeDayActivityResponse{Result: fromDay(r)}, nil
+}
+
+func (s *Server) BatchCompute(stream grpc.BidiStreamingServer[trackerpb.BatchComputeRequest, trackerpb.BatchComputeResponse]) error {
+	loc := locale(stream.Context())
+	for index := uint64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &trackerpb.BatchComputeResponse{Index: index}
+		switch item := req.GetItem().(type) {
+		case *trackerpb.BatchComputeRequest_Training:
+			var r spentcalories.TrainingResult
+			if r, err = s.training(item.Training); err == nil {
+				resp.Result = &trackerpb.BatchComputeResponse_Training{Training: fromTraining(r)}
+			}
+		case *trackerpb.BatchComputeRequest_Day:
+			var r daysteps.DayActionResult
+			if r, err = s.day(item.Day); err == nil {
+				resp.Result = &trackerpb.BatchComputeResponse_Day{Day: fromDay(r)}
+			}
+		default:
+			err = errors.New("пустой запрос: ожидается training или day")
+		}
+		if err != nil {
+			resp.Result = &trackerpb.BatchComputeResponse_Error{Error: recordError(err, loc)}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) training(req *trackerpb.ComputeTrainingRequest) (spentcalories.TrainingResult, error) {
+	p, err := toProfile(req.GetProfile())
+	if err != nil {
+		return spentcalories.TrainingResult{}, err
+	}
+	model, err := toModel(req.GetModel())
+	if err != nil {
+		return spentcalories.TrainingResult{}, err
+	}
+	date, err := s.date(req.GetDate())
+	if err != nil {
+		return spentcalories.TrainingResult{}, err
+	}
+	opts := []spentcalories.Option{spentcalories.WithDate(date), spentcalories.WithModel(model)}
+	if t := req.GetTraining(); t != nil {
+		w, err := toWorkout(t)
+		if err != nil {
+			return spentcalories.TrainingResult{}, err
+		}
+		return spentcalories.ComputeFor(w, p, opts...)
+	}
+	return spentcalories.TrainingFor(req.GetPacket(), p, opts...)
+}
+
+func (s *Server) day(req *trackerpb.ComputeDayActivityRequest) (daysteps.DayActionResult, error) {
+	p, err := toProfile(req.GetProfile())
+	if err != nil {
+		return daysteps.DayActionResult{}, err
+	}
+	date, err := s.date(req.GetDate())
+	if err != nil {
+		return daysteps.DayActionResult{}, err
+	}
+	d := req.GetDay()
+	if d == nil {
+		return daysteps.DayActionFor(req.GetPacket(), p, daysteps.WithDate(date))
+	}
+	return daysteps.ComputeDayFor(startOf(d.GetStart()), int(d.GetSteps()), d.GetDuration().AsDuration(), p,
+		daysteps.WithDate(date))
+}
+
+// date разбирает дату ГГГГ-ММ-ДД в часовом поясе сервера, пустая строка - нулевое время
+func (s *Server) date(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseInLocation(time.DateOnly, value, s.opts.location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата %q: ожидается ГГГГ-ММ-ДД", value)
+	}
+	return d, nil
+}
+
+// locale возвращает язык из метаданных accept-language, по умолчанию i18n.Default
+func locale(ctx context.Context) i18n.Locale {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("accept-language") {
+		if loc, err := i18n.Parse(value); err == nil {
+			return loc
+		}
+	}
+	return i18n.Default
+}
+
+// recordError описывает ошибку записи на языке loc
+func recordError(err error, loc i18n.Locale) *trackerpb.RecordError {
+	re := &trackerpb.RecordError{Column: -1, Message: i18n.Localize(err, loc)}
+	var pe *spentcalories.ParseError
+	var ve *profile.ValidationError
+	switch {
+	case errors.As(err, &pe):
+		re.Field, re.Value, re.Column, re.Code = pe.Field, pe.Value, int32(pe.Column), pe.Code
+	case errors.As(err, &ve):
+		re.Field, re.Code = "profile."+ve.Field, ve.Code
+	}
+	return re
+}
+
+// invalidArgument возвращает статус InvalidArgument, а для ошибок разбора
+// и проверки профиля добавляет к нему BadRequest с полем и кодом причины
+func invalidArgument(err error, loc i18n.Locale) error {
+	re := recordError(err, loc)
+	st := status.New(codes.InvalidArgument, re.Message)
+	if re.Field == "" {
+		return st.Err()
+	}
+	detailed, derr := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       re.Field,
+			Description: re.Message,
+			Reason:      re.Code,
+		}},
+	})
+	if derr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
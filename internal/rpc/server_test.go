@@ -0,0 +1,326 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/rpc/trackerpb"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RPCTestSuite struct {
+	suite.Suite
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client trackerpb.CalorieEngineClient
+}
+
+func TestRPCSuite(t *testing.T) {
+	suite.Run(t, new(RPCTestSuite))
+}
+
+var msk = time.FixedZone("MSK", 3*60*60)
+
+func (suite *RPCTestSuite) SetupTest() {
+	lis := bufconn.Listen(1 << 20)
+	suite.server = grpc.NewServer()
+	Register(suite.server, WithLocation(msk))
+	go suite.server.Serve(lis)
+
+	var err error
+	suite.conn, err = grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(suite.T(), err)
+	suite.client = trackerpb.NewCalorieEngineClient(suite.conn)
+}
+
+func (suite *RPCTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.conn.Close())
+	suite.server.Stop()
+}
+
+var testProfile = &trackerpb.Profile{WeightKg: 75, HeightM: 1.75, BirthDate: "1994-01-01", Sex: trackerpb.Sex_SEX_MALE}
+
+func (suite *RPCTestSuite) profile() profile.Profile {
+	p, err := toProfile(testProfile)
+	assert.NoError(suite.T(), err)
+	return p
+}
+
+func (suite *RPCTestSuite) TestComputeTraining() {
+	ctx := context.Background()
+	resp, err := suite.client.ComputeTraining(ctx, &trackerpb.ComputeTrainingRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeTrainingRequest_Packet{Packet: "08:00,8000,Бег,1h"},
+		Date:    "2024-03-05",
+		Model:   trackerpb.CalorieModel_CALORIE_MODEL_MET,
+	})
+	assert.NoError(suite.T(), err)
+
+	start := time.Date(2024, time.March, 5, 8, 0, 0, 0, msk)
+	want, err := spentcalories.TrainingFor("08:00,8000,Бег,1h", suite.profile(),
+		spentcalories.WithDate(time.Date(2024, time.March, 5, 0, 0, 0, 0, msk)), spentcalories.WithModel(spentcalories.METModel))
+	assert.NoError(suite.T(), err)
+
+	r := resp.GetResult()
+	assert.True(suite.T(), start.Equal(r.GetStart().AsTime()))
+	assert.Equal(suite.T(), "Бег", r.GetActivity())
+	assert.Equal(suite.T(), int32(8000), r.GetSteps())
+	assert.Equal(suite.T(), time.Hour, r.GetDuration().AsDuration())
+	assert.Equal(suite.T(), want.Distance, r.GetDistanceKm())
+	assert.Equal(suite.T(), want.Calories, r.GetCalories())
+	assert.Equal(suite.T(), trackerpb.CalorieModel_CALORIE_MODEL_MET, r.GetModel())
+
+	// Та же тренировка по полям дает тот же результат
+	fields, err := suite.client.ComputeTraining(ctx, &trackerpb.ComputeTrainingRequest{
+		Profile: testProfile,
+		Record: &trackerpb.ComputeTrainingRequest_Training{Training: &trackerpb.Training{
+			Start:    timestamppb.New(start),
+			Steps:    8000,
+			Activity: "Бег",
+			Duration: durationpb.New(time.Hour),
+		}},
+		Model: trackerpb.CalorieModel_CALORIE_MODEL_MET,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), r.GetCalories(), fields.GetResult().GetCalories())
+	assert.True(suite.T(), start.Equal(fields.GetResult().GetStart().AsTime()))
+}
+
+func (suite *RPCTestSuite) TestComputeTrainingHeartRate() {
+	training := &trackerpb.Training{Steps: 8000, Activity: "бег", Duration: durationpb.New(time.Hour), HeartRateBpm: 150}
+	resp, err := suite.client.ComputeTraining(context.Background(), &trackerpb.ComputeTrainingRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeTrainingRequest_Training{Training: training},
+		Model:   trackerpb.CalorieModel_CALORIE_MODEL_HEART_RATE,
+	})
+	assert.NoError(suite.T(), err)
+
+	want, err := spentcalories.ComputeFor(spentcalories.Workout{Activity: "Бег", Steps: 8000, Duration: time.Hour, HeartRate: 150},
+		suite.profile(), spentcalories.WithModel(spentcalories.HeartRateModel))
+	assert.NoError(suite.T(), err)
+	r := resp.GetResult()
+	assert.Equal(suite.T(), "Бег", r.GetActivity())
+	assert.Equal(suite.T(), trackerpb.CalorieModel_CALORIE_MODEL_HEART_RATE, r.GetModel())
+	assert.Equal(suite.T(), want.Calories, r.GetCalories())
+
+	// Без пульса калории считаются по скорости, и результат сообщает об этом
+	training.HeartRateBpm = 0
+	resp, err = suite.client.ComputeTraining(context.Background(), &trackerpb.ComputeTrainingRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeTrainingRequest_Training{Training: training},
+		Model:   trackerpb.CalorieModel_CALORIE_MODEL_HEART_RATE,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), trackerpb.CalorieModel_CALORIE_MODEL_SPEED, resp.GetResult().GetModel())
+	assert.NotEqual(suite.T(), want.Calories, resp.GetResult().GetCalories())
+}
+
+func (suite *RPCTestSuite) TestComputeDayActivity() {
+	resp, err := suite.client.ComputeDayActivity(context.Background(), &trackerpb.ComputeDayActivityRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeDayActivityRequest_Day{Day: &trackerpb.DayPacket{Steps: 678, Duration: durationpb.New(50 * time.Minute)}},
+	})
+	assert.NoError(suite.T(), err)
+
+	want, err := daysteps.DayActionFor("678,50m", suite.profile())
+	assert.NoError(suite.T(), err)
+	r := resp.GetResult()
+	assert.Nil(suite.T(), r.GetStart())
+	assert.Equal(suite.T(), int32(678), r.GetSteps())
+	assert.Equal(suite.T(), want.Distance, r.GetDistanceKm())
+	assert.Equal(suite.T(), want.Calories, r.GetCalories())
+
+	// Поля пакета передаются в расчет без разбора строки
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	resp, err = suite.client.ComputeDayActivity(context.Background(), &trackerpb.ComputeDayActivityRequest{
+		Profile: testProfile,
+		Record: &trackerpb.ComputeDayActivityRequest_Day{Day: &trackerpb.DayPacket{
+			Start: timestamppb.New(start), Steps: 678, Duration: durationpb.New(50 * time.Minute)}},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), start.Equal(resp.GetResult().GetStart().AsTime()))
+	assert.Equal(suite.T(), want.Calories, resp.GetResult().GetCalories())
+
+	_, err = suite.client.ComputeDayActivity(context.Background(), &trackerpb.ComputeDayActivityRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeDayActivityRequest_Day{Day: &trackerpb.DayPacket{Steps: -5, Duration: durationpb.New(time.Hour)}},
+	})
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = append(violations, br.GetFieldViolations()...)
+		}
+	}
+	if assert.Len(suite.T(), violations, 1) {
+		assert.Equal(suite.T(), daysteps.FieldSteps, violations[0].GetField())
+		assert.Equal(suite.T(), "reason.steps_positive", violations[0].GetReason())
+	}
+}
+
+func (suite *RPCTestSuite) TestErrors() {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		req    *trackerpb.ComputeTrainingRequest
+		field  string
+		reason string
+	}{
+		{
+			name:   "ошибка разбора",
+			req:    &trackerpb.ComputeTrainingRequest{Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Packet{Packet: "много,Бег,1h"}},
+			field:  spentcalories.FieldSteps,
+			reason: "reason.steps_not_integer",
+		},
+		{
+			name: "нулевая продолжительность",
+			req: &trackerpb.ComputeTrainingRequest{Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Training{
+				Training: &trackerpb.Training{Steps: 1000, Activity: "Бег"}}},
+			field:  spentcalories.FieldDuration,
+			reason: "reason.duration_positive",
+		},
+		{
+			// Значения полей не склеиваются в строку, запятая остается в названии
+			name: "запятая в активности",
+			req: &trackerpb.ComputeTrainingRequest{Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Training{
+				Training: &trackerpb.Training{Steps: 1000, Activity: "Бег,1h", Duration: durationpb.New(time.Hour)}}},
+			field:  spentcalories.FieldActivity,
+			reason: "reason.activity_name_not_registered",
+		},
+		{
+			name: "отрицательный пульс",
+			req: &trackerpb.ComputeTrainingRequest{Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Training{
+				Training: &trackerpb.Training{Steps: 1000, Activity: "Бег", Duration: durationpb.New(time.Hour), HeartRateBpm: -1}},
+				Model: trackerpb.CalorieModel_CALORIE_MODEL_HEART_RATE},
+		},
+		{
+			name:   "неверный профиль",
+			req:    &trackerpb.ComputeTrainingRequest{Profile: &trackerpb.Profile{WeightKg: 5, HeightM: 1.75}, Record: &trackerpb.ComputeTrainingRequest_Packet{Packet: "1000,Бег,1h"}},
+			field:  "profile." + profile.FieldWeight,
+			reason: "profile.range",
+		},
+		{
+			name: "без профиля",
+			req:  &trackerpb.ComputeTrainingRequest{Record: &trackerpb.ComputeTrainingRequest_Packet{Packet: "1000,Бег,1h"}},
+		},
+		{
+			name: "неверная дата",
+			req:  &trackerpb.ComputeTrainingRequest{Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Packet{Packet: "1000,Бег,1h"}, Date: "вчера"},
+		},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, err := suite.client.ComputeTraining(ctx, tt.req)
+			st := status.Convert(err)
+			assert.Equal(suite.T(), codes.InvalidArgument, st.Code())
+			assert.NotEmpty(suite.T(), st.Message())
+
+			var violations []*errdetails.BadRequest_FieldViolation
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					violations = append(violations, br.GetFieldViolations()...)
+				}
+			}
+			if tt.field == "" {
+				assert.Empty(suite.T(), violations)
+				return
+			}
+			if assert.Len(suite.T(), violations, 1) {
+				assert.Equal(suite.T(), tt.field, violations[0].GetField())
+				assert.Equal(suite.T(), tt.reason, violations[0].GetReason())
+				assert.Equal(suite.T(), st.Message(), violations[0].GetDescription())
+			}
+		})
+	}
+
+	// Язык ошибки выбирается по метаданным accept-language
+	en := metadata.AppendToOutgoingContext(ctx, "accept-language", "en")
+	_, err := suite.client.ComputeDayActivity(en, &trackerpb.ComputeDayActivityRequest{
+		Profile: testProfile,
+		Record:  &trackerpb.ComputeDayActivityRequest_Packet{Packet: "678"},
+	})
+	_, want := daysteps.DayActionFor("678", suite.profile())
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+	assert.Equal(suite.T(), i18n.Localize(want, i18n.English), status.Convert(err).Message())
+}
+
+func (suite *RPCTestSuite) TestBatchCompute() {
+	stream, err := suite.client.BatchCompute(context.Background())
+	assert.NoError(suite.T(), err)
+
+	requests := []*trackerpb.BatchComputeRequest{
+		{Item: &trackerpb.BatchComputeRequest_Training{Training: &trackerpb.ComputeTrainingRequest{
+			Profile: testProfile, Record: &trackerpb.ComputeTrainingRequest_Packet{Packet: "8000,Бег,1h"}}}},
+		{Item: &trackerpb.BatchComputeRequest_Day{Day: &trackerpb.ComputeDayActivityRequest{
+			Profile: testProfile, Record: &trackerpb.ComputeDayActivityRequest_Packet{Packet: "абв,50m"}}}},
+		{Item: &trackerpb.BatchComputeRequest_Day{Day: &trackerpb.ComputeDayActivityRequest{
+			Profile: testProfile, Record: &trackerpb.ComputeDayActivityRequest_Packet{Packet: "678,50m"}}}},
+		{},
+	}
+	// Запросы отправляются вперед ответов: сервер отвечает по мере чтения потока
+	go func() {
+		for _, req := range requests {
+			if err := stream.Send(req); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	var responses []*trackerpb.BatchComputeResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(suite.T(), err) {
+			return
+		}
+		responses = append(responses, resp)
+	}
+
+	if !assert.Len(suite.T(), responses, len(requests)) {
+		return
+	}
+	for i, resp := range responses {
+		assert.Equal(suite.T(), uint64(i), resp.GetIndex())
+	}
+	assert.Equal(suite.T(), "Бег", responses[0].GetTraining().GetActivity())
+
+	parseErr := responses[1].GetError()
+	if assert.NotNil(suite.T(), parseErr) {
+		assert.Equal(suite.T(), spentcalories.FieldSteps, parseErr.GetField())
+		assert.Equal(suite.T(), "абв", parseErr.GetValue())
+		assert.Equal(suite.T(), int32(0), parseErr.GetColumn())
+		assert.Equal(suite.T(), "reason.steps_not_integer", parseErr.GetCode())
+	}
+
+	assert.Equal(suite.T(), int32(678), responses[2].GetDay().GetSteps())
+
+	empty := responses[3].GetError()
+	if assert.NotNil(suite.T(), empty) {
+		assert.Empty(suite.T(), empty.GetField())
+		assert.Equal(suite.T(), int32(-1), empty.GetColumn())
+	}
+}
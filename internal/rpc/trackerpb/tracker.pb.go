@@ -0,0 +1,1313 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: tracker/v1/tracker.proto
+
+// Сервис расчета калорий поверх пакетов spentcalories и daysteps
+
+package trackerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Sex int32
+
+const (
+	Sex_SEX_UNSPECIFIED Sex = 0
+	Sex_SEX_MALE        Sex = 1
+	Sex_SEX_FEMALE      Sex = 2
+)
+
+// Enum value maps for Sex.
+var (
+	Sex_name = map[int32]string{
+		0: "SEX_UNSPECIFIED",
+		1: "SEX_MALE",
+		2: "SEX_FEMALE",
+	}
+	Sex_value = map[string]int32{
+		"SEX_UNSPECIFIED": 0,
+		"SEX_MALE":        1,
+		"SEX_FEMALE":      2,
+	}
+)
+
+func (x Sex) Enum() *Sex {
+	p := new(Sex)
+	*p = x
+	return p
+}
+
+func (x Sex) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sex) Descriptor() protoreflect.EnumDescriptor {
+	return file_tracker_v1_tracker_proto_enumTypes[0].Descriptor()
+}
+
+func (Sex) Type() protoreflect.EnumType {
+	return &file_tracker_v1_tracker_proto_enumTypes[0]
+}
+
+func (x Sex) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sex.Descriptor instead.
+func (Sex) EnumDescriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{0}
+}
+
+// Модель расчета калорий тренировки, по умолчанию - по скорости
+type CalorieModel int32
+
+const (
+	CalorieModel_CALORIE_MODEL_UNSPECIFIED CalorieModel = 0
+	CalorieModel_CALORIE_MODEL_SPEED       CalorieModel = 1
+	CalorieModel_CALORIE_MODEL_MET         CalorieModel = 2
+	CalorieModel_CALORIE_MODEL_HEART_RATE  CalorieModel = 3
+)
+
+// Enum value maps for CalorieModel.
+var (
+	CalorieModel_name = map[int32]string{
+		0: "CALORIE_MODEL_UNSPECIFIED",
+		1: "CALORIE_MODEL_SPEED",
+		2: "CALORIE_MODEL_MET",
+		3: "CALORIE_MODEL_HEART_RATE",
+	}
+	CalorieModel_value = map[string]int32{
+		"CALORIE_MODEL_UNSPECIFIED": 0,
+		"CALORIE_MODEL_SPEED":       1,
+		"CALORIE_MODEL_MET":         2,
+		"CALORIE_MODEL_HEART_RATE":  3,
+	}
+)
+
+func (x CalorieModel) Enum() *CalorieModel {
+	p := new(CalorieModel)
+	*p = x
+	return p
+}
+
+func (x CalorieModel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CalorieModel) Descriptor() protoreflect.EnumDescriptor {
+	return file_tracker_v1_tracker_proto_enumTypes[1].Descriptor()
+}
+
+func (CalorieModel) Type() protoreflect.EnumType {
+	return &file_tracker_v1_tracker_proto_enumTypes[1]
+}
+
+func (x CalorieModel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CalorieModel.Descriptor instead.
+func (CalorieModel) EnumDescriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{1}
+}
+
+// Профиль пользователя, вес и рост обязательны
+type Profile struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WeightKg float64                `protobuf:"fixed64,1,opt,name=weight_kg,json=weightKg,proto3" json:"weight_kg,omitempty"`
+	HeightM  float64                `protobuf:"fixed64,2,opt,name=height_m,json=heightM,proto3" json:"height_m,omitempty"`
+	// Дата рождения ГГГГ-ММ-ДД
+	BirthDate string `protobuf:"bytes,3,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	Sex       Sex    `protobuf:"varint,4,opt,name=sex,proto3,enum=tracker.v1.Sex" json:"sex,omitempty"`
+	// Измеренная длина шага в метрах
+	StrideM          float64 `protobuf:"fixed64,5,opt,name=stride_m,json=strideM,proto3" json:"stride_m,omitempty"`
+	MaxHeartRate     float64 `protobuf:"fixed64,6,opt,name=max_heart_rate,json=maxHeartRate,proto3" json:"max_heart_rate,omitempty"`
+	RestingHeartRate float64 `protobuf:"fixed64,7,opt,name=resting_heart_rate,json=restingHeartRate,proto3" json:"resting_heart_rate,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Profile) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
+
+func (x *Profile) GetHeightM() float64 {
+	if x != nil {
+		return x.HeightM
+	}
+	return 0
+}
+
+func (x *Profile) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+func (x *Profile) GetSex() Sex {
+	if x != nil {
+		return x.Sex
+	}
+	return Sex_SEX_UNSPECIFIED
+}
+
+func (x *Profile) GetStrideM() float64 {
+	if x != nil {
+		return x.StrideM
+	}
+	return 0
+}
+
+func (x *Profile) GetMaxHeartRate() float64 {
+	if x != nil {
+		return x.MaxHeartRate
+	}
+	return 0
+}
+
+func (x *Profile) GetRestingHeartRate() float64 {
+	if x != nil {
+		return x.RestingHeartRate
+	}
+	return 0
+}
+
+// Тренировка по полям, значения проверяются так же, как в строке
+// "начало,шаги,активность,продолжительность"
+type Training struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Start    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Steps    int32                  `protobuf:"varint,2,opt,name=steps,proto3" json:"steps,omitempty"`
+	Activity string                 `protobuf:"bytes,3,opt,name=activity,proto3" json:"activity,omitempty"`
+	Duration *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Средний пульс в ударах в минуту для CALORIE_MODEL_HEART_RATE. Без него
+	// калории считаются по скорости, а в результате указывается CALORIE_MODEL_SPEED
+	HeartRateBpm  float64 `protobuf:"fixed64,5,opt,name=heart_rate_bpm,json=heartRateBpm,proto3" json:"heart_rate_bpm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Training) Reset() {
+	*x = Training{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Training) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Training) ProtoMessage() {}
+
+func (x *Training) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Training.ProtoReflect.Descriptor instead.
+func (*Training) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Training) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Training) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *Training) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *Training) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Training) GetHeartRateBpm() float64 {
+	if x != nil {
+		return x.HeartRateBpm
+	}
+	return 0
+}
+
+// Пакет дневной активности по полям, собирается в строку "начало,шаги,продолжительность"
+type DayPacket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Steps         int32                  `protobuf:"varint,2,opt,name=steps,proto3" json:"steps,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayPacket) Reset() {
+	*x = DayPacket{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayPacket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayPacket) ProtoMessage() {}
+
+func (x *DayPacket) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayPacket.ProtoReflect.Descriptor instead.
+func (*DayPacket) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DayPacket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *DayPacket) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *DayPacket) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type ComputeTrainingRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Profile *Profile               `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*ComputeTrainingRequest_Packet
+	//	*ComputeTrainingRequest_Training
+	Record isComputeTrainingRequest_Record `protobuf_oneof:"record"`
+	// Дата ГГГГ-ММ-ДД для записей, где указано только время суток
+	Date          string       `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	Model         CalorieModel `protobuf:"varint,5,opt,name=model,proto3,enum=tracker.v1.CalorieModel" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeTrainingRequest) Reset() {
+	*x = ComputeTrainingRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeTrainingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeTrainingRequest) ProtoMessage() {}
+
+func (x *ComputeTrainingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeTrainingRequest.ProtoReflect.Descriptor instead.
+func (*ComputeTrainingRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ComputeTrainingRequest) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *ComputeTrainingRequest) GetRecord() isComputeTrainingRequest_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ComputeTrainingRequest) GetPacket() string {
+	if x != nil {
+		if x, ok := x.Record.(*ComputeTrainingRequest_Packet); ok {
+			return x.Packet
+		}
+	}
+	return ""
+}
+
+func (x *ComputeTrainingRequest) GetTraining() *Training {
+	if x != nil {
+		if x, ok := x.Record.(*ComputeTrainingRequest_Training); ok {
+			return x.Training
+		}
+	}
+	return nil
+}
+
+func (x *ComputeTrainingRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ComputeTrainingRequest) GetModel() CalorieModel {
+	if x != nil {
+		return x.Model
+	}
+	return CalorieModel_CALORIE_MODEL_UNSPECIFIED
+}
+
+type isComputeTrainingRequest_Record interface {
+	isComputeTrainingRequest_Record()
+}
+
+type ComputeTrainingRequest_Packet struct {
+	// Строка в формате команды training, например "8000,Бег,1h"
+	Packet string `protobuf:"bytes,2,opt,name=packet,proto3,oneof"`
+}
+
+type ComputeTrainingRequest_Training struct {
+	Training *Training `protobuf:"bytes,3,opt,name=training,proto3,oneof"`
+}
+
+func (*ComputeTrainingRequest_Packet) isComputeTrainingRequest_Record() {}
+
+func (*ComputeTrainingRequest_Training) isComputeTrainingRequest_Record() {}
+
+type ComputeDayActivityRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Profile *Profile               `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*ComputeDayActivityRequest_Packet
+	//	*ComputeDayActivityRequest_Day
+	Record        isComputeDayActivityRequest_Record `protobuf_oneof:"record"`
+	Date          string                             `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeDayActivityRequest) Reset() {
+	*x = ComputeDayActivityRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeDayActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeDayActivityRequest) ProtoMessage() {}
+
+func (x *ComputeDayActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeDayActivityRequest.ProtoReflect.Descriptor instead.
+func (*ComputeDayActivityRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ComputeDayActivityRequest) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *ComputeDayActivityRequest) GetRecord() isComputeDayActivityRequest_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ComputeDayActivityRequest) GetPacket() string {
+	if x != nil {
+		if x, ok := x.Record.(*ComputeDayActivityRequest_Packet); ok {
+			return x.Packet
+		}
+	}
+	return ""
+}
+
+func (x *ComputeDayActivityRequest) GetDay() *DayPacket {
+	if x != nil {
+		if x, ok := x.Record.(*ComputeDayActivityRequest_Day); ok {
+			return x.Day
+		}
+	}
+	return nil
+}
+
+func (x *ComputeDayActivityRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type isComputeDayActivityRequest_Record interface {
+	isComputeDayActivityRequest_Record()
+}
+
+type ComputeDayActivityRequest_Packet struct {
+	// Строка в формате команды day, например "678,50m"
+	Packet string `protobuf:"bytes,2,opt,name=packet,proto3,oneof"`
+}
+
+type ComputeDayActivityRequest_Day struct {
+	Day *DayPacket `protobuf:"bytes,3,opt,name=day,proto3,oneof"`
+}
+
+func (*ComputeDayActivityRequest_Packet) isComputeDayActivityRequest_Record() {}
+
+func (*ComputeDayActivityRequest_Day) isComputeDayActivityRequest_Record() {}
+
+type TrainingResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Время начала, не задано если в записи его не было
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Activity      string                 `protobuf:"bytes,2,opt,name=activity,proto3" json:"activity,omitempty"`
+	Steps         int32                  `protobuf:"varint,3,opt,name=steps,proto3" json:"steps,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,5,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	SpeedKmh      float64                `protobuf:"fixed64,6,opt,name=speed_kmh,json=speedKmh,proto3" json:"speed_kmh,omitempty"`
+	Calories      float64                `protobuf:"fixed64,7,opt,name=calories,proto3" json:"calories,omitempty"`
+	Model         CalorieModel           `protobuf:"varint,8,opt,name=model,proto3,enum=tracker.v1.CalorieModel" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrainingResult) Reset() {
+	*x = TrainingResult{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrainingResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrainingResult) ProtoMessage() {}
+
+func (x *TrainingResult) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrainingResult.ProtoReflect.Descriptor instead.
+func (*TrainingResult) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TrainingResult) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TrainingResult) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *TrainingResult) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *TrainingResult) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *TrainingResult) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *TrainingResult) GetSpeedKmh() float64 {
+	if x != nil {
+		return x.SpeedKmh
+	}
+	return 0
+}
+
+func (x *TrainingResult) GetCalories() float64 {
+	if x != nil {
+		return x.Calories
+	}
+	return 0
+}
+
+func (x *TrainingResult) GetModel() CalorieModel {
+	if x != nil {
+		return x.Model
+	}
+	return CalorieModel_CALORIE_MODEL_UNSPECIFIED
+}
+
+type DayActivityResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Steps         int32                  `protobuf:"varint,2,opt,name=steps,proto3" json:"steps,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,4,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	SpeedKmh      float64                `protobuf:"fixed64,5,opt,name=speed_kmh,json=speedKmh,proto3" json:"speed_kmh,omitempty"`
+	Calories      float64                `protobuf:"fixed64,6,opt,name=calories,proto3" json:"calories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayActivityResult) Reset() {
+	*x = DayActivityResult{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayActivityResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayActivityResult) ProtoMessage() {}
+
+func (x *DayActivityResult) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayActivityResult.ProtoReflect.Descriptor instead.
+func (*DayActivityResult) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DayActivityResult) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *DayActivityResult) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *DayActivityResult) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *DayActivityResult) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *DayActivityResult) GetSpeedKmh() float64 {
+	if x != nil {
+		return x.SpeedKmh
+	}
+	return 0
+}
+
+func (x *DayActivityResult) GetCalories() float64 {
+	if x != nil {
+		return x.Calories
+	}
+	return 0
+}
+
+type ComputeTrainingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *TrainingResult        `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeTrainingResponse) Reset() {
+	*x = ComputeTrainingResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeTrainingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeTrainingResponse) ProtoMessage() {}
+
+func (x *ComputeTrainingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeTrainingResponse.ProtoReflect.Descriptor instead.
+func (*ComputeTrainingResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ComputeTrainingResponse) GetResult() *TrainingResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ComputeDayActivityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *DayActivityResult     `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeDayActivityResponse) Reset() {
+	*x = ComputeDayActivityResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeDayActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeDayActivityResponse) ProtoMessage() {}
+
+func (x *ComputeDayActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeDayActivityResponse.ProtoReflect.Descriptor instead.
+func (*ComputeDayActivityResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ComputeDayActivityResponse) GetResult() *DayActivityResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type BatchComputeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Item:
+	//
+	//	*BatchComputeRequest_Training
+	//	*BatchComputeRequest_Day
+	Item          isBatchComputeRequest_Item `protobuf_oneof:"item"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchComputeRequest) Reset() {
+	*x = BatchComputeRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchComputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchComputeRequest) ProtoMessage() {}
+
+func (x *BatchComputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchComputeRequest.ProtoReflect.Descriptor instead.
+func (*BatchComputeRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchComputeRequest) GetItem() isBatchComputeRequest_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *BatchComputeRequest) GetTraining() *ComputeTrainingRequest {
+	if x != nil {
+		if x, ok := x.Item.(*BatchComputeRequest_Training); ok {
+			return x.Training
+		}
+	}
+	return nil
+}
+
+func (x *BatchComputeRequest) GetDay() *ComputeDayActivityRequest {
+	if x != nil {
+		if x, ok := x.Item.(*BatchComputeRequest_Day); ok {
+			return x.Day
+		}
+	}
+	return nil
+}
+
+type isBatchComputeRequest_Item interface {
+	isBatchComputeRequest_Item()
+}
+
+type BatchComputeRequest_Training struct {
+	Training *ComputeTrainingRequest `protobuf:"bytes,1,opt,name=training,proto3,oneof"`
+}
+
+type BatchComputeRequest_Day struct {
+	Day *ComputeDayActivityRequest `protobuf:"bytes,2,opt,name=day,proto3,oneof"`
+}
+
+func (*BatchComputeRequest_Training) isBatchComputeRequest_Item() {}
+
+func (*BatchComputeRequest_Day) isBatchComputeRequest_Item() {}
+
+// Ошибка записи, для ошибок разбора заполнены поля ParseError
+type RecordError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Номер колонки начиная с нуля, -1 если ошибка относится ко всей записи
+	Column int32 `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
+	// Ключ причины в каталоге i18n
+	Code          string `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordError) Reset() {
+	*x = RecordError{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordError) ProtoMessage() {}
+
+func (x *RecordError) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordError.ProtoReflect.Descriptor instead.
+func (*RecordError) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RecordError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *RecordError) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *RecordError) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *RecordError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RecordError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchComputeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Номер запроса в потоке начиная с нуля
+	Index uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchComputeResponse_Training
+	//	*BatchComputeResponse_Day
+	//	*BatchComputeResponse_Error
+	Result        isBatchComputeResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchComputeResponse) Reset() {
+	*x = BatchComputeResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchComputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchComputeResponse) ProtoMessage() {}
+
+func (x *BatchComputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchComputeResponse.ProtoReflect.Descriptor instead.
+func (*BatchComputeResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchComputeResponse) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchComputeResponse) GetResult() isBatchComputeResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchComputeResponse) GetTraining() *TrainingResult {
+	if x != nil {
+		if x, ok := x.Result.(*BatchComputeResponse_Training); ok {
+			return x.Training
+		}
+	}
+	return nil
+}
+
+func (x *BatchComputeResponse) GetDay() *DayActivityResult {
+	if x != nil {
+		if x, ok := x.Result.(*BatchComputeResponse_Day); ok {
+			return x.Day
+		}
+	}
+	return nil
+}
+
+func (x *BatchComputeResponse) GetError() *RecordError {
+	if x != nil {
+		if x, ok := x.Result.(*BatchComputeResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchComputeResponse_Result interface {
+	isBatchComputeResponse_Result()
+}
+
+type BatchComputeResponse_Training struct {
+	Training *TrainingResult `protobuf:"bytes,2,opt,name=training,proto3,oneof"`
+}
+
+type BatchComputeResponse_Day struct {
+	Day *DayActivityResult `protobuf:"bytes,3,opt,name=day,proto3,oneof"`
+}
+
+type BatchComputeResponse_Error struct {
+	Error *RecordError `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchComputeResponse_Training) isBatchComputeResponse_Result() {}
+
+func (*BatchComputeResponse_Day) isBatchComputeResponse_Result() {}
+
+func (*BatchComputeResponse_Error) isBatchComputeResponse_Result() {}
+
+var File_tracker_v1_tracker_proto protoreflect.FileDescriptor
+
+const file_tracker_v1_tracker_proto_rawDesc = "" +
+	"\n" +
+	"\x18tracker/v1/tracker.proto\x12\n" +
+	"tracker.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf2\x01\n" +
+	"\aProfile\x12\x1b\n" +
+	"\tweight_kg\x18\x01 \x01(\x01R\bweightKg\x12\x19\n" +
+	"\bheight_m\x18\x02 \x01(\x01R\aheightM\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\x03 \x01(\tR\tbirthDate\x12!\n" +
+	"\x03sex\x18\x04 \x01(\x0e2\x0f.tracker.v1.SexR\x03sex\x12\x19\n" +
+	"\bstride_m\x18\x05 \x01(\x01R\astrideM\x12$\n" +
+	"\x0emax_heart_rate\x18\x06 \x01(\x01R\fmaxHeartRate\x12,\n" +
+	"\x12resting_heart_rate\x18\a \x01(\x01R\x10restingHeartRate\"\xcb\x01\n" +
+	"\bTraining\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05steps\x18\x02 \x01(\x05R\x05steps\x12\x1a\n" +
+	"\bactivity\x18\x03 \x01(\tR\bactivity\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12$\n" +
+	"\x0eheart_rate_bpm\x18\x05 \x01(\x01R\fheartRateBpm\"\x8a\x01\n" +
+	"\tDayPacket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05steps\x18\x02 \x01(\x05R\x05steps\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\"\xe3\x01\n" +
+	"\x16ComputeTrainingRequest\x12-\n" +
+	"\aprofile\x18\x01 \x01(\v2\x13.tracker.v1.ProfileR\aprofile\x12\x18\n" +
+	"\x06packet\x18\x02 \x01(\tH\x00R\x06packet\x122\n" +
+	"\btraining\x18\x03 \x01(\v2\x14.tracker.v1.TrainingH\x00R\btraining\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04date\x12.\n" +
+	"\x05model\x18\x05 \x01(\x0e2\x18.tracker.v1.CalorieModelR\x05modelB\b\n" +
+	"\x06record\"\xad\x01\n" +
+	"\x19ComputeDayActivityRequest\x12-\n" +
+	"\aprofile\x18\x01 \x01(\v2\x13.tracker.v1.ProfileR\aprofile\x12\x18\n" +
+	"\x06packet\x18\x02 \x01(\tH\x00R\x06packet\x12)\n" +
+	"\x03day\x18\x03 \x01(\v2\x15.tracker.v1.DayPacketH\x00R\x03day\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04dateB\b\n" +
+	"\x06record\"\xb5\x02\n" +
+	"\x0eTrainingResult\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x1a\n" +
+	"\bactivity\x18\x02 \x01(\tR\bactivity\x12\x14\n" +
+	"\x05steps\x18\x03 \x01(\x05R\x05steps\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x1f\n" +
+	"\vdistance_km\x18\x05 \x01(\x01R\n" +
+	"distanceKm\x12\x1b\n" +
+	"\tspeed_kmh\x18\x06 \x01(\x01R\bspeedKmh\x12\x1a\n" +
+	"\bcalories\x18\a \x01(\x01R\bcalories\x12.\n" +
+	"\x05model\x18\b \x01(\x0e2\x18.tracker.v1.CalorieModelR\x05model\"\xec\x01\n" +
+	"\x11DayActivityResult\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05steps\x18\x02 \x01(\x05R\x05steps\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x1f\n" +
+	"\vdistance_km\x18\x04 \x01(\x01R\n" +
+	"distanceKm\x12\x1b\n" +
+	"\tspeed_kmh\x18\x05 \x01(\x01R\bspeedKmh\x12\x1a\n" +
+	"\bcalories\x18\x06 \x01(\x01R\bcalories\"M\n" +
+	"\x17ComputeTrainingResponse\x122\n" +
+	"\x06result\x18\x01 \x01(\v2\x1a.tracker.v1.TrainingResultR\x06result\"S\n" +
+	"\x1aComputeDayActivityResponse\x125\n" +
+	"\x06result\x18\x01 \x01(\v2\x1d.tracker.v1.DayActivityResultR\x06result\"\x9a\x01\n" +
+	"\x13BatchComputeRequest\x12@\n" +
+	"\btraining\x18\x01 \x01(\v2\".tracker.v1.ComputeTrainingRequestH\x00R\btraining\x129\n" +
+	"\x03day\x18\x02 \x01(\v2%.tracker.v1.ComputeDayActivityRequestH\x00R\x03dayB\x06\n" +
+	"\x04item\"\x7f\n" +
+	"\vRecordError\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\x05R\x06column\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"\xd4\x01\n" +
+	"\x14BatchComputeResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x128\n" +
+	"\btraining\x18\x02 \x01(\v2\x1a.tracker.v1.TrainingResultH\x00R\btraining\x121\n" +
+	"\x03day\x18\x03 \x01(\v2\x1d.tracker.v1.DayActivityResultH\x00R\x03day\x12/\n" +
+	"\x05error\x18\x04 \x01(\v2\x17.tracker.v1.RecordErrorH\x00R\x05errorB\b\n" +
+	"\x06result*8\n" +
+	"\x03Sex\x12\x13\n" +
+	"\x0fSEX_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSEX_MALE\x10\x01\x12\x0e\n" +
+	"\n" +
+	"SEX_FEMALE\x10\x02*{\n" +
+	"\fCalorieModel\x12\x1d\n" +
+	"\x19CALORIE_MODEL_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13CALORIE_MODEL_SPEED\x10\x01\x12\x15\n" +
+	"\x11CALORIE_MODEL_MET\x10\x02\x12\x1c\n" +
+	"\x18CALORIE_MODEL_HEART_RATE\x10\x032\xa7\x02\n" +
+	"\rCalorieEngine\x12Z\n" +
+	"\x0fComputeTraining\x12\".tracker.v1.ComputeTrainingRequest\x1a#.tracker.v1.ComputeTrainingResponse\x12c\n" +
+	"\x12ComputeDayActivity\x12%.tracker.v1.ComputeDayActivityRequest\x1a&.tracker.v1.ComputeDayActivityResponse\x12U\n" +
+	"\fBatchCompute\x12\x1f.tracker.v1.BatchComputeRequest\x1a .tracker.v1.BatchComputeResponse(\x010\x01B<Z:github.com/Yandex-Practicum/tracker/internal/rpc/trackerpbb\x06proto3"
+
+var (
+	file_tracker_v1_tracker_proto_rawDescOnce sync.Once
+	file_tracker_v1_tracker_proto_rawDescData []byte
+)
+
+func file_tracker_v1_tracker_proto_rawDescGZIP() []byte {
+	file_tracker_v1_tracker_proto_rawDescOnce.Do(func() {
+		file_tracker_v1_tracker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tracker_v1_tracker_proto_rawDesc), len(file_tracker_v1_tracker_proto_rawDesc)))
+	})
+	return file_tracker_v1_tracker_proto_rawDescData
+}
+
+var file_tracker_v1_tracker_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_tracker_v1_tracker_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_tracker_v1_tracker_proto_goTypes = []any{
+	(Sex)(0),                           // 0: tracker.v1.Sex
+	(CalorieModel)(0),                  // 1: tracker.v1.CalorieModel
+	(*Profile)(nil),                    // 2: tracker.v1.Profile
+	(*Training)(nil),                   // 3: tracker.v1.Training
+	(*DayPacket)(nil),                  // 4: tracker.v1.DayPacket
+	(*ComputeTrainingRequest)(nil),     // 5: tracker.v1.ComputeTrainingRequest
+	(*ComputeDayActivityRequest)(nil),  // 6: tracker.v1.ComputeDayActivityRequest
+	(*TrainingResult)(nil),             // 7: tracker.v1.TrainingResult
+	(*DayActivityResult)(nil),          // 8: tracker.v1.DayActivityResult
+	(*ComputeTrainingResponse)(nil),    // 9: tracker.v1.ComputeTrainingResponse
+	(*ComputeDayActivityResponse)(nil), // 10: tracker.v1.ComputeDayActivityResponse
+	(*BatchComputeRequest)(nil),        // 11: tracker.v1.BatchComputeRequest
+	(*RecordError)(nil),                // 12: tracker.v1.RecordError
+	(*BatchComputeResponse)(nil),       // 13: tracker.v1.BatchComputeResponse
+	(*timestamppb.Timestamp)(nil),      // 14: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),        // 15: google.protobuf.Duration
+}
+var file_tracker_v1_tracker_proto_depIdxs = []int32{
+	0,  // 0: tracker.v1.Profile.sex:type_name -> tracker.v1.Sex
+	14, // 1: tracker.v1.Training.start:type_name -> google.protobuf.Timestamp
+	15, // 2: tracker.v1.Training.duration:type_name -> google.protobuf.Duration
+	14, // 3: tracker.v1.DayPacket.start:type_name -> google.protobuf.Timestamp
+	15, // 4: tracker.v1.DayPacket.duration:type_name -> google.protobuf.Duration
+	2,  // 5: tracker.v1.ComputeTrainingRequest.profile:type_name -> tracker.v1.Profile
+	3,  // 6: tracker.v1.ComputeTrainingRequest.training:type_name -> tracker.v1.Training
+	1,  // 7: tracker.v1.ComputeTrainingRequest.model:type_name -> tracker.v1.CalorieModel
+	2,  // 8: tracker.v1.ComputeDayActivityRequest.profile:type_name -> tracker.v1.Profile
+	4,  // 9: tracker.v1.ComputeDayActivityRequest.day:type_name -> tracker.v1.DayPacket
+	14, // 10: tracker.v1.TrainingResult.start:type_name -> google.protobuf.Timestamp
+	15, // 11: tracker.v1.TrainingResult.duration:type_name -> google.protobuf.Duration
+	1,  // 12: tracker.v1.TrainingResult.model:type_name -> tracker.v1.CalorieModel
+	14, // 13: tracker.v1.DayActivityResult.start:type_name -> google.protobuf.Timestamp
+	15, // 14: tracker.v1.DayActivityResult.duration:type_name -> google.protobuf.Duration
+	7,  // 15: tracker.v1.ComputeTrainingResponse.result:type_name -> tracker.v1.TrainingResult
+	8,  // 16: tracker.v1.ComputeDayActivityResponse.result:type_name -> tracker.v1.DayActivityResult
+	5,  // 17: tracker.v1.BatchComputeRequest.training:type_name -> tracker.v1.ComputeTrainingRequest
+	6,  // 18: tracker.v1.BatchComputeRequest.day:type_name -> tracker.v1.ComputeDayActivityRequest
+	7,  // 19: tracker.v1.BatchComputeResponse.training:type_name -> tracker.v1.TrainingResult
+	8,  // 20: tracker.v1.BatchComputeResponse.day:type_name -> tracker.v1.DayActivityResult
+	12, // 21: tracker.v1.BatchComputeResponse.error:type_name -> tracker.v1.RecordError
+	5,  // 22: tracker.v1.CalorieEngine.ComputeTraining:input_type -> tracker.v1.ComputeTrainingRequest
+	6,  // 23: tracker.v1.CalorieEngine.ComputeDayActivity:input_type -> tracker.v1.ComputeDayActivityRequest
+	11, // 24: tracker.v1.CalorieEngine.BatchCompute:input_type -> tracker.v1.BatchComputeRequest
+	9,  // 25: tracker.v1.CalorieEngine.ComputeTraining:output_type -> tracker.v1.ComputeTrainingResponse
+	10, // 26: tracker.v1.CalorieEngine.ComputeDayActivity:output_type -> tracker.v1.ComputeDayActivityResponse
+	13, // 27: tracker.v1.CalorieEngine.BatchCompute:output_type -> tracker.v1.BatchComputeResponse
+	25, // [25:28] is the sub-list for method output_type
+	22, // [22:25] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_tracker_v1_tracker_proto_init() }
+func file_tracker_v1_tracker_proto_init() {
+	if File_tracker_v1_tracker_proto != nil {
+		return
+	}
+	file_tracker_v1_tracker_proto_msgTypes[3].OneofWrappers = []any{
+		(*ComputeTrainingRequest_Packet)(nil),
+		(*ComputeTrainingRequest_Training)(nil),
+	}
+	file_tracker_v1_tracker_proto_msgTypes[4].OneofWrappers = []any{
+		(*ComputeDayActivityRequest_Packet)(nil),
+		(*ComputeDayActivityRequest_Day)(nil),
+	}
+	file_tracker_v1_tracker_proto_msgTypes[9].OneofWrappers = []any{
+		(*BatchComputeRequest_Training)(nil),
+		(*BatchComputeRequest_Day)(nil),
+	}
+	file_tracker_v1_tracker_proto_msgTypes[11].OneofWrappers = []any{
+		(*BatchComputeResponse_Training)(nil),
+		(*BatchComputeResponse_Day)(nil),
+		(*BatchComputeResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tracker_v1_tracker_proto_rawDesc), len(file_tracker_v1_tracker_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tracker_v1_tracker_proto_goTypes,
+		DependencyIndexes: file_tracker_v1_tracker_proto_depIdxs,
+		EnumInfos:         file_tracker_v1_tracker_proto_enumTypes,
+		MessageInfos:      file_tracker_v1_tracker_proto_msgTypes,
+	}.Build()
+	File_tracker_v1_tracker_proto = out.File
+	file_tracker_v1_tracker_proto_goTypes = nil
+	file_tracker_v1_tracker_proto_depIdxs = nil
+}
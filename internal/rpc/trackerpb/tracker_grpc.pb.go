@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tracker/v1/tracker.proto
+
+// Сервис расчета калорий поверх пакетов spentcalories и daysteps
+
+package trackerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CalorieEngine_ComputeTraining_FullMethodName    = "/tracker.v1.CalorieEngine/ComputeTraining"
+	CalorieEngine_ComputeDayActivity_FullMethodName = "/tracker.v1.CalorieEngine/ComputeDayActivity"
+	CalorieEngine_BatchCompute_FullMethodName       = "/tracker.v1.CalorieEngine/BatchCompute"
+)
+
+// CalorieEngineClient is the client API for CalorieEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CalorieEngineClient interface {
+	// ComputeTraining рассчитывает одну тренировку
+	ComputeTraining(ctx context.Context, in *ComputeTrainingRequest, opts ...grpc.CallOption) (*ComputeTrainingResponse, error)
+	// ComputeDayActivity рассчитывает один пакет дневной активности
+	ComputeDayActivity(ctx context.Context, in *ComputeDayActivityRequest, opts ...grpc.CallOption) (*ComputeDayActivityResponse, error)
+	// BatchCompute рассчитывает поток записей и отвечает на каждую в порядке запросов.
+	// Ошибка записи возвращается в ответе на нее и не прерывает поток
+	BatchCompute(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchComputeRequest, BatchComputeResponse], error)
+}
+
+type calorieEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalorieEngineClient(cc grpc.ClientConnInterface) CalorieEngineClient {
+	return &calorieEngineClient{cc}
+}
+
+func (c *calorieEngineClient) ComputeTraining(ctx context.Context, in *ComputeTrainingRequest, opts ...grpc.CallOption) (*ComputeTrainingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComputeTrainingResponse)
+	err := c.cc.Invoke(ctx, CalorieEngine_ComputeTraining_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calorieEngineClient) ComputeDayActivity(ctx context.Context, in *ComputeDayActivityRequest, opts ...grpc.CallOption) (*ComputeDayActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComputeDayActivityResponse)
+	err := c.cc.Invoke(ctx, CalorieEngine_ComputeDayActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calorieEngineClient) BatchCompute(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchComputeRequest, BatchComputeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalorieEngine_ServiceDesc.Streams[0], CalorieEngine_BatchCompute_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchComputeRequest, BatchComputeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalorieEngine_BatchComputeClient = grpc.BidiStreamingClient[BatchComputeRequest, BatchComputeResponse]
+
+// CalorieEngineServer is the server API for CalorieEngine service.
+// All implementations must embed UnimplementedCalorieEngineServer
+// for forward compatibility.
+type CalorieEngineServer interface {
+	// ComputeTraining рассчитывает одну тренировку
+	ComputeTraining(context.Context, *ComputeTrainingRequest) (*ComputeTrainingResponse, error)
+	// ComputeDayActivity рассчитывает один пакет дневной активности
+	ComputeDayActivity(context.Context, *ComputeDayActivityRequest) (*ComputeDayActivityResponse, error)
+	// BatchCompute рассчитывает поток записей и отвечает на каждую в порядке запросов.
+	// Ошибка записи возвращается в ответе на нее и не прерывает поток
+	BatchCompute(grpc.BidiStreamingServer[BatchComputeRequest, BatchComputeResponse]) error
+	mustEmbedUnimplementedCalorieEngineServer()
+}
+
+// UnimplementedCalorieEngineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCalorieEngineServer struct{}
+
+func (UnimplementedCalorieEngineServer) ComputeTraining(context.Context, *ComputeTrainingRequest) (*ComputeTrainingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeTraining not implemented")
+}
+func (UnimplementedCalorieEngineServer) ComputeDayActivity(context.Context, *ComputeDayActivityRequest) (*ComputeDayActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeDayActivity not implemented")
+}
+func (UnimplementedCalorieEngineServer) BatchCompute(grpc.BidiStreamingServer[BatchComputeRequest, BatchComputeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchCompute not implemented")
+}
+func (UnimplementedCalorieEngineServer) mustEmbedUnimplementedCalorieEngineServer() {}
+func (UnimplementedCalorieEngineServer) testEmbeddedByValue()                       {}
+
+// UnsafeCalorieEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalorieEngineServer will
+// result in compilation errors.
+type UnsafeCalorieEngineServer interface {
+	mustEmbedUnimplementedCalorieEngineServer()
+}
+
+func RegisterCalorieEngineServer(s grpc.ServiceRegistrar, srv CalorieEngineServer) {
+	// If the following call pancis, it indicates UnimplementedCalorieEngineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CalorieEngine_ServiceDesc, srv)
+}
+
+func _CalorieEngine_ComputeTraining_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeTrainingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalorieEngineServer).ComputeTraining(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalorieEngine_ComputeTraining_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalorieEngineServer).ComputeTraining(ctx, req.(*ComputeTrainingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalorieEngine_ComputeDayActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeDayActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalorieEngineServer).ComputeDayActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalorieEngine_ComputeDayActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalorieEngineServer).ComputeDayActivity(ctx, req.(*ComputeDayActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalorieEngine_BatchCompute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalorieEngineServer).BatchCompute(&grpc.GenericServerStream[BatchComputeRequest, BatchComputeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalorieEngine_BatchComputeServer = grpc.BidiStreamingServer[BatchComputeRequest, BatchComputeResponse]
+
+// CalorieEngine_ServiceDesc is the grpc.ServiceDesc for CalorieEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalorieEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tracker.v1.CalorieEngine",
+	HandlerType: (*CalorieEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ComputeTraining",
+			Handler:    _CalorieEngine_ComputeTraining_Handler,
+		},
+		{
+			MethodName: "ComputeDayActivity",
+			Handler:    _CalorieEngine_ComputeDayActivity_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchCompute",
+			Handler:       _CalorieEngine_BatchCompute_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "tracker/v1/tracker.proto",
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// ErrorResponse - тело ответа с ошибкой
+type ErrorResponse struct {
+	// Текст ошибки на языке запроса
+	Error string `json:"error"`
+	// Поле профиля для ошибки проверки профиля
+	Field string `json:"field,omitempty"`
+	// Ошибки отдельных записей, если запрос отклонен из-за них
+	Records []RecordError `json:"records,omitempty"`
+}
+
+// RecordError - ошибка одной записи из тела запроса. Для ошибок разбора
+// заполнены поля из spentcalories.ParseError
+type RecordError struct {
+	// Номер записи в запросе начиная с единицы
+	Record int `json:"record"`
+	// Поле записи, одна из констант spentcalories.Field*
+	Field string `json:"field,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Номер колонки начиная с нуля, -1 если ошибка относится ко всей записи
+	Column *int `json:"column,omitempty"`
+	// Ключ причины в каталоге i18n, по нему клиент может показать свой текст
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// recordError описывает ошибку записи с номером n на языке loc
+func recordError(n int, err error, loc i18n.Locale) RecordError {
+	re := RecordError{Record: n, Message: i18n.Localize(err, loc)}
+	var pe *spentcalories.ParseError
+	if errors.As(err, &pe) {
+		column := pe.Column
+		re.Field, re.Value, re.Column, re.Code = pe.Field, pe.Value, &column, pe.Code
+	}
+	return re
+}
+
+// httpError - ошибка обработчика с кодом ответа
+type httpError struct {
+	status int
+	err    error
+	// Ошибки записей, из-за которых отклонен запрос
+	records []RecordError
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func (e *httpError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(err error) error {
+	return &httpError{status: http.StatusBadRequest, err: err}
+}
+
+func notFound(err error) error {
+	return &httpError{status: http.StatusNotFound, err: err}
+}
+
+// bodyError - ошибка чтения тела запроса: слишком большое тело отклоняется
+// с кодом 413, остальные ошибки - 400
+func bodyError(err error) error {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return &httpError{status: http.StatusRequestEntityTooLarge, err: err}
+	}
+	return badRequest(err)
+}
+
+// errorResponse возвращает код и тело ответа для ошибки обработчика
+func errorResponse(err error, loc i18n.Locale) (int, ErrorResponse) {
+	status := http.StatusInternalServerError
+	resp := ErrorResponse{Error: i18n.Localize(err, loc)}
+	var he *httpError
+	if errors.As(err, &he) {
+		status = he.status
+		resp.Records = he.records
+	}
+	var ve *profile.ValidationError
+	if errors.As(err, &ve) {
+		resp.Field = ve.Field
+	}
+	return status, resp
+}
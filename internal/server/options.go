@@ -0,0 +1,45 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+// Option настраивает Server
+type Option func(*options)
+
+type options struct {
+	location *time.Location
+	now      func() time.Time
+	logger   *log.Logger
+}
+
+func newOptions(opts []Option) options {
+	o := options{location: time.Local, now: time.Now, logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLocation задает часовой пояс, в котором разбираются даты запросов и
+// записи делятся на дни в сводках. По умолчанию используется time.Local
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// WithLogger задает журнал для внутренних ошибок, по умолчанию log.Default()
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// withNow подменяет текущее время в тестах
+func withNow(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
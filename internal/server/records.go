@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Максимальный размер тела запроса
+const maxBodySize = 10 << 20
+
+// Record - запись в JSON. Если задан Packet, запись разбирается из него как
+// строка команд day и training, иначе значения берутся из остальных полей и
+// проверяются теми же функциями разбора
+type Record struct {
+	// Исходная строка, например "678,50m" или "8000,Бег,1h"
+	Packet string `json:"packet,omitempty"`
+	// Время начала в RFC 3339 или ЧЧ:ММ, необязательное
+	Start string `json:"start,omitempty"`
+	Steps int    `json:"steps,omitempty"`
+	// Активность, только для тренировок
+	Activity string `json:"activity,omitempty"`
+	// Продолжительность в формате time.ParseDuration, например 50m
+	Duration string `json:"duration,omitempty"`
+}
+
+// values разбирает поля записи без Packet, время суток относится к дню date.
+// withActivity - запись тренировки. Колонки в ошибках нумеруются так же, как
+// в строке "[время,]шаги,[активность,]продолжительность" с этими значениями
+func (r Record) values(date time.Time, withActivity bool) (spentcalories.Workout, error) {
+	var w spentcalories.Workout
+	col := 0
+	if r.Start != "" {
+		start, err := spentcalories.ParseTime(r.Start, col, date)
+		if err != nil {
+			return w, err
+		}
+		w.Start = start
+		col++
+	}
+
+	if r.Steps <= 0 {
+		return w, spentcalories.NewParseError(spentcalories.FieldSteps, strconv.Itoa(r.Steps), col,
+			spentcalories.ErrSteps, "reason.steps_positive")
+	}
+	w.Steps = r.Steps
+	col++
+
+	if withActivity {
+		if strings.TrimSpace(r.Activity) == "" {
+			return w, spentcalories.NewParseError(spentcalories.FieldActivity, r.Activity, col,
+				spentcalories.ErrActivity, "reason.activity_empty")
+		}
+		w.Activity = r.Activity
+		col++
+	}
+
+	duration, err := spentcalories.ParseDuration(r.Duration, col)
+	if err != nil {
+		return w, err
+	}
+	w.Duration = duration
+	return w, nil
+}
+
+// readRecords читает записи из тела запроса. Тело application/json - одна запись
+// или массив, элементы которого - объекты Record или строки; любое другое тело
+// читается как текст по одной записи на строку, пустые строки пропускаются.
+// Строки возвращаются в поле Packet
+func readRecords(r *http.Request) ([]Record, error) {
+	body := http.MaxBytesReader(nil, r.Body, maxBodySize)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, bodyError(fmt.Errorf("не удалось прочитать тело запроса: %w", err))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return textRecords(data)
+	}
+
+	data = bytes.TrimSpace(data)
+	items := []json.RawMessage{data}
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, badRequest(fmt.Errorf("неверный JSON: %w", err))
+		}
+	}
+
+	records := make([]Record, 0, len(items))
+	for i, item := range items {
+		var rec Record
+		if len(item) > 0 && item[0] == '"' {
+			err = json.Unmarshal(item, &rec.Packet)
+		} else {
+			err = json.Unmarshal(item, &rec)
+		}
+		if err != nil {
+			return nil, badRequest(fmt.Errorf("запись %d: неверный JSON: %w", i+1, err))
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func textRecords(data []byte) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxBodySize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) != "" {
+			records = append(records, Record{Packet: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, badRequest(err)
+	}
+	return records, nil
+}
+
+// decodeJSON разбирает тело запроса в v
+func decodeJSON(r *http.Request, v any) error {
+	d := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return bodyError(fmt.Errorf("неверный JSON: %w", err))
+	}
+	if d.More() {
+		return badRequest(errors.New("неверный JSON: лишние данные после значения"))
+	}
+	return nil
+}
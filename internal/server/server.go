@@ -0,0 +1,375 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/storage"
+)
+
+// Prefix - общий префикс путей API
+const Prefix = "/api/v1"
+
+// Server - HTTP JSON API для расчета и хранения активности пользователей.
+// Результаты считаются по профилю пользователя и сохраняются в store
+type Server struct {
+	store storage.Store
+	opts  options
+	mux   *http.ServeMux
+}
+
+// handlerFunc обрабатывает запрос и возвращает код и тело успешного ответа.
+// loc - язык ответа для текстов ошибок
+type handlerFunc func(r *http.Request, loc i18n.Locale) (int, any, error)
+
+// New создает сервер с хранилищем store
+func New(store storage.Store, opts ...Option) *Server {
+	s := &Server{store: store, opts: newOptions(opts), mux: http.NewServeMux()}
+
+	routes := []struct {
+		pattern string
+		handler handlerFunc
+	}{
+		{"GET /users/{user}/profile", s.getProfile},
+		{"PUT /users/{user}/profile", s.putProfile},
+		{"POST /users/{user}/days", s.postDays},
+		{"GET /users/{user}/days", s.getDays},
+		{"POST /users/{user}/trainings", s.postTrainings},
+		{"GET /users/{user}/trainings", s.getTrainings},
+		{"GET /users/{user}/summary/daily", s.dailySummary},
+		{"GET /users/{user}/summary/weekly", s.weeklySummary},
+	}
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route.pattern, " ")
+		s.mux.Handle(method+" "+Prefix+path, s.handle(route.handler))
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle выбирает язык ответа, вызывает обработчик и записывает ответ в JSON
+func (s *Server) handle(fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc := locale(r)
+		status, body, err := fn(r, loc)
+		if err != nil {
+			var resp ErrorResponse
+			status, resp = errorResponse(err, loc)
+			if status == http.StatusInternalServerError {
+				// Внутренние ошибки не показываются клиенту, только пишутся в журнал
+				s.opts.logger.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+				resp = ErrorResponse{Error: http.StatusText(status)}
+			}
+			body = resp
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			s.opts.logger.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+		}
+	})
+}
+
+// locale возвращает язык из параметра locale или первый поддерживаемый
+// из заголовка Accept-Language, по умолчанию i18n.Default
+func locale(r *http.Request) i18n.Locale {
+	if value := r.URL.Query().Get("locale"); value != "" {
+		if loc, err := i18n.Parse(value); err == nil {
+			return loc
+		}
+	}
+	for _, tag := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		if loc, err := i18n.Parse(tag); err == nil {
+			return loc
+		}
+	}
+	return i18n.Default
+}
+
+// user ищет пользователя из пути запроса
+func (s *Server) user(r *http.Request) (storage.User, error) {
+	u, err := s.store.User(r.Context(), r.PathValue("user"))
+	if errors.Is(err, storage.ErrNotFound) {
+		return storage.User{}, notFound(err)
+	}
+	return u, err
+}
+
+// profile возвращает пользователя и его профиль, без профиля записи не рассчитать
+func (s *Server) profile(r *http.Request) (storage.User, profile.Profile, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return u, profile.Profile{}, err
+	}
+	p, err := s.store.Profile(r.Context(), u.ID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return u, p, &httpError{status: http.StatusConflict,
+			err: fmt.Errorf("%w: сохраните профиль через PUT %s/users/%s/profile", err, Prefix, u.Name)}
+	}
+	return u, p, err
+}
+
+func (s *Server) getProfile(r *http.Request, _ i18n.Locale) (int, any, error) {
+	_, p, err := s.profile(r)
+	if err != nil {
+		var he *httpError
+		if errors.As(err, &he) && he.status == http.StatusConflict {
+			return 0, nil, notFound(he.err)
+		}
+		return 0, nil, err
+	}
+	return http.StatusOK, p, nil
+}
+
+// putProfile проверяет и сохраняет профиль, пользователь создается при первом сохранении
+func (s *Server) putProfile(r *http.Request, _ i18n.Locale) (int, any, error) {
+	var p profile.Profile
+	if err := decodeJSON(r, &p); err != nil {
+		return 0, nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return 0, nil, badRequest(err)
+	}
+	u, err := s.store.EnsureUser(r.Context(), r.PathValue("user"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.store.SaveProfile(r.Context(), u.ID, p); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, p, nil
+}
+
+// compute рассчитывает все записи функцией fn. Если хотя бы одна запись
+// с ошибкой, запрос отклоняется целиком с ошибками всех записей
+func compute[T any](records []Record, loc i18n.Locale, fn func(Record) (T, error)) ([]T, error) {
+	if len(records) == 0 {
+		return nil, badRequest(errors.New("нет записей"))
+	}
+	results := make([]T, 0, len(records))
+	var failed []RecordError
+	for i, rec := range records {
+		v, err := fn(rec)
+		if err != nil {
+			failed = append(failed, recordError(i+1, err, loc))
+			continue
+		}
+		results = append(results, v)
+	}
+	if len(failed) > 0 {
+		return nil, &httpError{
+			status:  http.StatusBadRequest,
+			err:     i18n.NewError("server.rejected", len(failed), len(records)),
+			records: failed,
+		}
+	}
+	return results, nil
+}
+
+func (s *Server) postDays(r *http.Request, loc i18n.Locale) (int, any, error) {
+	u, p, err := s.profile(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	date, err := s.date(r, "date")
+	if err != nil {
+		return 0, nil, err
+	}
+	records, err := readRecords(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	results, err := compute(records, loc, func(rec Record) (daysteps.DayActionResult, error) {
+		if rec.Packet != "" {
+			return daysteps.DayActionFor(rec.Packet, p, daysteps.WithDate(date))
+		}
+		w, err := rec.values(date, false)
+		if err != nil {
+			return daysteps.DayActionResult{}, err
+		}
+		return daysteps.ComputeDayFor(w.Start, w.Steps, w.Duration, p)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ids, err := s.store.AddDays(r.Context(), u.ID, results...)
+	if err != nil {
+		return 0, nil, err
+	}
+	days := make([]storage.Day, len(results))
+	for i, d := range results {
+		days[i] = storage.Day{ID: ids[i], UserID: u.ID, DayActionResult: d}
+	}
+	return http.StatusCreated, days, nil
+}
+
+func (s *Server) postTrainings(r *http.Request, loc i18n.Locale) (int, any, error) {
+	u, p, err := s.profile(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	date, err := s.date(r, "date")
+	if err != nil {
+		return 0, nil, err
+	}
+	model := spentcalories.SpeedModel
+	if name := r.URL.Query().Get("model"); name != "" {
+		if model, err = spentcalories.ParseModel(name); err != nil {
+			return 0, nil, badRequest(err)
+		}
+	}
+	records, err := readRecords(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	results, err := compute(records, loc, func(rec Record) (spentcalories.TrainingResult, error) {
+		opts := []spentcalories.Option{spentcalories.WithDate(date), spentcalories.WithModel(model)}
+		if rec.Packet != "" {
+			return spentcalories.TrainingFor(rec.Packet, p, opts...)
+		}
+		w, err := rec.values(date, true)
+		if err != nil {
+			return spentcalories.TrainingResult{}, err
+		}
+		return spentcalories.ComputeFor(w, p, opts...)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ids, err := s.store.AddTrainings(r.Context(), u.ID, results...)
+	if err != nil {
+		return 0, nil, err
+	}
+	trainings := make([]storage.Training, len(results))
+	for i, t := range results {
+		trainings[i] = storage.Training{ID: ids[i], UserID: u.ID, TrainingResult: t}
+	}
+	return http.StatusCreated, trainings, nil
+}
+
+func (s *Server) getDays(r *http.Request, _ i18n.Locale) (int, any, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	q, err := s.query(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	days, err := s.store.Days(r.Context(), u.ID, q)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, nonNil(days), nil
+}
+
+func (s *Server) getTrainings(r *http.Request, _ i18n.Locale) (int, any, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	q, err := s.query(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	trainings, err := s.store.Trainings(r.Context(), u.ID, q.WithActivities(r.URL.Query()["activity"]...))
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, nonNil(trainings), nil
+}
+
+func (s *Server) dailySummary(r *http.Request, _ i18n.Locale) (int, any, error) {
+	return s.summary(r, startOfDay, 1)
+}
+
+func (s *Server) weeklySummary(r *http.Request, _ i18n.Locale) (int, any, error) {
+	return s.summary(r, startOfWeek, 7)
+}
+
+// summary подводит итоги за n дней, начало периода находит start по дате из
+// параметра date, по умолчанию - по сегодняшней
+func (s *Server) summary(r *http.Request, start func(time.Time) time.Time, n int) (int, any, error) {
+	u, err := s.user(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	date, err := s.date(r, "date")
+	if err != nil {
+		return 0, nil, err
+	}
+	if date.IsZero() {
+		date = s.opts.now().In(s.opts.location)
+	}
+
+	from := start(date)
+	q := storage.Between(from, from.AddDate(0, 0, n))
+	days, err := s.store.Days(r.Context(), u.ID, q)
+	if err != nil {
+		return 0, nil, err
+	}
+	trainings, err := s.store.Trainings(r.Context(), u.ID, q)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, summarize(from, n, days, trainings), nil
+}
+
+// date разбирает дату ГГГГ-ММ-ДД из параметра name в часовом поясе сервера,
+// отсутствующий параметр - нулевое время
+func (s *Server) date(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseInLocation(time.DateOnly, value, s.opts.location)
+	if err != nil {
+		return time.Time{}, badRequest(fmt.Errorf("неверная дата %s=%q: ожидается ГГГГ-ММ-ДД", name, value))
+	}
+	return d, nil
+}
+
+// query собирает запрос к хранилищу из параметров from и to (даты включительно) и limit
+func (s *Server) query(r *http.Request) (storage.Query, error) {
+	var q storage.Query
+	var err error
+	if q.From, err = s.date(r, "from"); err != nil {
+		return q, err
+	}
+	if q.To, err = s.date(r, "to"); err != nil {
+		return q, err
+	}
+	if !q.To.IsZero() {
+		// Конец периода включительно
+		q.To = q.To.AddDate(0, 0, 1)
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if q.Limit, err = strconv.Atoi(value); err != nil || q.Limit < 0 {
+			return q, badRequest(fmt.Errorf("неверное ограничение limit=%q", value))
+		}
+	}
+	return q, nil
+}
+
+// nonNil заменяет nil на пустой срез, чтобы в JSON был [] вместо null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
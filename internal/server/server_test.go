@@ -0,0 +1,337 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	store  *storage.SQLite
+	server *Server
+	logs   bytes.Buffer
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+var (
+	msk = time.FixedZone("MSK", 3*60*60)
+	// Среда 6 марта 2024 года
+	now = time.Date(2024, time.March, 6, 15, 0, 0, 0, msk)
+)
+
+const profileJSON = `{"weight": 75, "height": 1.75, "birth_date": "1994-01-01", "sex": "male"}`
+
+func (suite *ServerTestSuite) SetupTest() {
+	var err error
+	suite.store, err = storage.Open(context.Background(), filepath.Join(suite.T().TempDir(), "tracker.db"))
+	assert.NoError(suite.T(), err)
+	suite.logs.Reset()
+	suite.server = New(suite.store, WithLocation(msk), withNow(func() time.Time { return now }),
+		WithLogger(log.New(&suite.logs, "", 0)))
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.store.Close())
+}
+
+// do выполняет запрос и разбирает JSON-ответ в v
+func (suite *ServerTestSuite) do(method, path, contentType, body string, v any, header ...string) int {
+	req := httptest.NewRequest(method, Prefix+path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	suite.server.ServeHTTP(rec, req)
+
+	assert.Equal(suite.T(), "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	if v != nil {
+		assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), v), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func (suite *ServerTestSuite) putProfile() {
+	code := suite.do(http.MethodPut, "/users/anna/profile", "application/json", profileJSON, nil)
+	assert.Equal(suite.T(), http.StatusOK, code)
+}
+
+func (suite *ServerTestSuite) TestProfile() {
+	var resp ErrorResponse
+	code := suite.do(http.MethodGet, "/users/anna/profile", "", "", &resp)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+	assert.NotEmpty(suite.T(), resp.Error)
+
+	suite.putProfile()
+	var p map[string]any
+	code = suite.do(http.MethodGet, "/users/anna/profile", "", "", &p)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Equal(suite.T(), 75.0, p["weight"])
+	assert.Equal(suite.T(), "1994-01-01", p["birth_date"])
+
+	// Ошибка проверки профиля указывает поле
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPut, "/users/anna/profile", "application/json", `{"weight": 5, "height": 1.75}`, &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Equal(suite.T(), "weight", resp.Field)
+
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPut, "/users/anna/profile", "application/json", `{"weight": 75, "height": 1.75, "color": 1}`, &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Contains(suite.T(), resp.Error, "неверный JSON")
+}
+
+func (suite *ServerTestSuite) TestPostDays() {
+	var resp ErrorResponse
+	code := suite.do(http.MethodPost, "/users/anna/days", "text/plain", "678,50m", &resp)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+
+	_, err := suite.store.AddUser(context.Background(), "anna")
+	assert.NoError(suite.T(), err)
+	code = suite.do(http.MethodPost, "/users/anna/days", "text/plain", "678,50m", &resp)
+	assert.Equal(suite.T(), http.StatusConflict, code)
+	suite.putProfile()
+
+	var days []storage.Day
+	code = suite.do(http.MethodPost, "/users/anna/days?date=2024-03-05", "text/plain",
+		"09:00,6000,1h\n\n2024-03-06T10:00:00+03:00,3000,30m\r\n", &days)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	if assert.Len(suite.T(), days, 2) {
+		assert.NotZero(suite.T(), days[0].ID)
+		assert.Equal(suite.T(), 6000, days[0].Steps)
+		assert.True(suite.T(), time.Date(2024, time.March, 5, 9, 0, 0, 0, msk).Equal(days[0].Start))
+		assert.Greater(suite.T(), days[0].Calories, 0.0)
+	}
+
+	// JSON: одна запись, массив объектов и строк
+	code = suite.do(http.MethodPost, "/users/anna/days", "application/json",
+		`{"start": "2024-03-07T08:00:00+03:00", "steps": 1000, "duration": "10m"}`, &days)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	assert.Len(suite.T(), days, 1)
+	code = suite.do(http.MethodPost, "/users/anna/days", "application/json; charset=utf-8",
+		`[{"packet": "2024-03-07T09:00:00+03:00,500,5m"}, "2024-03-07T10:00:00+03:00,700,7m"]`, &days)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	assert.Len(suite.T(), days, 2)
+
+	code = suite.do(http.MethodGet, "/users/anna/days?from=2024-03-07&to=2024-03-07", "", "", &days)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	steps := make([]int, 0, len(days))
+	for _, d := range days {
+		steps = append(steps, d.Steps)
+	}
+	assert.Equal(suite.T(), []int{1000, 500, 700}, steps)
+
+	code = suite.do(http.MethodGet, "/users/anna/days?limit=1", "", "", &days)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.Len(suite.T(), days, 1)
+}
+
+func (suite *ServerTestSuite) TestParseErrors() {
+	suite.putProfile()
+
+	var resp ErrorResponse
+	code := suite.do(http.MethodPost, "/users/anna/days", "text/plain", "678,50m\nабв,50m\n100\n", &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Equal(suite.T(), "записи не приняты: с ошибками 2 из 3", resp.Error)
+	if assert.Len(suite.T(), resp.Records, 2) {
+		r := resp.Records[0]
+		assert.Equal(suite.T(), 2, r.Record)
+		assert.Equal(suite.T(), spentcalories.FieldSteps, r.Field)
+		assert.Equal(suite.T(), "абв", r.Value)
+		if assert.NotNil(suite.T(), r.Column) {
+			assert.Equal(suite.T(), 0, *r.Column)
+		}
+		assert.Equal(suite.T(), "reason.steps_not_integer", r.Code)
+		assert.NotEmpty(suite.T(), r.Message)
+
+		r = resp.Records[1]
+		assert.Equal(suite.T(), 3, r.Record)
+		assert.Equal(suite.T(), spentcalories.FieldRecord, r.Field)
+		if assert.NotNil(suite.T(), r.Column) {
+			assert.Equal(suite.T(), -1, *r.Column)
+		}
+	}
+
+	// Запрос отклоняется целиком, корректная запись тоже не сохраняется
+	var days []storage.Day
+	suite.do(http.MethodGet, "/users/anna/days", "", "", &days)
+	assert.Empty(suite.T(), days)
+
+	// Язык ошибок выбирается по Accept-Language
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPost, "/users/anna/trainings", "application/json",
+		`{"steps": 1000, "activity": "Полет", "duration": "1h"}`, &resp, "Accept-Language", "en-US,en;q=0.9")
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	assert.Equal(suite.T(), "records rejected: 1 of 1 failed", resp.Error)
+	if assert.Len(suite.T(), resp.Records, 1) {
+		assert.Equal(suite.T(), spentcalories.FieldActivity, resp.Records[0].Field)
+		assert.Equal(suite.T(), "Полет", resp.Records[0].Value)
+	}
+
+	// Значения полей JSON не склеиваются в строку, поэтому запятая в них
+	// не сдвигает остальные поля, а ошибка относится к своему полю
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPost, "/users/anna/trainings", "application/json",
+		`[{"steps": 1000, "activity": "Бег,1h", "duration": "1h"}, {"start": "08:00,1000", "steps": 1000, "duration": "1h"}]`, &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	if assert.Len(suite.T(), resp.Records, 2) {
+		assert.Equal(suite.T(), spentcalories.FieldActivity, resp.Records[0].Field)
+		assert.Equal(suite.T(), "Бег,1h", resp.Records[0].Value)
+		assert.Equal(suite.T(), spentcalories.FieldTime, resp.Records[1].Field)
+		assert.Equal(suite.T(), "08:00,1000", resp.Records[1].Value)
+	}
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPost, "/users/anna/days", "application/json", `{"steps": 0, "duration": "1h"}`, &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	if assert.Len(suite.T(), resp.Records, 1) && assert.NotNil(suite.T(), resp.Records[0].Column) {
+		assert.Equal(suite.T(), spentcalories.FieldSteps, resp.Records[0].Field)
+		assert.Equal(suite.T(), 0, *resp.Records[0].Column)
+	}
+
+	// Слишком большое тело отклоняется с кодом 413
+	large := strings.Repeat("678,50m\n", maxBodySize/8+1)
+	code = suite.do(http.MethodPost, "/users/anna/days", "text/plain", large, &resp)
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, code)
+	large = `{"weight": 75, "height": 1.75, "sex": "` + strings.Repeat("x", maxBodySize) + `"}`
+	code = suite.do(http.MethodPut, "/users/anna/profile", "application/json", large, &resp)
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, code)
+
+	tests := []struct {
+		name, path, contentType, body string
+	}{
+		{"пустое тело", "/users/anna/days", "text/plain", "\n\n"},
+		{"неверный JSON", "/users/anna/days", "application/json", `[{"steps": "много"}]`},
+		{"неверная дата", "/users/anna/days?date=05.03.2024", "text/plain", "678,50m"},
+		{"неизвестная модель", "/users/anna/trainings?model=magic", "text/plain", "1000,Бег,1h"},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			resp := ErrorResponse{}
+			code := suite.do(http.MethodPost, tt.path, tt.contentType, tt.body, &resp)
+			assert.Equal(suite.T(), http.StatusBadRequest, code)
+			assert.NotEmpty(suite.T(), resp.Error)
+			assert.Empty(suite.T(), resp.Records)
+		})
+	}
+}
+
+func (suite *ServerTestSuite) TestTrainings() {
+	suite.putProfile()
+
+	var trainings []storage.Training
+	code := suite.do(http.MethodPost, "/users/anna/trainings?date=2024-03-05&model=met", "text/plain",
+		"08:00,8000,Бег,1h\n18:00,5000,Ходьба,1h\n", &trainings)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	if assert.Len(suite.T(), trainings, 2) {
+		assert.Equal(suite.T(), "Бег", trainings[0].Activity)
+		assert.Equal(suite.T(), spentcalories.METModel, trainings[0].Model)
+	}
+
+	code = suite.do(http.MethodGet, "/users/anna/trainings?activity=Ходьба", "", "", &trainings)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	if assert.Len(suite.T(), trainings, 1) {
+		assert.Equal(suite.T(), 5000, trainings[0].Steps)
+	}
+
+	// Пустая выборка - пустой массив, а не null
+	req := httptest.NewRequest(http.MethodGet, Prefix+"/users/anna/trainings?from=2025-01-01", nil)
+	rec := httptest.NewRecorder()
+	suite.server.ServeHTTP(rec, req)
+	assert.Equal(suite.T(), "[]\n", rec.Body.String())
+
+	var resp ErrorResponse
+	code = suite.do(http.MethodGet, "/users/anna/trainings?limit=-1", "", "", &resp)
+	assert.Equal(suite.T(), http.StatusBadRequest, code)
+	code = suite.do(http.MethodGet, "/users/boris/trainings", "", "", &resp)
+	assert.Equal(suite.T(), http.StatusNotFound, code)
+}
+
+func (suite *ServerTestSuite) TestSummary() {
+	suite.putProfile()
+	var days []storage.Day
+	// Понедельник 4 марта, среда 6 марта и следующий понедельник 11 марта
+	code := suite.do(http.MethodPost, "/users/anna/days", "text/plain",
+		"2024-03-04T09:00:00+03:00,6000,1h\n2024-03-06T09:00:00+03:00,3000,30m\n2024-03-06T20:00:00+03:00,1000,10m\n"+
+			"2024-03-11T09:00:00+03:00,2000,20m\n", &days)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+	var trainings []storage.Training
+	// 2024-03-06T22:30Z - уже 7 марта по Москве
+	code = suite.do(http.MethodPost, "/users/anna/trainings", "text/plain",
+		"2024-03-06T08:00:00+03:00,8000,Бег,1h\n2024-03-06T22:30:00Z,4000,Бег,30m\n", &trainings)
+	assert.Equal(suite.T(), http.StatusCreated, code)
+
+	// Без даты - сегодня, 6 марта
+	var daily Summary
+	code = suite.do(http.MethodGet, "/users/anna/summary/daily", "", "", &daily)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.True(suite.T(), time.Date(2024, time.March, 6, 0, 0, 0, 0, msk).Equal(daily.From))
+	assert.Equal(suite.T(), 2, daily.Packets)
+	assert.Equal(suite.T(), 4000, daily.Steps)
+	assert.Equal(suite.T(), 1, daily.Trainings)
+	assert.InDelta(suite.T(), days[1].Calories+days[2].Calories, daily.Walking, 1e-9)
+	assert.InDelta(suite.T(), trainings[0].Calories, daily.Training, 1e-9)
+	assert.InDelta(suite.T(), daily.Walking+daily.Training, daily.Total, 1e-9)
+	assert.Empty(suite.T(), daily.Days)
+
+	var weekly Summary
+	code = suite.do(http.MethodGet, "/users/anna/summary/weekly?date=2024-03-10", "", "", &weekly)
+	assert.Equal(suite.T(), http.StatusOK, code)
+	assert.True(suite.T(), time.Date(2024, time.March, 4, 0, 0, 0, 0, msk).Equal(weekly.From))
+	assert.True(suite.T(), time.Date(2024, time.March, 11, 0, 0, 0, 0, msk).Equal(weekly.To))
+	assert.Equal(suite.T(), 3, weekly.Packets)
+	assert.Equal(suite.T(), 10000, weekly.Steps)
+	assert.Equal(suite.T(), 2, weekly.Trainings)
+	if assert.Len(suite.T(), weekly.Days, 7) {
+		assert.Equal(suite.T(), 6000, weekly.Days[0].Steps)
+		assert.Equal(suite.T(), 0, weekly.Days[1].Steps)
+		assert.Equal(suite.T(), 4000, weekly.Days[2].Steps)
+		assert.Equal(suite.T(), 1, weekly.Days[2].Trainings)
+		assert.Equal(suite.T(), 1, weekly.Days[3].Trainings)
+	}
+}
+
+func (suite *ServerTestSuite) TestInternalError() {
+	suite.putProfile()
+	assert.NoError(suite.T(), suite.store.Close())
+
+	var resp ErrorResponse
+	code := suite.do(http.MethodGet, "/users/anna/days", "", "", &resp)
+	assert.Equal(suite.T(), http.StatusInternalServerError, code)
+	// Подробности только в журнале сервера
+	assert.Equal(suite.T(), http.StatusText(http.StatusInternalServerError), resp.Error)
+	assert.Contains(suite.T(), suite.logs.String(), "GET /api/v1/users/anna/days")
+
+	// Ошибка хранилища при сохранении профиля - тоже внутренняя, а не ошибка запроса
+	resp = ErrorResponse{}
+	code = suite.do(http.MethodPut, "/users/boris/profile", "application/json", profileJSON, &resp)
+	assert.Equal(suite.T(), http.StatusInternalServerError, code)
+	assert.Contains(suite.T(), suite.logs.String(), "PUT /api/v1/users/boris/profile")
+
+	// Закрытое хранилище открывается заново, чтобы TearDownTest мог его закрыть
+	suite.store, _ = storage.Open(context.Background(), filepath.Join(suite.T().TempDir(), "other.db"))
+}
+
+func (suite *ServerTestSuite) TestNotFoundRoute() {
+	req := httptest.NewRequest(http.MethodDelete, Prefix+"/users/anna/days", nil)
+	rec := httptest.NewRecorder()
+	suite.server.ServeHTTP(rec, req)
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, rec.Code)
+}
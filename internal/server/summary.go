@@ -0,0 +1,100 @@
+package server
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/storage"
+)
+
+// Summary - итоги за период: пакеты дневной активности и тренировки.
+// Записи без времени начала не относятся ни к одному периоду
+type Summary struct {
+	// Начало периода включительно и конец не включительно
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Шаги, дистанция и калории пакетов дневной активности
+	Packets  int     `json:"packets"`
+	Steps    int     `json:"steps"`
+	Distance float64 `json:"distance_km"`
+	Walking  float64 `json:"walking_calories"`
+	// Количество тренировок и потраченные на них калории
+	Trainings int     `json:"trainings"`
+	Training  float64 `json:"training_calories"`
+	// Ходьба + тренировки
+	Total float64 `json:"total_calories"`
+	// Итоги по дням для недельной сводки
+	Days []Summary `json:"days,omitempty"`
+}
+
+func (s *Summary) addDay(d storage.Day) {
+	s.Packets++
+	s.Steps += d.Steps
+	s.Distance += d.Distance
+	s.Walking += d.Calories
+	s.Total += d.Calories
+}
+
+func (s *Summary) addTraining(t storage.Training) {
+	s.Trainings++
+	s.Training += t.Calories
+	s.Total += t.Calories
+}
+
+// startOfDay возвращает полночь дня t в часовом поясе t
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek возвращает полночь понедельника недели, в которую входит t
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	// В Go неделя начинается с воскресенья, а у нас - с понедельника
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// summarize подводит итоги записей за n дней с полуночи from, а при n > 1
+// добавляет итоги каждого дня. Записи вне периода пропускаются
+func summarize(from time.Time, n int, days []storage.Day, trainings []storage.Training) Summary {
+	total := Summary{From: from, To: from.AddDate(0, 0, n)}
+	if n > 1 {
+		total.Days = make([]Summary, n)
+		for i := range total.Days {
+			total.Days[i] = Summary{From: from.AddDate(0, 0, i), To: from.AddDate(0, 0, i+1)}
+		}
+	}
+
+	// index возвращает номер дня периода для времени t или -1
+	index := func(t time.Time) int {
+		if t.IsZero() || t.Before(total.From) || !t.Before(total.To) {
+			return -1
+		}
+		// Дни сравниваются по календарю, потому что при переходе на летнее время в сутках не 24 часа
+		day := startOfDay(t.In(from.Location()))
+		for i := range n {
+			if day.Equal(from.AddDate(0, 0, i)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, d := range days {
+		if i := index(d.Start); i >= 0 {
+			total.addDay(d)
+			if total.Days != nil {
+				total.Days[i].addDay(d)
+			}
+		}
+	}
+	for _, t := range trainings {
+		if i := index(t.Start); i >= 0 {
+			total.addTraining(t)
+			if total.Days != nil {
+				total.Days[i].addTraining(t)
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,151 @@
+package spentcalories
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+)
+
+// CaloriesFunc рассчитывает потраченные калории, сигнатура совпадает с RunningSpentCalories и WalkingSpentCalories
+type CaloriesFunc func(steps int, weight, height float64, duration time.Duration) (float64, error)
+
+// Activity описывает вид тренировки, который умеет обрабатывать TrainingInfo
+type Activity struct {
+	// Основное название активности, оно же выводится в результате
+	Name string
+	// Дополнительные названия, по которым активность тоже находится
+	Aliases []string
+	// Названия на других языках, по ним активность тоже находится
+	Titles map[i18n.Locale]string
+	// Функция расчета калорий
+	Calories CaloriesFunc
+	// Таблица MET по скорости для METModel, может быть пустой
+	MET []METBand
+}
+
+// Реестр зарегистрированных активностей, ключи хранятся в нижнем регистре
+var registry = struct {
+	sync.RWMutex
+	byName map[string]*Activity
+}{byName: make(map[string]*Activity)}
+
+func init() {
+	builtin := []Activity{
+		{Name: "Бег", Titles: map[i18n.Locale]string{i18n.English: "Running"}, Calories: RunningSpentCalories, MET: runningMET},
+		{Name: "Ходьба", Titles: map[i18n.Locale]string{i18n.English: "Walking"}, Calories: WalkingSpentCalories, MET: walkingMET},
+	}
+	for _, a := range builtin {
+		if err := RegisterActivity(a); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func activityKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RegisterActivity добавляет активность в реестр. Имя и псевдонимы не должны быть заняты другой активностью
+func RegisterActivity(a Activity) error {
+	if activityKey(a.Name) == "" {
+		return errors.New("название активности не может быть пустым")
+	}
+	if a.Calories == nil {
+		return fmt.Errorf("для активности %s не задана функция расчета калорий", a.Name)
+	}
+
+	keys := []string{activityKey(a.Name)}
+	for _, alias := range a.Aliases {
+		if activityKey(alias) == "" {
+			return fmt.Errorf("пустой псевдоним у активности %s", a.Name)
+		}
+		keys = append(keys, activityKey(alias))
+	}
+	for _, title := range a.Titles {
+		// Название может совпадать с основным, например у международных активностей
+		if key := activityKey(title); key != "" && key != activityKey(a.Name) {
+			keys = append(keys, key)
+		}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, key := range keys {
+		if _, ok := registry.byName[key]; ok {
+			return fmt.Errorf("активность %q уже зарегистрирована", key)
+		}
+	}
+
+	// Копируем срезы, чтобы вызывающий код не мог изменить реестр снаружи
+	a.Aliases = append([]string(nil), a.Aliases...)
+	a.MET = append([]METBand(nil), a.MET...)
+	titles := make(map[i18n.Locale]string, len(a.Titles))
+	for loc, title := range a.Titles {
+		titles[loc] = title
+	}
+	a.Titles = titles
+	for _, key := range keys {
+		registry.byName[key] = &a
+	}
+	return nil
+}
+
+// Title возвращает название активности на языке loc или основное название
+func (a Activity) Title(loc i18n.Locale) string {
+	if title, ok := a.Titles[loc]; ok && title != "" {
+		return title
+	}
+	return a.Name
+}
+
+// UnregisterActivity удаляет активность вместе со всеми её псевдонимами
+func UnregisterActivity(name string) bool {
+	registry.Lock()
+	defer registry.Unlock()
+
+	a, ok := registry.byName[activityKey(name)]
+	if !ok {
+		return false
+	}
+	for key, v := range registry.byName {
+		if v == a {
+			delete(registry.byName, key)
+		}
+	}
+	return true
+}
+
+// LookupActivity ищет активность по названию или псевдониму без учета регистра
+func LookupActivity(name string) (Activity, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	a, ok := registry.byName[activityKey(name)]
+	if !ok {
+		return Activity{}, false
+	}
+	return *a, true
+}
+
+// Activities возвращает все зарегистрированные активности, отсортированные по названию
+func Activities() []Activity {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	seen := make(map[*Activity]bool)
+	var list []Activity
+	for _, a := range registry.byName {
+		if !seen[a] {
+			seen[a] = true
+			list = append(list, *a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,65 @@
+package spentcalories
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestRegisterActivity() {
+	cycling := Activity{
+		Name:    "Велосипед",
+		Aliases: []string{"Вело", "Cycling"},
+		Calories: func(steps int, weight, height float64, duration time.Duration) (float64, error) {
+			return weight * duration.Hours() * 8, nil
+		},
+	}
+	assert.NoError(suite.T(), RegisterActivity(cycling))
+	defer UnregisterActivity("Велосипед")
+
+	got, err := TrainingInfo("1000,вело,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), got, "Тип тренировки: Велосипед\n")
+	assert.Contains(suite.T(), got, "Сожгли калорий: 600.00\n")
+
+	a, ok := LookupActivity("CYCLING")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "Велосипед", a.Name)
+
+	// Повторная регистрация имени или псевдонима запрещена
+	assert.Error(suite.T(), RegisterActivity(Activity{Name: "cycling", Calories: cycling.Calories}))
+	assert.Error(suite.T(), RegisterActivity(Activity{Name: "Плавание"}))
+	assert.Error(suite.T(), RegisterActivity(Activity{Name: " ", Calories: cycling.Calories}))
+}
+
+func (suite *SpentCaloriesTestSuite) TestUnregisterActivity() {
+	assert.NoError(suite.T(), RegisterActivity(Activity{Name: "Поход", Aliases: []string{"Hiking"}, Calories: WalkingSpentCalories}))
+	assert.True(suite.T(), UnregisterActivity("hiking"))
+
+	_, ok := LookupActivity("Поход")
+	assert.False(suite.T(), ok)
+	assert.False(suite.T(), UnregisterActivity("Поход"))
+
+	names := []string{}
+	for _, a := range Activities() {
+		names = append(names, a.Name)
+	}
+	assert.Equal(suite.T(), []string{"Бег", "Ходьба"}, names)
+}
+
+func (suite *SpentCaloriesTestSuite) TestLocalizedActivity() {
+	a, ok := LookupActivity("running")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "Бег", a.Name)
+	assert.Equal(suite.T(), "Running", a.Title(i18n.English))
+	assert.Equal(suite.T(), "Бег", a.Title(i18n.Russian))
+
+	got, err := Training("6000,Walking,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Ходьба", got.Activity)
+	assert.Equal(suite.T(), "Training type: Walking\nDuration: 1.00 h.\nDistance: 4.72 km.\nSpeed: 4.72 km/h\nCalories burned: 177.19\n",
+		got.Format(i18n.English, units.Metric))
+	assert.Equal(suite.T(), got.String(), got.Format(i18n.Russian, units.Metric))
+}
@@ -0,0 +1,100 @@
+package spentcalories
+
+import (
+	"fmt"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+)
+
+// BMRFormula определяет формулу основного обмена - калорий, которые организм тратит в покое за сутки
+type BMRFormula int
+
+const (
+	// MifflinStJeor - формула Миффлина - Сан Жеора (1990), используется по умолчанию
+	MifflinStJeor BMRFormula = iota
+	// HarrisBenedict - формула Харриса - Бенедикта в редакции Roza и Shizgal (1984)
+	HarrisBenedict
+)
+
+func (f BMRFormula) String() string {
+	switch f {
+	case MifflinStJeor:
+		return "mifflin"
+	case HarrisBenedict:
+		return "harris"
+	default:
+		return fmt.Sprintf("BMRFormula(%d)", int(f))
+	}
+}
+
+// MarshalText кодирует формулу ее названием
+func (f BMRFormula) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText разбирает формулу по названию
+func (f *BMRFormula) UnmarshalText(text []byte) error {
+	parsed, err := ParseBMRFormula(string(text))
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
+// ParseBMRFormula возвращает формулу по названию: mifflin или harris
+func ParseBMRFormula(name string) (BMRFormula, error) {
+	for _, f := range []BMRFormula{MifflinStJeor, HarrisBenedict} {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("неизвестная формула основного обмена %q", name)
+}
+
+// Коэффициенты формул основного обмена в ккал/сутки:
+// константа + вес * weight + рост в см * height + возраст * age
+type bmrCoefficients struct {
+	constant, weight, height, age float64
+}
+
+var bmrCoefficientsBySex = map[BMRFormula]map[profile.Sex]bmrCoefficients{
+	MifflinStJeor: {
+		profile.Male:   {constant: 5, weight: 10, height: 6.25, age: -5},
+		profile.Female: {constant: -161, weight: 10, height: 6.25, age: -5},
+	},
+	HarrisBenedict: {
+		profile.Male:   {constant: 88.362, weight: 13.397, height: 4.799, age: -5.677},
+		profile.Female: {constant: 447.593, weight: 9.247, height: 3.098, age: -4.330},
+	},
+}
+
+// BMR рассчитывает основной обмен в ккал за сутки по весу в кг, росту в м,
+// возрасту и полу. Если пол неизвестен, берется среднее формул для мужчин и женщин
+func BMR(formula BMRFormula, weight, height float64, age int, sex profile.Sex) (float64, error) {
+	// Проверяем корректность входных параметров
+	coefficients, ok := bmrCoefficientsBySex[formula]
+	if !ok {
+		return 0, fmt.Errorf("неизвестная формула основного обмена %s", formula)
+	}
+	if weight <= 0 {
+		return 0, errWeight
+	}
+	if height <= 0 {
+		return 0, errHeight
+	}
+	if age <= 0 {
+		return 0, errAge
+	}
+
+	calc := func(c bmrCoefficients) float64 {
+		return c.constant + c.weight*weight + c.height*height*cmInM + c.age*float64(age)
+	}
+
+	switch sex {
+	case profile.Male, profile.Female:
+		return calc(coefficients[sex]), nil
+	default:
+		return (calc(coefficients[profile.Male]) + calc(coefficients[profile.Female])) / 2, nil
+	}
+}
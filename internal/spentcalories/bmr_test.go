@@ -0,0 +1,61 @@
+package spentcalories
+
+import (
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestBMR() {
+	tests := []struct {
+		name    string
+		formula BMRFormula
+		weight  float64
+		height  float64
+		age     int
+		sex     profile.Sex
+		want    float64
+		wantErr error
+	}{
+		// 10 * 75 + 6.25 * 175 - 5 * 30 + 5
+		{name: "Миффлин, мужчина", formula: MifflinStJeor, weight: 75, height: 1.75, age: 30, sex: profile.Male, want: 1698.75},
+		{name: "Миффлин, женщина", formula: MifflinStJeor, weight: 60, height: 1.65, age: 25, sex: profile.Female, want: 1345.25},
+		{name: "Миффлин, пол неизвестен", formula: MifflinStJeor, weight: 75, height: 1.75, age: 30, want: 1615.75},
+		// 88.362 + 13.397 * 75 + 4.799 * 175 - 5.677 * 30
+		{name: "Харрис - Бенедикт, мужчина", formula: HarrisBenedict, weight: 75, height: 1.75, age: 30, sex: profile.Male, want: 1762.652},
+		{name: "Харрис - Бенедикт, женщина", formula: HarrisBenedict, weight: 60, height: 1.65, age: 25, sex: profile.Female, want: 1405.333},
+		{name: "нет веса", formula: MifflinStJeor, height: 1.75, age: 30, wantErr: errWeight},
+		{name: "нет роста", formula: MifflinStJeor, weight: 75, age: 30, wantErr: errHeight},
+		{name: "нет возраста", formula: MifflinStJeor, weight: 75, height: 1.75, wantErr: errAge},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := BMR(tt.formula, tt.weight, tt.height, tt.age, tt.sex)
+			if tt.wantErr != nil {
+				assert.ErrorIs(suite.T(), err, tt.wantErr)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+
+	_, err := BMR(BMRFormula(7), 75, 1.75, 30, profile.Male)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpentCaloriesTestSuite) TestParseBMRFormula() {
+	for _, f := range []BMRFormula{MifflinStJeor, HarrisBenedict} {
+		got, err := ParseBMRFormula(f.String())
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), f, got)
+	}
+
+	_, err := ParseBMRFormula("katch")
+	assert.Error(suite.T(), err)
+
+	var f BMRFormula
+	assert.NoError(suite.T(), f.UnmarshalText([]byte("harris")))
+	assert.Equal(suite.T(), HarrisBenedict, f)
+	assert.Error(suite.T(), f.UnmarshalText([]byte("katch")))
+}
@@ -0,0 +1,87 @@
+package spentcalories
+
+import (
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+)
+
+// Поля записи, к которым может относиться ошибка разбора
+const (
+	// FieldRecord - ошибка относится ко всей записи, например неверное число значений
+	FieldRecord   = "record"
+	FieldTime     = "time"
+	FieldSteps    = "steps"
+	FieldActivity = "activity"
+	FieldDuration = "duration"
+)
+
+// Ошибки разбора записей, проверяются через errors.Is
+var (
+	ErrFieldCount      error = i18n.NewError("err.field_count")
+	ErrTime            error = i18n.NewError("err.time")
+	ErrSteps           error = i18n.NewError("err.steps")
+	ErrActivity        error = i18n.NewError("err.activity")
+	ErrDuration        error = i18n.NewError("err.duration")
+	ErrUnknownActivity error = i18n.NewError("err.unknown_activity")
+)
+
+// Ошибки проверки параметров расчета
+var (
+	errWeight    = i18n.NewError("err.weight_positive")
+	errHeight    = i18n.NewError("err.height_positive")
+	errSteps     = i18n.NewError("err.steps_positive")
+	errDuration  = i18n.NewError("err.duration_positive")
+	errHeartRate = i18n.NewError("err.heart_rate_positive")
+	errAge       = i18n.NewError("err.age_positive")
+)
+
+// ParseError описывает ошибку в конкретном поле записи, извлекается через errors.As
+type ParseError struct {
+	// Поле записи, одна из констант Field*
+	Field string
+	// Исходное значение поля
+	Value string
+	// Номер колонки начиная с нуля, -1 если ошибка относится ко всей записи
+	Column int
+	// Пояснение причины на языке i18n.Default
+	Reason string
+	// Одна из ошибок Err*
+	Err error
+	// Ключ причины в каталоге i18n и аргументы для перевода
+	Code string
+	Args []any
+}
+
+// NewParseError создает ошибку разбора, причина задается ключом каталога i18n
+func NewParseError(field, value string, column int, err error, code string, args ...any) *ParseError {
+	return &ParseError{
+		Field:  field,
+		Value:  value,
+		Column: column,
+		Reason: i18n.Default.T(code, args...),
+		Err:    err,
+		Code:   code,
+		Args:   args,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return e.Localize(i18n.Default)
+}
+
+// Localize возвращает текст ошибки на языке loc
+func (e *ParseError) Localize(loc i18n.Locale) string {
+	reason := e.Reason
+	if e.Code != "" {
+		reason = loc.T(e.Code, e.Args...)
+	}
+
+	kind := i18n.Localize(e.Err, loc)
+	if e.Column < 0 {
+		return loc.T("parse.record", kind, reason)
+	}
+	return loc.T("parse.field", kind, e.Column, e.Value, reason)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
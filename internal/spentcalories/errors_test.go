@@ -0,0 +1,106 @@
+package spentcalories
+
+import (
+	"errors"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestParseTrainingErrors() {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    error
+		wantField  string
+		wantValue  string
+		wantColumn int
+	}{
+		{
+			name:       "неверное количество значений",
+			input:      "678,Ходьба",
+			wantErr:    ErrFieldCount,
+			wantField:  FieldRecord,
+			wantValue:  "678,Ходьба",
+			wantColumn: -1,
+		},
+		{
+			name:       "шаги не число",
+			input:      "abc,Ходьба,1h30m",
+			wantErr:    ErrSteps,
+			wantField:  FieldSteps,
+			wantValue:  "abc",
+			wantColumn: 0,
+		},
+		{
+			name:       "отрицательные шаги",
+			input:      "-100,Ходьба,1h30m",
+			wantErr:    ErrSteps,
+			wantField:  FieldSteps,
+			wantValue:  "-100",
+			wantColumn: 0,
+		},
+		{
+			name:       "пустая активность",
+			input:      "100, ,1h30m",
+			wantErr:    ErrActivity,
+			wantField:  FieldActivity,
+			wantValue:  " ",
+			wantColumn: 1,
+		},
+		{
+			name:       "неверная продолжительность",
+			input:      "100,Бег,30",
+			wantErr:    ErrDuration,
+			wantField:  FieldDuration,
+			wantValue:  "30",
+			wantColumn: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, _, _, err := parseTraining(tt.input)
+			assert.ErrorIs(suite.T(), err, tt.wantErr)
+
+			var perr *ParseError
+			if assert.ErrorAs(suite.T(), err, &perr) {
+				assert.Equal(suite.T(), tt.wantField, perr.Field)
+				assert.Equal(suite.T(), tt.wantValue, perr.Value)
+				assert.Equal(suite.T(), tt.wantColumn, perr.Column)
+				assert.NotEmpty(suite.T(), perr.Reason)
+			}
+		})
+	}
+}
+
+func (suite *SpentCaloriesTestSuite) TestUnknownActivityError() {
+	_, err := Training("6000,Плавание,1h00m", 75.0, 1.75)
+	assert.True(suite.T(), errors.Is(err, ErrUnknownActivity))
+
+	var perr *ParseError
+	assert.True(suite.T(), errors.As(err, &perr))
+	assert.Equal(suite.T(), FieldActivity, perr.Field)
+	assert.Equal(suite.T(), "Плавание", perr.Value)
+
+	_, err = METSpentCalories("Плавание", 1000, 75.0, 1.75, 1)
+	assert.ErrorIs(suite.T(), err, ErrUnknownActivity)
+}
+
+func (suite *SpentCaloriesTestSuite) TestParseErrorLocalize() {
+	_, _, _, err := parseTraining("-100,Ходьба,1h30m")
+
+	var perr *ParseError
+	if assert.ErrorAs(suite.T(), err, &perr) {
+		assert.Equal(suite.T(), `Ошибка: некорректное количество шагов в колонке 0 ("-100"): кол-во шагов должно быть > 0`, perr.Error())
+		assert.Equal(suite.T(), `Error: invalid step count in column 0 ("-100"): step count must be > 0`, perr.Localize(i18n.English))
+		assert.Equal(suite.T(), perr.Localize(i18n.English), i18n.Localize(err, i18n.English))
+	}
+
+	_, _, _, err = parseTraining("1,2")
+	assert.Equal(suite.T(), "Error: wrong number of values: expected 3 values or 4 with a start time, got 2", i18n.Localize(err, i18n.English))
+
+	_, err = Training("1000,Бег,1h", 0, 1.75)
+	assert.Equal(suite.T(), "weight must be positive", i18n.Localize(err, i18n.English))
+}
@@ -0,0 +1,169 @@
+package spentcalories
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+)
+
+// Коэффициенты формулы Keytel (2005) для расхода энергии в кДж/мин:
+// константа + пульс * hr + вес * w + возраст * a
+type keytelCoefficients struct {
+	constant, hr, weight, age float64
+}
+
+var (
+	keytelMale   = keytelCoefficients{constant: -55.0969, hr: 0.6309, weight: 0.1988, age: 0.2017}
+	keytelFemale = keytelCoefficients{constant: -20.4022, hr: 0.4472, weight: -0.1263, age: 0.074}
+)
+
+// Количество кДж в одной ккал
+const kJInKcal = 4.184
+
+// HeartRateSample - измерение пульса, действует до следующего измерения
+type HeartRateSample struct {
+	Time time.Time
+	// Пульс в ударах в минуту
+	BPM float64
+}
+
+// keytelPerMinute возвращает расход в ккал/мин при пульсе heartRate. При низком
+// пульсе формула дает отрицательные значения, такие минуты считаются нулевыми.
+// Если пол неизвестен, берется среднее формул для мужчин и женщин
+func keytelPerMinute(heartRate float64, age int, sex profile.Sex, weight float64) float64 {
+	calc := func(c keytelCoefficients) float64 {
+		return (c.constant + c.hr*heartRate + c.weight*weight + c.age*float64(age)) / kJInKcal
+	}
+
+	var perMinute float64
+	switch sex {
+	case profile.Male:
+		perMinute = calc(keytelMale)
+	case profile.Female:
+		perMinute = calc(keytelFemale)
+	default:
+		perMinute = (calc(keytelMale) + calc(keytelFemale)) / 2
+	}
+	return math.Max(perMinute, 0)
+}
+
+// KeytelSpentCalories рассчитывает калории по среднему пульсу, возрасту, полу и весу
+func KeytelSpentCalories(heartRate float64, age int, sex profile.Sex, weight float64, duration time.Duration) (float64, error) {
+	// Проверяем корректность входных параметров
+	if heartRate <= 0 {
+		return 0, errHeartRate
+	}
+	if age <= 0 {
+		return 0, errAge
+	}
+	if weight <= 0 {
+		return 0, errWeight
+	}
+	if duration <= 0 {
+		return 0, errDuration
+	}
+
+	return keytelPerMinute(heartRate, age, sex, weight) * duration.Minutes(), nil
+}
+
+// heartRateCalories считает калории тренировки по пульсу. Если есть измерения,
+// каждое действует до следующего, а последнее - до конца тренировки, кроме пауз
+func heartRateCalories(w Workout, weight float64, o options) (float64, error) {
+	if len(w.HeartRateSamples) == 0 {
+		return KeytelSpentCalories(w.HeartRate, o.age, o.sex, weight, w.Duration)
+	}
+	if o.age <= 0 {
+		return 0, errAge
+	}
+	if weight <= 0 {
+		return 0, errWeight
+	}
+
+	var calories float64
+	eachSample(w, func(bpm float64, d time.Duration) {
+		calories += keytelPerMinute(bpm, o.age, o.sex, weight) * d.Minutes()
+	})
+	return calories, nil
+}
+
+// Разрыв между измерениями пульса считается паузой, если он длиннее pauseFactor
+// типичных интервалов между измерениями и длиннее minPauseGap
+const (
+	pauseFactor = 4
+	minPauseGap = time.Minute
+)
+
+// eachSample вызывает fn для каждого измерения пульса с длительностью его действия:
+// до следующего измерения, а для последнего - до конца тренировки. Время берется
+// по отметкам измерений, а не по продолжительности тренировки, потому что она
+// считается по таймеру без пауз. Если до следующего измерения или до конца
+// тренировки прошло больше отсечки, это пауза: измерение действует типичный интервал
+func eachSample(w Workout, fn func(bpm float64, d time.Duration)) {
+	samples := w.HeartRateSamples
+	typical, cutoff := sampleInterval(samples)
+	end := w.Start.Add(w.Duration)
+	for i, s := range samples {
+		next := end
+		if i+1 < len(samples) {
+			next = samples[i+1].Time
+		}
+		d := next.Sub(s.Time)
+		if d > cutoff || (d < 0 && i+1 == len(samples)) {
+			// Последнее измерение позже конца тренировки по таймеру - значит,
+			// была пауза, и оно тоже действует типичный интервал
+			d = typical
+		}
+		if d > 0 && s.BPM > 0 {
+			fn(s.BPM, d)
+		}
+	}
+}
+
+// sampleInterval возвращает типичный интервал между измерениями - медиану
+// положительных интервалов - и отсечку, после которой разрыв считается паузой.
+// Если интервалов нет, пауз не бывает
+func sampleInterval(samples []HeartRateSample) (typical, cutoff time.Duration) {
+	intervals := make([]time.Duration, 0, len(samples))
+	for i := 1; i < len(samples); i++ {
+		if d := samples[i].Time.Sub(samples[i-1].Time); d > 0 {
+			intervals = append(intervals, d)
+		}
+	}
+	if len(intervals) == 0 {
+		return 0, math.MaxInt64
+	}
+	slices.Sort(intervals)
+	typical = intervals[len(intervals)/2]
+	return typical, max(minPauseGap, pauseFactor*typical)
+}
+
+// zoneTimes возвращает время в пульсовых зонах или nil, если зоны не заданы
+// или у тренировки нет измерений пульса
+func zoneTimes(w Workout, o options) []hrzone.ZoneTime {
+	if o.zones == nil || len(w.HeartRateSamples) == 0 {
+		return nil
+	}
+	c := hrzone.NewCounter(*o.zones)
+	eachSample(w, c.Add)
+	return c.Result()
+}
+
+// averageHeartRate возвращает средний пульс, взвешенный по времени измерений
+func averageHeartRate(w Workout) float64 {
+	if w.HeartRate > 0 || len(w.HeartRateSamples) == 0 {
+		return w.HeartRate
+	}
+
+	var sum, minutes float64
+	eachSample(w, func(bpm float64, d time.Duration) {
+		sum += bpm * d.Minutes()
+		minutes += d.Minutes()
+	})
+	if minutes == 0 {
+		return 0
+	}
+	return sum / minutes
+}
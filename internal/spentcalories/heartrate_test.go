@@ -0,0 +1,178 @@
+package spentcalories
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestKeytelSpentCalories() {
+	male := (-55.0969 + 0.6309*150 + 0.1988*75 + 0.2017*30) / 4.184 * 60
+	female := (-20.4022 + 0.4472*150 - 0.1263*75 + 0.074*30) / 4.184 * 60
+
+	tests := []struct {
+		name      string
+		heartRate float64
+		age       int
+		sex       profile.Sex
+		duration  time.Duration
+		want      float64
+		wantErr   error
+	}{
+		{name: "мужчина", heartRate: 150, age: 30, sex: profile.Male, duration: time.Hour, want: male},
+		{name: "женщина", heartRate: 150, age: 30, sex: profile.Female, duration: time.Hour, want: female},
+		{name: "пол неизвестен", heartRate: 150, age: 30, duration: time.Hour, want: (male + female) / 2},
+		// При пульсе покоя формула отрицательна, калории не могут быть меньше нуля
+		{name: "низкий пульс", heartRate: 40, age: 30, sex: profile.Male, duration: time.Hour, want: 0},
+		{name: "нет пульса", age: 30, sex: profile.Male, duration: time.Hour, wantErr: errHeartRate},
+		{name: "нет возраста", heartRate: 150, sex: profile.Male, duration: time.Hour, wantErr: errAge},
+		{name: "нет продолжительности", heartRate: 150, age: 30, sex: profile.Male, wantErr: errDuration},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := KeytelSpentCalories(tt.heartRate, tt.age, tt.sex, 75.0, tt.duration)
+			if tt.wantErr != nil {
+				assert.ErrorIs(suite.T(), err, tt.wantErr)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+}
+
+func (suite *SpentCaloriesTestSuite) TestHeartRateModel() {
+	start := time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC)
+	w := Workout{
+		Start:    start,
+		Activity: "Бег",
+		Steps:    8000,
+		Duration: time.Hour,
+		HeartRateSamples: []HeartRateSample{
+			{Time: start, BPM: 120},
+			{Time: start.Add(30 * time.Minute), BPM: 160},
+		},
+	}
+	opts := []Option{WithModel(HeartRateModel), WithAge(30), WithSex(profile.Male)}
+
+	// Каждое измерение действует по 30 минут
+	low, _ := KeytelSpentCalories(120, 30, profile.Male, 75.0, 30*time.Minute)
+	high, _ := KeytelSpentCalories(160, 30, profile.Male, 75.0, 30*time.Minute)
+	got, err := Compute(w, 75.0, 1.75, opts...)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), HeartRateModel, got.Model)
+	assert.InDelta(suite.T(), low+high, got.Calories, 1e-9)
+	assert.InDelta(suite.T(), 140.0, got.HeartRate, 1e-9)
+
+	// Средний пульс без измерений
+	avg, err := Compute(Workout{Activity: "Бег", Steps: 8000, Duration: time.Hour, HeartRate: 140}, 75.0, 1.75, opts...)
+	assert.NoError(suite.T(), err)
+	want, _ := KeytelSpentCalories(140, 30, profile.Male, 75.0, time.Hour)
+	assert.InDelta(suite.T(), want, avg.Calories, 1e-9)
+
+	// Без пульса используется модель по скорости, и это видно в результате
+	speed, err := Training("8000,Бег,1h00m", 75.0, 1.75, opts...)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SpeedModel, speed.Model)
+	plain, _ := Training("8000,Бег,1h00m", 75.0, 1.75)
+	assert.Equal(suite.T(), plain.Calories, speed.Calories)
+
+	// Без возраста посчитать по пульсу нельзя
+	_, err = Compute(w, 75.0, 1.75, WithModel(HeartRateModel))
+	assert.ErrorIs(suite.T(), err, errAge)
+
+	data, err := json.Marshal(got)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(data), `"model":"hr"`)
+}
+
+func (suite *SpentCaloriesTestSuite) TestHeartRatePause() {
+	start := time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC)
+	// Измерения раз в 10 секунд: 10 минут с пульсом 120, пауза 20 минут, 10 минут с пульсом 160.
+	// Продолжительность по таймеру - 20 минут без паузы
+	var samples []HeartRateSample
+	for i := 0; i < 60; i++ {
+		samples = append(samples, HeartRateSample{Time: start.Add(time.Duration(i) * 10 * time.Second), BPM: 120})
+	}
+	resume := start.Add(30 * time.Minute)
+	for i := 0; i < 60; i++ {
+		samples = append(samples, HeartRateSample{Time: resume.Add(time.Duration(i) * 10 * time.Second), BPM: 160})
+	}
+	w := Workout{Start: start, Activity: "Бег", Steps: 4000, Duration: 20 * time.Minute, HeartRateSamples: samples}
+
+	// Пауза не засчитывается ни одному измерению: каждое действует по 10 минут
+	low, _ := KeytelSpentCalories(120, 30, profile.Male, 75.0, 10*time.Minute)
+	high, _ := KeytelSpentCalories(160, 30, profile.Male, 75.0, 10*time.Minute)
+	got, err := Compute(w, 75.0, 1.75, WithModel(HeartRateModel), WithAge(30), WithSex(profile.Male))
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), low+high, got.Calories, 1e-9)
+	assert.InDelta(suite.T(), 140.0, got.HeartRate, 1e-9)
+
+	zones, err := hrzone.New(hrzone.Config{}, 200, 0)
+	assert.NoError(suite.T(), err)
+	got, err = Compute(w, 75.0, 1.75, WithHeartRateZones(zones))
+	assert.NoError(suite.T(), err)
+	var total time.Duration
+	for _, z := range got.Zones {
+		total += z.Duration
+	}
+	assert.Equal(suite.T(), 20*time.Minute, total)
+}
+
+func (suite *SpentCaloriesTestSuite) TestParseModel() {
+	for _, m := range []CalorieModel{SpeedModel, METModel, HeartRateModel} {
+		got, err := ParseModel(m.String())
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), m, got)
+	}
+	_, err := ParseModel("magic")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpentCaloriesTestSuite) TestHeartRateZones() {
+	start := time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC)
+	zones, err := hrzone.New(hrzone.Config{}, 200, 0)
+	assert.NoError(suite.T(), err)
+
+	w := Workout{
+		Start:    start,
+		Activity: "Бег",
+		Steps:    8000,
+		Duration: time.Hour,
+		HeartRateSamples: []HeartRateSample{
+			{Time: start, BPM: 90},
+			{Time: start.Add(10 * time.Minute), BPM: 130},
+			{Time: start.Add(40 * time.Minute), BPM: 185},
+		},
+		Segments: []Workout{{
+			Start:            start,
+			Activity:         "Бег",
+			Steps:            1000,
+			Duration:         10 * time.Minute,
+			HeartRateSamples: []HeartRateSample{{Time: start, BPM: 90}},
+		}},
+	}
+
+	got, err := Compute(w, 75.0, 1.75, WithHeartRateZones(zones))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), got.Zones, hrzone.Count+1)
+	want := []time.Duration{10 * time.Minute, 0, 30 * time.Minute, 0, 0, 20 * time.Minute}
+	for i, z := range got.Zones {
+		assert.Equal(suite.T(), i, z.Zone)
+		assert.Equal(suite.T(), want[i], z.Duration, "зона %d", i)
+	}
+	assert.Equal(suite.T(), 10*time.Minute, got.Segments[0].Zones[0].Duration)
+
+	// Без измерений пульса и без зон разбивки нет
+	plain, err := Compute(Workout{Activity: "Бег", Steps: 8000, Duration: time.Hour, HeartRate: 140}, 75.0, 1.75,
+		WithHeartRateZones(zones))
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), plain.Zones)
+	none, err := Compute(w, 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), none.Zones)
+}
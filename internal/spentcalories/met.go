@@ -0,0 +1,136 @@
+package spentcalories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+)
+
+// CalorieModel определяет способ расчета потраченных калорий
+type CalorieModel int
+
+const (
+	// SpeedModel - исходная формула вес * скорость * минуты
+	SpeedModel CalorieModel = iota
+	// METModel - расчет по метаболическому эквиваленту (MET) активности
+	METModel
+	// HeartRateModel - расчет по пульсу, возрасту, полу и весу (формула Keytel).
+	// Если у тренировки нет данных о пульсе, используется SpeedModel
+	HeartRateModel
+)
+
+func (m CalorieModel) String() string {
+	switch m {
+	case SpeedModel:
+		return "speed"
+	case METModel:
+		return "met"
+	case HeartRateModel:
+		return "hr"
+	default:
+		return fmt.Sprintf("CalorieModel(%d)", int(m))
+	}
+}
+
+// MarshalText кодирует модель ее названием, например в JSON-результате тренировки
+func (m CalorieModel) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText разбирает модель по названию, например из сохраненного результата
+func (m *CalorieModel) UnmarshalText(text []byte) error {
+	parsed, err := ParseModel(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// ParseModel возвращает модель по названию: speed, met или hr
+func ParseModel(name string) (CalorieModel, error) {
+	for _, m := range []CalorieModel{SpeedModel, METModel, HeartRateModel} {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("неизвестная модель расчета калорий %q", name)
+}
+
+// METBand задает значение MET для скоростей ниже UpTo км/ч. Нулевой UpTo означает отсутствие верхней границы
+type METBand struct {
+	UpTo float64
+	MET  float64
+}
+
+// Значения MET по Compendium of Physical Activities, скорость в км/ч
+var (
+	walkingMET = []METBand{
+		{UpTo: 3.2, MET: 2.0},
+		{UpTo: 4.0, MET: 2.8},
+		{UpTo: 4.8, MET: 3.0},
+		{UpTo: 5.6, MET: 3.5},
+		{UpTo: 6.4, MET: 4.3},
+		{UpTo: 7.2, MET: 5.0},
+		{UpTo: 8.0, MET: 7.0},
+		{MET: 8.3},
+	}
+	runningMET = []METBand{
+		{UpTo: 6.4, MET: 6.0},
+		{UpTo: 8.0, MET: 8.3},
+		{UpTo: 8.4, MET: 9.0},
+		{UpTo: 9.7, MET: 9.8},
+		{UpTo: 10.8, MET: 10.5},
+		{UpTo: 11.3, MET: 11.0},
+		{UpTo: 12.1, MET: 11.5},
+		{UpTo: 12.9, MET: 11.8},
+		{UpTo: 13.8, MET: 12.3},
+		{UpTo: 14.5, MET: 12.8},
+		{UpTo: 16.1, MET: 14.5},
+		{UpTo: 17.7, MET: 16.0},
+		{UpTo: 19.3, MET: 19.0},
+		{UpTo: 20.9, MET: 19.8},
+		{MET: 23.0},
+	}
+)
+
+// metForSpeed выбирает MET из таблицы по средней скорости
+func metForSpeed(bands []METBand, speed float64) float64 {
+	for _, b := range bands {
+		if b.UpTo == 0 || speed < b.UpTo {
+			return b.MET
+		}
+	}
+	// Скорость выше последней границы - берем самое большое значение
+	return bands[len(bands)-1].MET
+}
+
+func METSpentCalories(activity string, steps int, weight, height float64, duration time.Duration) (float64, error) {
+	// Проверяем корректность входных параметров
+	if weight <= 0 {
+		return 0, errWeight
+	}
+	if height <= 0 {
+		return 0, errHeight
+	}
+	if steps <= 0 {
+		return 0, errSteps
+	}
+	if duration <= 0 {
+		return 0, errDuration
+	}
+
+	a, ok := LookupActivity(activity)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownActivity, activity)
+	}
+	if len(a.MET) == 0 {
+		return 0, i18n.NewError("err.no_met", a.Name)
+	}
+
+	// Рассчитываем среднюю скорость и выбираем по ней MET
+	met := metForSpeed(a.MET, meanSpeed(steps, height, duration))
+	// Калории по MET: MET * вес в кг * часы
+	return met * weight * duration.Hours(), nil
+}
@@ -0,0 +1,112 @@
+package spentcalories
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestMETSpentCalories() {
+	tests := []struct {
+		name     string
+		activity string
+		steps    int
+		duration time.Duration
+		wantCal  float64
+		wantErr  bool
+	}{
+		{
+			// 4.725 км/ч попадает в полосу до 4.8 км/ч, MET 3.0
+			name:     "ходьба - средний темп",
+			activity: "Ходьба",
+			steps:    6000,
+			duration: time.Hour,
+			wantCal:  225,
+		},
+		{
+			// 0.39 км/ч - самая медленная полоса, MET 2.0
+			name:     "ходьба - очень медленно",
+			activity: "Ходьба",
+			steps:    1000,
+			duration: 2 * time.Hour,
+			wantCal:  300,
+		},
+		{
+			// 15.75 км/ч - полоса до 16.1 км/ч, MET 14.5
+			name:     "бег - быстрый темп",
+			activity: "Бег",
+			steps:    20000,
+			duration: time.Hour,
+			wantCal:  1087.5,
+		},
+		{
+			// 31.5 км/ч - выше всех границ, MET 23.0
+			name:     "бег - спринт",
+			activity: "Бег",
+			steps:    20000,
+			duration: 30 * time.Minute,
+			wantCal:  862.5,
+		},
+		{
+			name:     "неизвестная активность",
+			activity: "Плавание",
+			steps:    1000,
+			duration: time.Hour,
+			wantErr:  true,
+		},
+		{
+			name:     "нулевая продолжительность",
+			activity: "Бег",
+			steps:    1000,
+			duration: 0,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := METSpentCalories(tt.activity, tt.steps, 75.0, 1.75, tt.duration)
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				assert.Equal(suite.T(), 0.0, got)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.wantCal, got, 1e-9)
+		})
+	}
+}
+
+func (suite *SpentCaloriesTestSuite) TestTrainingWithModel() {
+	speed, err := Training("6000,Ходьба,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 177.19, speed.Calories, 0.01)
+
+	met, err := Training("6000,Ходьба,1h00m", 75.0, 1.75, WithModel(METModel))
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 225, met.Calories, 1e-9)
+
+	// Активность без таблицы MET не может быть рассчитана по METModel
+	assert.NoError(suite.T(), RegisterActivity(Activity{Name: "Йога", Calories: WalkingSpentCalories}))
+	defer UnregisterActivity("Йога")
+	_, err = Training("1000,Йога,1h00m", 75.0, 1.75, WithModel(METModel))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpentCaloriesTestSuite) TestTrainingResultJSONRoundTrip() {
+	for _, m := range []CalorieModel{SpeedModel, METModel, HeartRateModel} {
+		want, err := Training("2024-03-05T07:00:00Z,8000,Бег,1h00m", 75.0, 1.75, WithModel(m))
+		assert.NoError(suite.T(), err)
+
+		data, err := json.Marshal(want)
+		assert.NoError(suite.T(), err)
+		var got TrainingResult
+		assert.NoError(suite.T(), json.Unmarshal(data, &got))
+		assert.Equal(suite.T(), want.Model, got.Model)
+		assert.InDelta(suite.T(), want.Calories, got.Calories, 1e-9)
+	}
+
+	var m CalorieModel
+	assert.Error(suite.T(), json.Unmarshal([]byte(`"watts"`), &m))
+}
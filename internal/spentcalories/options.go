@@ -0,0 +1,93 @@
+package spentcalories
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// Option настраивает расчет тренировки в Training
+type Option func(*options)
+
+type options struct {
+	model  CalorieModel
+	date   time.Time
+	stride stride.Model
+	age    int
+	sex    profile.Sex
+	zones  *hrzone.Zones
+	weight WeightSource
+	// Профиль из WithProfile: возраст и пульсовые зоны по нему считаются
+	// на дату тренировки, если не заданы явно
+	profile *profile.Profile
+}
+
+func newOptions(opts []Option) options {
+	o := options{model: SpeedModel}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithModel выбирает модель расчета калорий, по умолчанию используется SpeedModel
+func WithModel(m CalorieModel) Option {
+	return func(o *options) {
+		o.model = m
+	}
+}
+
+// WithDate задает день, к которому относится время суток в записи, например "12:40,678,Бег,5m"
+func WithDate(date time.Time) Option {
+	return func(o *options) {
+		o.date = date
+	}
+}
+
+// WithStride задает модель длины шага для дистанции, скорости и калорий.
+// Без нее используется stride.Default
+func WithStride(m stride.Model) Option {
+	return func(o *options) {
+		o.stride = m
+	}
+}
+
+// WithAge задает возраст в полных годах для HeartRateModel
+func WithAge(years int) Option {
+	return func(o *options) {
+		o.age = years
+	}
+}
+
+// WithSex задает пол для HeartRateModel. Если пол не задан, берется среднее
+// формул для мужчин и женщин
+func WithSex(sex profile.Sex) Option {
+	return func(o *options) {
+		o.sex = sex
+	}
+}
+
+// WithHeartRateZones включает расчет времени в пульсовых зонах z
+// для тренировок с измерениями пульса
+func WithHeartRateZones(z hrzone.Zones) Option {
+	return func(o *options) {
+		o.zones = &z
+	}
+}
+
+// WeightSource возвращает вес пользователя в килограммах на момент t,
+// например по истории взвешиваний. ok = false, если вес на этот момент неизвестен
+type WeightSource interface {
+	WeightAt(t time.Time) (kg float64, ok bool)
+}
+
+// WithWeightHistory задает источник веса для тренировок с временем начала:
+// калории считаются по весу на дату тренировки, а переданный в Training
+// или Compute вес используется, только если источник его не знает
+func WithWeightHistory(h WeightSource) Option {
+	return func(o *options) {
+		o.weight = h
+	}
+}
@@ -0,0 +1,66 @@
+package spentcalories
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// WithProfile задает параметры расчета из профиля: модель шага, пол, возраст
+// на дату тренировки и пульсовые зоны. Опции после нее переопределяют значения профиля
+func WithProfile(p profile.Profile) Option {
+	return func(o *options) {
+		o.stride = stride.FromProfile(p)
+		o.sex = p.Sex
+		o.age = 0
+		o.zones = nil
+		o.profile = &p
+	}
+}
+
+// at дополняет опции возрастом и пульсовыми зонами из профиля на момент start.
+// Для тренировки без времени начала возраст считается на сегодня
+func (o options) at(start time.Time) options {
+	if o.profile == nil {
+		return o
+	}
+	if start.IsZero() {
+		start = time.Now()
+	}
+	if o.age == 0 {
+		o.age = o.profile.Age(start)
+	}
+	if o.zones == nil {
+		if zones, ok, err := o.profile.HeartRateZones(o.age); err == nil && ok {
+			o.zones = &zones
+		}
+	}
+	o.profile = nil
+	return o
+}
+
+// TrainingFor рассчитывает тренировку для пользователя с профилем p.
+// Профиль проверяется целиком, поэтому недопустимый вес или рост дают ошибку
+func TrainingFor(data string, p profile.Profile, opts ...Option) (TrainingResult, error) {
+	if err := p.Validate(); err != nil {
+		return TrainingResult{}, err
+	}
+	return Training(data, p.Weight, p.Height, append([]Option{WithProfile(p)}, opts...)...)
+}
+
+// ComputeFor рассчитывает тренировку w для пользователя с профилем p, как TrainingFor
+func ComputeFor(w Workout, p profile.Profile, opts ...Option) (TrainingResult, error) {
+	if err := p.Validate(); err != nil {
+		return TrainingResult{}, err
+	}
+	return Compute(w, p.Weight, p.Height, append([]Option{WithProfile(p)}, opts...)...)
+}
+
+// BMRFor рассчитывает основной обмен по профилю p с возрастом на момент at
+func BMRFor(formula BMRFormula, p profile.Profile, at time.Time) (float64, error) {
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	return BMR(formula, p.Weight, p.Height, p.Age(at), p.Sex)
+}
@@ -0,0 +1,81 @@
+package spentcalories
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestTrainingFor() {
+	p := profile.Profile{Weight: 75.0, Height: 1.75, Sex: profile.Male, Stride: 0.8}
+
+	got, err := TrainingFor("8000,Бег,1h00m", p)
+	assert.NoError(suite.T(), err)
+	want, _ := Training("8000,Бег,1h00m", 75.0, 1.75, WithStride(stride.Calibrated(0.8)))
+	assert.Equal(suite.T(), want, got)
+
+	// Опции после профиля переопределяют его значения
+	plain, err := TrainingFor("8000,Бег,1h00m", p, WithStride(stride.HeightBased(stride.HeightCoefficient)))
+	assert.NoError(suite.T(), err)
+	base, _ := Training("8000,Бег,1h00m", 75.0, 1.75)
+	assert.Equal(suite.T(), base, plain)
+
+	for _, bad := range []profile.Profile{{Weight: 8460, Height: 1.75}, {Weight: 75, Height: 175}, {}} {
+		_, err := TrainingFor("8000,Бег,1h00m", bad)
+		var verr *profile.ValidationError
+		assert.ErrorAs(suite.T(), err, &verr)
+	}
+}
+
+func (suite *SpentCaloriesTestSuite) TestComputeFor() {
+	start := time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC)
+	p := profile.Profile{
+		Weight:       75.0,
+		Height:       1.75,
+		Sex:          profile.Male,
+		BirthDate:    profile.NewDate(1990, time.May, 17),
+		MaxHeartRate: 200,
+	}
+	w := Workout{
+		Start:            start,
+		Activity:         "Бег",
+		Steps:            8000,
+		Duration:         time.Hour,
+		HeartRateSamples: []HeartRateSample{{Time: start, BPM: 150}},
+	}
+
+	// Возраст на дату тренировки, пол и пульсовые зоны берутся из профиля
+	got, err := ComputeFor(w, p, WithModel(HeartRateModel))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), HeartRateModel, got.Model)
+	want, _ := KeytelSpentCalories(150, 33, profile.Male, 75.0, time.Hour)
+	assert.InDelta(suite.T(), want, got.Calories, 1e-9)
+
+	// Явный возраст переопределяет возраст по профилю
+	older, err := ComputeFor(w, p, WithModel(HeartRateModel), WithAge(50))
+	assert.NoError(suite.T(), err)
+	want, _ = KeytelSpentCalories(150, 50, profile.Male, 75.0, time.Hour)
+	assert.InDelta(suite.T(), want, older.Calories, 1e-9)
+	if assert.Len(suite.T(), got.Zones, hrzone.Count+1) {
+		assert.Equal(suite.T(), time.Hour, got.Zones[3].Duration)
+	}
+
+	_, err = ComputeFor(w, profile.Profile{Weight: 8460, Height: 1.75})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpentCaloriesTestSuite) TestBMRFor() {
+	p := profile.Profile{Weight: 75.0, Height: 1.75, Sex: profile.Male, BirthDate: profile.NewDate(1994, time.March, 10)}
+
+	got, err := BMRFor(MifflinStJeor, p, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC))
+	assert.NoError(suite.T(), err)
+	// Возраст 29 лет: 10 * 75 + 6.25 * 175 - 5 * 29 + 5
+	assert.InDelta(suite.T(), 1703.75, got, 1e-9)
+
+	// Без даты рождения возраст неизвестен
+	_, err = BMRFor(MifflinStJeor, profile.Profile{Weight: 75.0, Height: 1.75}, time.Now())
+	assert.ErrorIs(suite.T(), err, errAge)
+}
@@ -1,69 +1,148 @@
 package spentcalories
 
 import (
-	"errors"
-	"fmt"
 	"log"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/Yandex-Practicum/tracker/internal/units"
 )
 
 // Константы для расчетов калорий и расстояний
 const (
 	lenStep                    = 0.65 // средняя длина шага в метрах
 	mInKm                      = 1000 // количество метров в километре
+	cmInM                      = 100  // количество сантиметров в метре
 	minInH                     = 60   // количество минут в часе
-	stepLengthCoefficient      = 0.45 // коэффициент для расчета длины шага на основе роста
 	walkingCaloriesCoefficient = 0.5  // коэффициент для расчета калорий при ходьбе
 )
 
 func parseTraining(data string) (int, string, time.Duration, error) {
+	rec, err := parseTrainingRecord(data, time.Time{})
+	if err != nil {
+		return 0, "", 0, err
+	}
+	return rec.steps, rec.activity, rec.duration, nil
+}
+
+// trainingRecord - разобранная запись "[время,]шаги,активность,продолжительность"
+type trainingRecord struct {
+	// Сдвиг колонок: 1, если запись начинается со времени
+	offset   int
+	start    time.Time
+	steps    int
+	activity string
+	duration time.Duration
+}
+
+func parseTrainingRecord(data string, date time.Time) (trainingRecord, error) {
 	// Разделяем строку по запятым
 	delstr := strings.Split(data, ",")
-	if len(delstr) != 3 {
-		return 0, "", 0, fmt.Errorf("Ошибка: неверный формат, ожидается 3 значения, получено %d", len(delstr))
+	if len(delstr) != 3 && len(delstr) != 4 {
+		return trainingRecord{}, NewParseError(FieldRecord, data, -1, ErrFieldCount, "reason.fields_training", len(delstr))
 	}
 
-	// Обрезаем пробелы со всех параметров
-	trSteps := strings.TrimSpace(delstr[0])
-	activ := strings.TrimSpace(delstr[1])
-	trDuration := strings.TrimSpace(delstr[2])
+	var rec trainingRecord
 
-	// Убираем знак + если есть перед числом шагов
-	if strings.HasPrefix(trSteps, "+") {
-		trSteps = trSteps[1:]
+	// Если значений четыре, первое из них - время начала тренировки
+	if len(delstr) == 4 {
+		start, err := ParseTime(strings.TrimSpace(delstr[0]), 0, date)
+		if err != nil {
+			return trainingRecord{}, err
+		}
+		rec.start = start
+		rec.offset = 1
 	}
+	col := rec.offset
+
+	// Обрезаем пробелы со всех параметров
+	trSteps := strings.TrimSpace(delstr[col])
+	activ := strings.TrimSpace(delstr[col+1])
+	trDuration := strings.TrimSpace(delstr[col+2])
 
 	// Парсим количество шагов
-	steps, err := strconv.Atoi(trSteps)
+	steps, err := ParseSteps(trSteps, col)
 	if err != nil {
-		return 0, "", 0, fmt.Errorf("Ошибка при парсинге шагов: %v", err)
-	}
-	if steps <= 0 {
-		return 0, "", 0, fmt.Errorf("Ошибка: кол-во шагов должно быть > 0, получено %d", steps)
+		return trainingRecord{}, err
 	}
 
 	// Проверяем что указан тип активности
 	if activ == "" {
-		return 0, "", 0, fmt.Errorf("Ошибка: неверный вид активности")
+		return trainingRecord{}, NewParseError(FieldActivity, delstr[col+1], col+1, ErrActivity, "reason.activity_empty")
 	}
 
 	// Парсим продолжительность тренировки
-	duration, err := time.ParseDuration(trDuration)
+	duration, err := ParseDuration(trDuration, col+2)
 	if err != nil {
-		return 0, "", 0, fmt.Errorf("Ошибка при парсинге продолжительности: %v", err)
+		return trainingRecord{}, err
 	}
-	if duration <= 0 {
-		return 0, "", 0, fmt.Errorf("Ошибка: продолжительность должна быть положительная, получено %v", duration)
+
+	rec.steps = steps
+	rec.activity = activ
+	rec.duration = duration
+	return rec, nil
+}
+
+// Форматы времени суток, которые дополняются датой из контекста
+var clockLayouts = []string{"15:04:05", "15:04"}
+
+// ParseTime разбирает время начала из колонки column: RFC 3339 или время суток,
+// которое относится к дню date в его часовом поясе
+func ParseTime(value string, column int, date time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
 
-	return steps, activ, duration, nil
+	for _, layout := range clockLayouts {
+		clock, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if date.IsZero() {
+			return time.Time{}, NewParseError(FieldTime, value, column, ErrTime, "reason.time_no_date")
+		}
+		y, m, d := date.Date()
+		return time.Date(y, m, d, clock.Hour(), clock.Minute(), clock.Second(), 0, date.Location()), nil
+	}
+
+	return time.Time{}, NewParseError(FieldTime, value, column, ErrTime, "reason.time_format")
+}
+
+// ParseSteps разбирает количество шагов из колонки column, допускается знак +
+func ParseSteps(value string, column int) (int, error) {
+	// Убираем знак + если есть перед числом шагов
+	trimmed := strings.TrimPrefix(value, "+")
+
+	steps, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, NewParseError(FieldSteps, value, column, ErrSteps, "reason.steps_not_integer")
+	}
+	if steps <= 0 {
+		return 0, NewParseError(FieldSteps, value, column, ErrSteps, "reason.steps_positive")
+	}
+	return steps, nil
+}
+
+// ParseDuration разбирает продолжительность из колонки column в формате time.ParseDuration
+func ParseDuration(value string, column int) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, NewParseError(FieldDuration, value, column, ErrDuration, "reason.duration_format")
+	}
+	if duration <= 0 {
+		return 0, NewParseError(FieldDuration, value, column, ErrDuration, "reason.duration_positive")
+	}
+	return duration, nil
 }
 
 func distance(steps int, height float64) float64 {
 	// Рассчитываем длину шага исходя из роста
-	stridelength := height * stepLengthCoefficient
+	stridelength := stride.Default().Length(height)
 	// Рассчитываем общую дистанцию в километрах
 	distance := float64(steps) * stridelength / mInKm
 	return distance
@@ -89,65 +168,110 @@ func meanSpeed(steps int, height float64, duration time.Duration) float64 {
 	return distance / durationHours
 }
 
-func TrainingInfo(data string, weight, height float64) (string, error) {
+// TrainingResult содержит рассчитанные показатели одной тренировки
+type TrainingResult struct {
+	// Время начала, нулевое если в записи его не было
+	Start    time.Time     `json:"start,omitzero"`
+	Activity string        `json:"activity"`
+	Steps    int           `json:"steps"`
+	Duration time.Duration `json:"duration"`
+	Distance float64       `json:"distance_km"`
+	Speed    float64       `json:"speed_kmh"`
+	Calories float64       `json:"calories"`
+	// Модель, по которой рассчитаны калории
+	Model CalorieModel `json:"model"`
+	// Набор высоты в метрах, известен только для импортированных треков
+	Elevation float64 `json:"elevation_gain_m,omitempty"`
+	// Средний пульс, известен только для импортированных тренировок
+	HeartRate float64 `json:"heart_rate_bpm,omitempty"`
+	// Время в пульсовых зонах, если они заданы и есть измерения пульса
+	Zones []hrzone.ZoneTime `json:"zones,omitempty"`
+	// Вес из истории взвешиваний, по которому посчитаны калории, 0 если использован переданный вес
+	Weight float64 `json:"weight_kg,omitempty"`
+	// Результаты по отрезкам тренировки, например по кругам из TCX
+	Segments []TrainingResult `json:"segments,omitempty"`
+}
+
+// String форматирует результат в виде текста, который возвращает TrainingInfo
+func (r TrainingResult) String() string {
+	return r.Format(i18n.Default, units.Metric)
+}
+
+// Format форматирует результат на языке loc, название активности тоже переводится,
+// дистанция и скорость выводятся в единицах sys
+func (r TrainingResult) Format(loc i18n.Locale, sys units.System) string {
+	title := r.Activity
+	if a, ok := LookupActivity(r.Activity); ok {
+		title = a.Title(loc)
+	}
+	return loc.T("training.result", title, r.Duration.Hours(),
+		sys.Distance(r.Distance), loc.T(sys.DistanceKey()),
+		sys.Speed(r.Speed), loc.T(sys.SpeedKey()),
+		r.Calories)
+}
+
+func Training(data string, weight, height float64, opts ...Option) (TrainingResult, error) {
+	o := newOptions(opts)
+
 	// Проверяем корректность веса и роста
 	if weight <= 0 {
-		return "", fmt.Errorf("вес должен быть положителен")
+		return TrainingResult{}, errWeight
 	}
 	if height <= 0 {
-		return "", fmt.Errorf("рост должен быть положителен")
+		return TrainingResult{}, errHeight
 	}
 
 	// Парсим данные тренировки
-	steps, activ, duration, err := parseTraining(data)
+	rec, err := parseTrainingRecord(data, o.date)
 	if err != nil {
-		log.Println(err)
-		return "", err
+		return TrainingResult{}, err
+	}
+	// Ищем тип активности в реестре
+	activity, ok := LookupActivity(rec.activity)
+	if !ok {
+		// Если тип активности неизвестен - возвращаем ошибку
+		return TrainingResult{}, NewParseError(FieldActivity, rec.activity, rec.offset+1, ErrUnknownActivity, "reason.activity_not_registered")
 	}
 
-	// Рассчитываем дистанцию и среднюю скорость
-	dist := distance(steps, height)
-	speed := meanSpeed(steps, height, duration)
-
-	var calorie float64
+	return compute(Workout{
+		Start:    rec.start,
+		Activity: activity.Name,
+		Steps:    rec.steps,
+		Duration: rec.duration,
+	}, activity, weight, height, o)
+}
 
-	// В зависимости от типа активности рассчитываем калории
-	switch activ {
-	case "Бег":
-		calorie, err = RunningSpentCalories(steps, weight, height, duration)
-	case "Ходьба":
-		calorie, err = WalkingSpentCalories(steps, weight, height, duration)
-	default:
-		// Если тип активности неизвестен - возвращаем ошибку
-		log.Printf("Неизвестный тип тренировки: %s", activ)
-		return "", fmt.Errorf("неизвестный тип тренировки: %s", activ)
+func TrainingInfo(data string, weight, height float64) (string, error) {
+	// Вес и рост проверяются по тем же границам, что и в профиле
+	p := profile.Profile{Weight: weight, Height: height}
+	if err := p.Validate(); err != nil {
+		log.Println(err)
+		return "", err
 	}
 
+	result, err := Training(data, weight, height)
 	if err != nil {
 		log.Println(err)
 		return "", err
 	}
 
 	// Форматируем результат
-	durationHours := duration.Hours()
-	finish := fmt.Sprintf("Тип тренировки: %s\nДлительность: %.2f ч.\nДистанция: %.2f км.\nСкорость: %.2f км/ч\nСожгли калорий: %.2f\n",
-		activ, durationHours, dist, speed, calorie)
-	return finish, nil
+	return result.String(), nil
 }
 
 func RunningSpentCalories(steps int, weight, height float64, duration time.Duration) (float64, error) {
 	// Проверяем корректность входных параметров
 	if weight <= 0 {
-		return 0, errors.New("вес должен быть положительным")
+		return 0, errWeight
 	}
 	if height <= 0 {
-		return 0, errors.New("рост должен быть положительным")
+		return 0, errHeight
 	}
 	if steps <= 0 {
-		return 0, errors.New("количество шагов должно быть положительным")
+		return 0, errSteps
 	}
 	if duration <= 0 {
-		return 0, errors.New("продолжительность должна быть положительной")
+		return 0, errDuration
 	}
 
 	// Рассчитываем среднюю скорость
@@ -163,16 +287,16 @@ func RunningSpentCalories(steps int, weight, height float64, duration time.Durat
 func WalkingSpentCalories(steps int, weight, height float64, duration time.Duration) (float64, error) {
 	// Проверяем корректность входных параметров
 	if weight <= 0 {
-		return 0, errors.New("вес должен быть положительным")
+		return 0, errWeight
 	}
 	if height <= 0 {
-		return 0, errors.New("рост должен быть положительным")
+		return 0, errHeight
 	}
 	if steps <= 0 {
-		return 0, errors.New("количество шагов должно быть положительным")
+		return 0, errSteps
 	}
 	if duration <= 0 {
-		return 0, errors.New("продолжительность должна быть положительной")
+		return 0, errDuration
 	}
 
 	// Рассчитываем среднюю скорость
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/units"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -680,6 +682,22 @@ func (suite *SpentCaloriesTestSuite) TestTrainingInfo() {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name:    "вес вне допустимого диапазона",
+			input:   "6000,Ходьба,1h00m",
+			weight:  8460,
+			height:  1.75,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "рост в сантиметрах",
+			input:   "6000,Ходьба,1h00m",
+			weight:  75.0,
+			height:  175,
+			want:    "",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -700,3 +718,52 @@ func (suite *SpentCaloriesTestSuite) TestTrainingInfo() {
 		})
 	}
 }
+
+func (suite *SpentCaloriesTestSuite) TestTraining() {
+	got, err := Training("6000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Бег", got.Activity)
+	assert.Equal(suite.T(), 6000, got.Steps)
+	assert.Equal(suite.T(), time.Hour, got.Duration)
+	assert.InDelta(suite.T(), 4.725, got.Distance, 1e-9)
+	assert.InDelta(suite.T(), 4.725, got.Speed, 1e-9)
+	assert.InDelta(suite.T(), 354.375, got.Calories, 1e-9)
+
+	info, err := TrainingInfo("6000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), info, got.String())
+
+	_, err = Training("6000,Бег,1h00m", 0, 1.75)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SpentCaloriesTestSuite) TestTrainingTimestamp() {
+	got, err := Training("2024-03-05T07:00:00Z,6000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC), got.Start)
+	assert.Equal(suite.T(), "Бег", got.Activity)
+
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	got, err = Training("18:30,6000,Ходьба,1h00m", 75.0, 1.75, WithDate(date))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 18, 30, 0, 0, time.UTC), got.Start)
+
+	_, err = Training("18:30,6000,Ходьба,1h00m", 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, ErrTime)
+
+	var perr *ParseError
+	_, err = Training("18:30,6000,Плавание,1h00m", 75.0, 1.75, WithDate(date))
+	if assert.ErrorAs(suite.T(), err, &perr) {
+		assert.Equal(suite.T(), FieldActivity, perr.Field)
+		assert.Equal(suite.T(), 2, perr.Column)
+	}
+}
+
+func (suite *SpentCaloriesTestSuite) TestTrainingFormatImperial() {
+	got, err := Training("20000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Training type: Running\nDuration: 1.00 h.\nDistance: 9.79 mi.\nSpeed: 9.79 mph\nCalories burned: 1181.25\n",
+		got.Format(i18n.English, units.Imperial))
+	assert.Equal(suite.T(), "Тип тренировки: Бег\nДлительность: 1.00 ч.\nДистанция: 9.79 миль.\nСкорость: 9.79 миль/ч\nСожгли калорий: 1181.25\n",
+		got.Format(i18n.Russian, units.Imperial))
+}
@@ -0,0 +1,154 @@
+package spentcalories
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+)
+
+// Workout - тренировка с уже известными показателями, например импортированная
+// из файла спортивных часов. В отличие от записи для Training, дистанция
+// может быть измерена напрямую, а шаги - отсутствовать
+type Workout struct {
+	Start    time.Time
+	Activity string
+	// Количество шагов, 0 если неизвестно - тогда оно оценивается по модели шага
+	Steps    int
+	Duration time.Duration
+	// Измеренная дистанция в километрах, 0 если неизвестна - тогда она считается по шагам
+	Distance float64
+	// Набор высоты в метрах
+	Elevation float64
+	// Средний пульс в ударах в минуту, 0 если неизвестен
+	HeartRate float64
+	// Измерения пульса по времени, точнее среднего для HeartRateModel
+	HeartRateSamples []HeartRateSample
+	// Средний каденс в шагах в минуту, 0 если неизвестен. Если шаги не заданы,
+	// они считаются по каденсу точнее, чем по модели шага
+	Cadence float64
+	// Отрезки тренировки, например круги (laps) из TCX. Активность отрезков
+	// берется из тренировки, а итоги тренировки задаются отдельно
+	Segments []Workout
+}
+
+// Compute рассчитывает тренировку по уже разобранным данным так же, как Training
+func Compute(w Workout, weight, height float64, opts ...Option) (TrainingResult, error) {
+	o := newOptions(opts)
+
+	// Проверяем корректность веса и роста
+	if weight <= 0 {
+		return TrainingResult{}, errWeight
+	}
+	if height <= 0 {
+		return TrainingResult{}, errHeight
+	}
+
+	activity, ok := LookupActivity(w.Activity)
+	if !ok {
+		return TrainingResult{}, NewParseError(FieldActivity, w.Activity, -1, ErrUnknownActivity, "reason.activity_name_not_registered", w.Activity)
+	}
+	return compute(w, activity, weight, height, o)
+}
+
+func compute(w Workout, activity Activity, weight, height float64, o options) (TrainingResult, error) {
+	if w.Duration <= 0 {
+		return TrainingResult{}, errDuration
+	}
+	// Возраст по профилю на дату тренировки, отрезки считаются с тем же возрастом
+	o = o.at(w.Start)
+
+	// Вес на дату тренировки из истории взвешиваний
+	var historyWeight float64
+	if o.weight != nil && !w.Start.IsZero() {
+		if kg, ok := o.weight.WeightAt(w.Start); ok && kg > 0 {
+			weight, historyWeight = kg, kg
+		}
+	}
+
+	// Длина шага по выбранной модели или по общей модели по умолчанию
+	model := stride.Default()
+	if o.stride != nil {
+		model = o.stride
+	}
+	length := model.Length(height)
+
+	steps := w.Steps
+	if steps <= 0 && w.Cadence > 0 {
+		// Шаги неизвестны, но записан каденс
+		steps = int(math.Round(w.Cadence * w.Duration.Minutes()))
+	}
+	if steps <= 0 && w.Distance > 0 && length > 0 {
+		// Шаги неизвестны - оцениваем их по дистанции и длине шага
+		steps = int(math.Round(w.Distance * mInKm / length))
+	}
+	if steps <= 0 {
+		return TrainingResult{}, errSteps
+	}
+	if w.Distance > 0 {
+		// Дистанция измерена - фактическая длина шага следует из нее
+		length = w.Distance * mInKm / float64(steps)
+	}
+
+	// Рост, от которого формулы считают длину шага. Без модели и измеренной
+	// дистанции используем сам рост, чтобы не вносить ошибку округления
+	strideHeight := height
+	if o.stride != nil || w.Distance > 0 {
+		strideHeight = stride.EquivalentHeight(length)
+	}
+
+	// Рассчитываем калории выбранной моделью. Модель по пульсу применяется,
+	// только если пульс известен, иначе считаем по скорости
+	calorieModel := o.model
+	if calorieModel == HeartRateModel && w.HeartRate <= 0 && len(w.HeartRateSamples) == 0 {
+		calorieModel = SpeedModel
+	}
+	var calorie float64
+	var err error
+	switch calorieModel {
+	case METModel:
+		calorie, err = METSpentCalories(activity.Name, steps, weight, strideHeight, w.Duration)
+	case HeartRateModel:
+		calorie, err = heartRateCalories(w, weight, o)
+	default:
+		calorie, err = activity.Calories(steps, weight, strideHeight, w.Duration)
+	}
+	if err != nil {
+		return TrainingResult{}, err
+	}
+
+	dist := w.Distance
+	if dist <= 0 {
+		dist = distance(steps, strideHeight)
+	}
+
+	// Отрезки считаются так же, как вся тренировка
+	var segments []TrainingResult
+	for i, seg := range w.Segments {
+		seg.Activity = activity.Name
+		seg.Segments = nil
+		result, err := compute(seg, activity, weight, height, o)
+		if err != nil {
+			return TrainingResult{}, fmt.Errorf("отрезок %d: %w", i+1, err)
+		}
+		segments = append(segments, result)
+	}
+
+	// Рассчитываем дистанцию и среднюю скорость
+	return TrainingResult{
+		Start:     w.Start,
+		Activity:  activity.Name,
+		Steps:     steps,
+		Duration:  w.Duration,
+		Distance:  dist,
+		Speed:     meanSpeed(steps, strideHeight, w.Duration),
+		Calories:  calorie,
+		Model:     calorieModel,
+		Elevation: w.Elevation,
+		HeartRate: averageHeartRate(w),
+		Zones:     zoneTimes(w, o),
+		Weight:    historyWeight,
+		Segments:  segments,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package spentcalories
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/stride"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *SpentCaloriesTestSuite) TestCompute() {
+	// Без измеренной дистанции Compute совпадает с Training
+	want, err := Training("6000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	got, err := Compute(Workout{Activity: "Бег", Steps: 6000, Duration: time.Hour}, 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), want, got)
+
+	// Шаги неизвестны - оцениваются по дистанции и длине шага 0.8 м
+	got, err = Compute(Workout{Activity: "Ходьба", Duration: time.Hour, Distance: 4, Elevation: 25}, 75.0, 1.75,
+		WithStride(stride.Calibrated(0.8)))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 5000, got.Steps)
+	assert.InDelta(suite.T(), 4.0, got.Distance, 1e-9)
+	assert.InDelta(suite.T(), 4.0, got.Speed, 1e-9)
+	assert.InDelta(suite.T(), 150.0, got.Calories, 1e-9)
+	assert.Equal(suite.T(), 25.0, got.Elevation)
+
+	// Измеренная дистанция важнее длины шага по росту
+	got, err = Compute(Workout{Activity: "Бег", Steps: 10000, Duration: time.Hour, Distance: 9}, 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 9.0, got.Distance, 1e-9)
+	assert.InDelta(suite.T(), 9.0, got.Speed, 1e-9)
+
+	_, err = Compute(Workout{Activity: "Бег", Duration: time.Hour}, 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, errSteps)
+	_, err = Compute(Workout{Activity: "Парусный спорт", Steps: 100, Duration: time.Hour}, 75.0, 1.75)
+	assert.ErrorIs(suite.T(), err, ErrUnknownActivity)
+	_, err = Compute(Workout{Activity: "Бег", Steps: 100}, 75.0, 1.75)
+	assert.Error(suite.T(), err)
+}
+
+// weightByDay - история веса для тестов: вес по дате в формате ГГГГ-ММ-ДД
+type weightByDay map[string]float64
+
+func (h weightByDay) WeightAt(t time.Time) (float64, bool) {
+	kg, ok := h[t.Format(time.DateOnly)]
+	return kg, ok
+}
+
+func (suite *SpentCaloriesTestSuite) TestWeightHistory() {
+	history := WithWeightHistory(weightByDay{"2024-03-05": 90.0})
+
+	// Калории считаются по весу на дату тренировки, и он попадает в результат
+	got, err := Training("2024-03-05T07:00:00Z,8000,Бег,1h00m", 75.0, 1.75, history)
+	assert.NoError(suite.T(), err)
+	want, _ := Training("2024-03-05T07:00:00Z,8000,Бег,1h00m", 90.0, 1.75)
+	assert.InDelta(suite.T(), want.Calories, got.Calories, 1e-9)
+	assert.Equal(suite.T(), 90.0, got.Weight)
+
+	// Вес на дату неизвестен или у тренировки нет времени - используется переданный вес
+	for _, record := range []string{"2024-03-06T07:00:00Z,8000,Бег,1h00m", "8000,Бег,1h00m"} {
+		got, err := Training(record, 75.0, 1.75, history)
+		assert.NoError(suite.T(), err)
+		want, _ := Training(record, 75.0, 1.75)
+		assert.Equal(suite.T(), want, got)
+		assert.Zero(suite.T(), got.Weight)
+	}
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations - изменения схемы по порядку. Номер примененной миграции хранится
+// в PRAGMA user_version, поэтому миграции только добавляются в конец и не меняются.
+//
+// Время хранится текстом в UTC фиксированной ширины, чтобы сравнение строк
+// совпадало со сравнением моментов, а в колонке date - календарная дата в часовом
+// поясе записи. Колонка data содержит результат целиком в JSON, остальные
+// колонки повторяют его поля для SQL-запросов
+var migrations = []string{
+	// 1: пользователи и профили
+	`CREATE TABLE users (
+		id         INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL UNIQUE,
+		created_at TEXT NOT NULL
+	);
+	CREATE TABLE profiles (
+		user_id    INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		weight_kg  REAL NOT NULL,
+		height_m   REAL NOT NULL,
+		birth_date TEXT,
+		sex        TEXT,
+		data       TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);`,
+
+	// 2: пакеты дневной активности
+	`CREATE TABLE days (
+		id          INTEGER PRIMARY KEY,
+		user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		start       TEXT,
+		date        TEXT,
+		steps       INTEGER NOT NULL,
+		duration_s  REAL NOT NULL,
+		distance_km REAL NOT NULL,
+		speed_kmh   REAL NOT NULL,
+		calories    REAL NOT NULL,
+		data        TEXT NOT NULL
+	);
+	CREATE INDEX days_user_start ON days(user_id, start);`,
+
+	// 3: тренировки
+	`CREATE TABLE trainings (
+		id               INTEGER PRIMARY KEY,
+		user_id          INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		start            TEXT,
+		date             TEXT,
+		activity         TEXT NOT NULL,
+		model            TEXT NOT NULL,
+		steps            INTEGER NOT NULL,
+		duration_s       REAL NOT NULL,
+		distance_km      REAL NOT NULL,
+		speed_kmh        REAL NOT NULL,
+		calories         REAL NOT NULL,
+		elevation_gain_m REAL,
+		heart_rate_bpm   REAL,
+		weight_kg        REAL,
+		data             TEXT NOT NULL
+	);
+	CREATE INDEX trainings_user_start ON trainings(user_id, start);
+	CREATE INDEX trainings_user_activity ON trainings(user_id, activity, start);`,
+}
+
+// Version возвращает номер последней миграции, до которой Open обновляет схему
+func Version() int {
+	return len(migrations)
+}
+
+// migrate применяет недостающие миграции, каждую в своей транзакции
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("схема базы версии %d новее поддерживаемой %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("миграция %d: %w", i+1, err)
+		}
+		// PRAGMA не принимает параметры, номер подставляется в текст
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("миграция %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("миграция %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	// Драйвер SQLite на чистом Go, не требует cgo и сервера базы данных
+	_ "modernc.org/sqlite"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// timeLayout - время в UTC фиксированной ширины, строки сортируются как моменты времени
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLite - хранилище в файле SQLite
+type SQLite struct {
+	db *sql.DB
+}
+
+var _ Store = (*SQLite)(nil)
+
+// Open открывает базу в файле path, создавая его при необходимости,
+// и обновляет схему до последней версии
+func Open(ctx context.Context, path string) (*SQLite, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	// Путь экранируется, чтобы символы вроде ? и # не попали в параметры
+	dsn := url.URL{Scheme: "file", Path: path, RawQuery: params.Encode()}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+	// Одно соединение: SQLite все равно выполняет запись последовательно,
+	// а так не бывает ошибок SQLITE_BUSY между соединениями одного процесса
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("база %s: %w", path, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// DB возвращает соединение для произвольных SQL-запросов
+func (s *SQLite) DB() *sql.DB {
+	return s.db
+}
+
+// Close закрывает базу
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLite) AddUser(ctx context.Context, name string) (User, error) {
+	if name == "" {
+		return User{}, errors.New("пустое имя пользователя")
+	}
+	u := User{Name: name, Created: time.Now().UTC().Truncate(time.Microsecond)}
+	res, err := s.db.ExecContext(ctx, "INSERT INTO users (name, created_at) VALUES (?, ?)", name, formatTime(u.Created))
+	if err != nil {
+		return User{}, fmt.Errorf("пользователь %q: %w", name, err)
+	}
+	if u.ID, err = res.LastInsertId(); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *SQLite) User(ctx context.Context, name string) (User, error) {
+	u := User{Name: name}
+	var created string
+	err := s.db.QueryRowContext(ctx, "SELECT id, created_at FROM users WHERE name = ?", name).Scan(&u.ID, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("пользователь %q: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return User{}, err
+	}
+	if u.Created, err = parseTime(created); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *SQLite) EnsureUser(ctx context.Context, name string) (User, error) {
+	u, err := s.User(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return s.AddUser(ctx, name)
+	}
+	return u, err
+}
+
+func (s *SQLite) SaveProfile(ctx context.Context, userID int64, p profile.Profile) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	var birthDate any
+	if !p.BirthDate.IsZero() {
+		birthDate = p.BirthDate.String()
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO profiles (user_id, weight_kg, height_m, birth_date, sex, data, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET weight_kg = excluded.weight_kg, height_m = excluded.height_m,
+			birth_date = excluded.birth_date, sex = excluded.sex, data = excluded.data, updated_at = excluded.updated_at`,
+		userID, p.Weight, p.Height, birthDate, nullString(string(p.Sex)), string(data), formatTime(time.Now()))
+	return err
+}
+
+func (s *SQLite) Profile(ctx context.Context, userID int64) (profile.Profile, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, "SELECT data FROM profiles WHERE user_id = ?", userID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile.Profile{}, fmt.Errorf("профиль пользователя %d: %w", userID, ErrNotFound)
+	}
+	if err != nil {
+		return profile.Profile{}, err
+	}
+
+	var p profile.Profile
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return profile.Profile{}, fmt.Errorf("профиль пользователя %d: %w", userID, err)
+	}
+	return p, nil
+}
+
+func (s *SQLite) AddDays(ctx context.Context, userID int64, days ...daysteps.DayActionResult) ([]int64, error) {
+	return insert(ctx, s.db, `INSERT INTO days
+		(user_id, start, date, steps, duration_s, distance_km, speed_kmh, calories, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, days, func(d daysteps.DayActionResult) ([]any, error) {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		return []any{userID, startTime(d.Start), startDate(d.Start), d.Steps, d.Duration.Seconds(),
+			d.Distance, d.Speed, d.Calories, string(data)}, nil
+	})
+}
+
+func (s *SQLite) AddTrainings(ctx context.Context, userID int64, trainings ...spentcalories.TrainingResult) ([]int64, error) {
+	return insert(ctx, s.db, `INSERT INTO trainings
+		(user_id, start, date, activity, model, steps, duration_s, distance_km, speed_kmh, calories,
+			elevation_gain_m, heart_rate_bpm, weight_kg, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, trainings, func(t spentcalories.TrainingResult) ([]any, error) {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		return []any{userID, startTime(t.Start), startDate(t.Start), t.Activity, t.Model.String(), t.Steps,
+			t.Duration.Seconds(), t.Distance, t.Speed, t.Calories,
+			nullFloat(t.Elevation), nullFloat(t.HeartRate), nullFloat(t.Weight), string(data)}, nil
+	})
+}
+
+// insert добавляет записи одним подготовленным запросом в одной транзакции
+func insert[T any](ctx context.Context, db *sql.DB, query string, records []T, args func(T) ([]any, error)) ([]int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	ids := make([]int64, 0, len(records))
+	for _, r := range records {
+		a, err := args(r)
+		if err != nil {
+			return nil, err
+		}
+		res, err := stmt.ExecContext(ctx, a...)
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, tx.Commit()
+}
+
+func (s *SQLite) Days(ctx context.Context, userID int64, q Query) ([]Day, error) {
+	return selectRecords(ctx, s.db, "days", userID, Query{From: q.From, To: q.To, Limit: q.Limit},
+		func(id int64, data []byte) (Day, error) {
+			d := Day{ID: id, UserID: userID}
+			err := json.Unmarshal(data, &d.DayActionResult)
+			return d, err
+		})
+}
+
+func (s *SQLite) Trainings(ctx context.Context, userID int64, q Query) ([]Training, error) {
+	return selectRecords(ctx, s.db, "trainings", userID, q, func(id int64, data []byte) (Training, error) {
+		t := Training{ID: id, UserID: userID}
+		err := json.Unmarshal(data, &t.TrainingResult)
+		return t, err
+	})
+}
+
+// selectRecords выбирает записи таблицы table по запросу и разбирает их JSON-колонку
+func selectRecords[T any](ctx context.Context, db *sql.DB, table string, userID int64, q Query,
+	decode func(id int64, data []byte) (T, error)) ([]T, error) {
+	query, args := q.where(userID)
+	rows, err := db.QueryContext(ctx, "SELECT id, data FROM "+table+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []T
+	for rows.Next() {
+		var id int64
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		r, err := decode(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", table, id, err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// where возвращает условие, сортировку и ограничение SQL-запроса и их параметры
+func (q Query) where(userID int64) (string, []any) {
+	var b strings.Builder
+	args := []any{userID}
+	b.WriteString(" WHERE user_id = ?")
+	if !q.From.IsZero() {
+		b.WriteString(" AND start >= ?")
+		args = append(args, formatTime(q.From))
+	}
+	if !q.To.IsZero() {
+		b.WriteString(" AND start < ?")
+		args = append(args, formatTime(q.To))
+	}
+	if len(q.Activities) > 0 {
+		b.WriteString(" AND activity IN (?" + strings.Repeat(", ?", len(q.Activities)-1) + ")")
+		for _, a := range q.Activities {
+			// Тренировки хранятся под каноническим названием активности, поэтому
+			// псевдонимы и другой регистр приводятся к нему. Незарегистрированные
+			// названия ищутся как есть
+			if activity, ok := spentcalories.LookupActivity(a); ok {
+				a = activity.Name
+			}
+			args = append(args, a)
+		}
+	}
+	b.WriteString(" ORDER BY start, id")
+	if q.Limit > 0 {
+		b.WriteString(" LIMIT ?")
+		args = append(args, q.Limit)
+	}
+	return b.String(), args
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(timeLayout, value)
+}
+
+// startTime возвращает время начала для колонки start или NULL, если его нет
+func startTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return formatTime(t)
+}
+
+// startDate возвращает дату начала в часовом поясе записи или NULL
+func startDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
+
+func nullFloat(v float64) any {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func nullString(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
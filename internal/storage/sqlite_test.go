@@ -0,0 +1,267 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/hrzone"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SQLiteTestSuite struct {
+	suite.Suite
+	ctx   context.Context
+	path  string
+	store *SQLite
+	user  User
+}
+
+func TestSQLiteSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteTestSuite))
+}
+
+func (suite *SQLiteTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.path = filepath.Join(suite.T().TempDir(), "tracker.db")
+	var err error
+	suite.store, err = Open(suite.ctx, suite.path)
+	assert.NoError(suite.T(), err)
+	suite.user, err = suite.store.AddUser(suite.ctx, "anna")
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *SQLiteTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.store.Close())
+}
+
+var (
+	msk  = time.FixedZone("MSK", 3*60*60)
+	day0 = time.Date(2024, time.March, 5, 0, 0, 0, 0, msk)
+)
+
+// fill добавляет пакеты 5 и 6 марта и пакет без времени, бег и ходьбу 5 марта и бег 6 марта
+func (suite *SQLiteTestSuite) fill() {
+	_, err := suite.store.AddDays(suite.ctx, suite.user.ID,
+		daysteps.DayActionResult{Start: day0.Add(33 * time.Hour), Steps: 3000, Duration: 30 * time.Minute, Calories: 88},
+		daysteps.DayActionResult{Start: day0.Add(9 * time.Hour), Steps: 6000, Duration: time.Hour, Calories: 177},
+		daysteps.DayActionResult{Steps: 1000, Duration: 10 * time.Minute, Calories: 30},
+	)
+	assert.NoError(suite.T(), err)
+	_, err = suite.store.AddTrainings(suite.ctx, suite.user.ID,
+		spentcalories.TrainingResult{Start: day0.Add(8 * time.Hour), Activity: "Бег", Steps: 8000,
+			Duration: time.Hour, Calories: 472.5, Model: spentcalories.METModel},
+		spentcalories.TrainingResult{Start: day0.Add(18 * time.Hour), Activity: "Ходьба", Steps: 5000,
+			Duration: time.Hour, Calories: 210},
+		spentcalories.TrainingResult{Start: day0.Add(32 * time.Hour), Activity: "Бег", Steps: 4000,
+			Duration: 30 * time.Minute, Calories: 236},
+	)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *SQLiteTestSuite) TestMigrations() {
+	var version int
+	assert.NoError(suite.T(), suite.store.DB().QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(suite.T(), Version(), version)
+
+	// Повторное открытие не применяет миграции заново и сохраняет данные
+	suite.fill()
+	assert.NoError(suite.T(), suite.store.Close())
+	store, err := Open(suite.ctx, suite.path)
+	assert.NoError(suite.T(), err)
+	suite.store = store
+	days, err := store.Days(suite.ctx, suite.user.ID, Query{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), days, 3)
+}
+
+func (suite *SQLiteTestSuite) TestNewerSchema() {
+	_, err := suite.store.DB().Exec("PRAGMA user_version = 100")
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.store.Close())
+
+	store, err := Open(suite.ctx, suite.path)
+	assert.ErrorContains(suite.T(), err, "новее")
+	assert.Nil(suite.T(), store)
+
+	suite.store, err = Open(suite.ctx, filepath.Join(suite.T().TempDir(), "other.db"))
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *SQLiteTestSuite) TestSpecialCharsInPath() {
+	// Символы ? и # в пути относятся к имени файла, а не к параметрам DSN
+	path := filepath.Join(suite.T().TempDir(), "база?mode=ro#1.db")
+	store, err := Open(suite.ctx, path)
+	if !assert.NoError(suite.T(), err) {
+		return
+	}
+	_, err = store.AddUser(suite.ctx, "boris")
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), store.Close())
+	assert.FileExists(suite.T(), path)
+}
+
+func (suite *SQLiteTestSuite) TestUsers() {
+	u, err := suite.store.User(suite.ctx, "anna")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.user.ID, u.ID)
+	assert.True(suite.T(), suite.user.Created.Equal(u.Created))
+
+	_, err = suite.store.AddUser(suite.ctx, "anna")
+	assert.Error(suite.T(), err)
+	_, err = suite.store.AddUser(suite.ctx, "")
+	assert.Error(suite.T(), err)
+
+	_, err = suite.store.User(suite.ctx, "boris")
+	assert.ErrorIs(suite.T(), err, ErrNotFound)
+	boris, err := suite.store.EnsureUser(suite.ctx, "boris")
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), suite.user.ID, boris.ID)
+	again, err := suite.store.EnsureUser(suite.ctx, "boris")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), boris.ID, again.ID)
+}
+
+func (suite *SQLiteTestSuite) TestProfile() {
+	_, err := suite.store.Profile(suite.ctx, suite.user.ID)
+	assert.ErrorIs(suite.T(), err, ErrNotFound)
+
+	p := profile.Profile{
+		Weight:    62,
+		Height:    1.68,
+		BirthDate: profile.NewDate(1990, time.May, 17),
+		Sex:       profile.Female,
+		Zones:     &hrzone.Config{Method: hrzone.Reserve},
+	}
+	assert.NoError(suite.T(), suite.store.SaveProfile(suite.ctx, suite.user.ID, p))
+	got, err := suite.store.Profile(suite.ctx, suite.user.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), p, got)
+
+	// Повторное сохранение заменяет профиль
+	p.Weight = 60
+	assert.NoError(suite.T(), suite.store.SaveProfile(suite.ctx, suite.user.ID, p))
+	var weight float64
+	var birthDate, sex string
+	err = suite.store.DB().QueryRow("SELECT weight_kg, birth_date, sex FROM profiles WHERE user_id = ?", suite.user.ID).
+		Scan(&weight, &birthDate, &sex)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 60.0, weight)
+	assert.Equal(suite.T(), "1990-05-17", birthDate)
+	assert.Equal(suite.T(), "female", sex)
+
+	// Профиль несуществующего пользователя нарушает внешний ключ
+	assert.Error(suite.T(), suite.store.SaveProfile(suite.ctx, suite.user.ID+100, p))
+}
+
+func (suite *SQLiteTestSuite) TestDays() {
+	suite.fill()
+
+	days, err := suite.store.Days(suite.ctx, suite.user.ID, Query{})
+	assert.NoError(suite.T(), err)
+	steps := make([]int, 0, len(days))
+	for _, d := range days {
+		steps = append(steps, d.Steps)
+	}
+	// Пакет без времени первый, остальные по времени
+	assert.Equal(suite.T(), []int{1000, 6000, 3000}, steps)
+
+	days, err = suite.store.Days(suite.ctx, suite.user.ID, OnDate(day0))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), days, 1)
+	assert.Equal(suite.T(), 6000, days[0].Steps)
+	assert.True(suite.T(), day0.Add(9*time.Hour).Equal(days[0].Start))
+	assert.Equal(suite.T(), time.Hour, days[0].Duration)
+	assert.Equal(suite.T(), suite.user.ID, days[0].UserID)
+
+	// Фильтр по активностям к пакетам не применяется
+	days, err = suite.store.Days(suite.ctx, suite.user.ID, Query{Activities: []string{"Бег"}, Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), days, 2)
+
+	// Записи другого пользователя не видны
+	other, err := suite.store.AddUser(suite.ctx, "boris")
+	assert.NoError(suite.T(), err)
+	days, err = suite.store.Days(suite.ctx, other.ID, Query{})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), days)
+}
+
+func (suite *SQLiteTestSuite) TestTrainings() {
+	suite.fill()
+
+	tests := []struct {
+		name  string
+		query Query
+		steps []int
+	}{
+		{"все", Query{}, []int{8000, 5000, 4000}},
+		{"за день", OnDate(day0), []int{8000, 5000}},
+		{"по активности", Query{}.WithActivities("Бег"), []int{8000, 4000}},
+		{"за день по активности", OnDate(day0).WithActivities("Бег"), []int{8000}},
+		{"несколько активностей", Query{}.WithActivities("Бег", "Ходьба"), []int{8000, 5000, 4000}},
+		{"без учета регистра", Query{}.WithActivities("бег"), []int{8000, 4000}},
+		{"по английскому названию", Query{}.WithActivities("running"), []int{8000, 4000}},
+		{"с ограничением", Query{Limit: 1}, []int{8000}},
+		{"граница не включается", Between(day0.Add(8*time.Hour+time.Nanosecond), day0.Add(32*time.Hour)), []int{5000}},
+		{"в другом поясе", Between(day0.In(time.UTC), day0.In(time.UTC).Add(24*time.Hour)), []int{8000, 5000}},
+		{"без записей", Query{}.WithActivities("Плавание"), []int{}},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			trainings, err := suite.store.Trainings(suite.ctx, suite.user.ID, tt.query)
+			assert.NoError(suite.T(), err)
+			steps := make([]int, 0, len(trainings))
+			for _, t := range trainings {
+				steps = append(steps, t.Steps)
+			}
+			assert.Equal(suite.T(), tt.steps, steps)
+		})
+	}
+}
+
+func (suite *SQLiteTestSuite) TestTrainingRoundTrip() {
+	want := spentcalories.TrainingResult{
+		Start:     day0.Add(8 * time.Hour),
+		Activity:  "Бег",
+		Steps:     8000,
+		Duration:  time.Hour,
+		Distance:  6.2,
+		Speed:     6.2,
+		Calories:  472.5,
+		Model:     spentcalories.HeartRateModel,
+		HeartRate: 145,
+		Zones:     []hrzone.ZoneTime{{Zone: 3, Low: 130, High: 150, Duration: time.Hour}},
+		Weight:    61.5,
+		Segments:  []spentcalories.TrainingResult{{Activity: "Бег", Steps: 4000, Duration: 30 * time.Minute}},
+	}
+	ids, err := suite.store.AddTrainings(suite.ctx, suite.user.ID, want)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), ids, 1)
+
+	trainings, err := suite.store.Trainings(suite.ctx, suite.user.ID, Query{})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), trainings, 1) {
+		assert.Equal(suite.T(), ids[0], trainings[0].ID)
+		got := trainings[0].TrainingResult
+		assert.True(suite.T(), want.Start.Equal(got.Start))
+		got.Start = want.Start
+		assert.Equal(suite.T(), want, got)
+	}
+
+	// Колонки для SQL повторяют поля результата, дата - в поясе записи
+	var date, model string
+	var duration, heartRate float64
+	err = suite.store.DB().QueryRow("SELECT date, model, duration_s, heart_rate_bpm FROM trainings WHERE id = ?", ids[0]).
+		Scan(&date, &model, &duration, &heartRate)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2024-03-05", date)
+	assert.Equal(suite.T(), "hr", model)
+	assert.Equal(suite.T(), 3600.0, duration)
+	assert.Equal(suite.T(), 145.0, heartRate)
+}
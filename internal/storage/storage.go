@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// ErrNotFound - пользователь, профиль или запись не найдены
+var ErrNotFound = errors.New("не найдено")
+
+// User - пользователь, которому принадлежат профиль и записи
+type User struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// Day - сохраненный результат пакета дневной активности
+type Day struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	daysteps.DayActionResult
+}
+
+// Training - сохраненный результат тренировки
+type Training struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	spentcalories.TrainingResult
+}
+
+// Query отбирает записи пользователя. Нулевые поля не ограничивают выборку
+type Query struct {
+	// Записи с началом в [From, To). Если граница задана, записи без времени
+	// начала не попадают в выборку
+	From time.Time
+	To   time.Time
+	// Названия или псевдонимы активностей тренировок без учета регистра,
+	// для пакетов дневной активности не используются
+	Activities []string
+	// Максимальное количество записей, 0 - без ограничения
+	Limit int
+}
+
+// Between возвращает запрос записей с началом в [from, to)
+func Between(from, to time.Time) Query {
+	return Query{From: from, To: to}
+}
+
+// OnDate возвращает запрос записей за календарный день d в часовом поясе d
+func OnDate(d time.Time) Query {
+	start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+	return Between(start, start.AddDate(0, 0, 1))
+}
+
+// WithActivities возвращает копию запроса, ограниченную тренировками указанных активностей
+func (q Query) WithActivities(names ...string) Query {
+	q.Activities = append(q.Activities[:len(q.Activities):len(q.Activities)], names...)
+	return q
+}
+
+// Store - хранилище пользователей, профилей и результатов расчетов
+type Store interface {
+	// AddUser создает пользователя с уникальным именем
+	AddUser(ctx context.Context, name string) (User, error)
+	// User ищет пользователя по имени, ErrNotFound если его нет
+	User(ctx context.Context, name string) (User, error)
+	// EnsureUser возвращает пользователя по имени, создавая его при необходимости
+	EnsureUser(ctx context.Context, name string) (User, error)
+
+	// SaveProfile сохраняет профиль пользователя, заменяя прежний
+	SaveProfile(ctx context.Context, userID int64, p profile.Profile) error
+	// Profile возвращает профиль пользователя, ErrNotFound если он не сохранен
+	Profile(ctx context.Context, userID int64) (profile.Profile, error)
+
+	// AddDays и AddTrainings сохраняют результаты в одной транзакции и возвращают их номера
+	AddDays(ctx context.Context, userID int64, days ...daysteps.DayActionResult) ([]int64, error)
+	AddTrainings(ctx context.Context, userID int64, trainings ...spentcalories.TrainingResult) ([]int64, error)
+
+	// Days и Trainings возвращают записи по запросу по возрастанию времени начала,
+	// записи без времени начала идут первыми
+	Days(ctx context.Context, userID int64, q Query) ([]Day, error)
+	Trainings(ctx context.Context, userID int64, q Query) ([]Training, error)
+
+	Close() error
+}
@@ -0,0 +1,32 @@
+package stride
+
+import (
+	"fmt"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+)
+
+// Допустимые границы длины шага в метрах. Откалиброванный шаг сохраняется
+// в профиль, поэтому границы совпадают с проверкой профиля
+const (
+	MinLength = profile.MinStride
+	MaxLength = profile.MaxStride
+)
+
+// Calibrate рассчитывает длину шага по контрольной прогулке: steps шагов
+// на измеренной дистанции distance метров, например круг стадиона 400 м
+func Calibrate(steps int, distance float64) (Calibrated, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("количество шагов должно быть положительным, получено %d", steps)
+	}
+	if distance <= 0 {
+		return 0, fmt.Errorf("дистанция должна быть положительной, получено %.2f м", distance)
+	}
+
+	length := distance / float64(steps)
+	if length < MinLength || length > MaxLength {
+		return 0, fmt.Errorf("длина шага %.2f м вне допустимого диапазона %.1f-%.1f м, проверьте шаги и дистанцию",
+			length, MinLength, MaxLength)
+	}
+	return Calibrated(length), nil
+}
@@ -0,0 +1,56 @@
+package stride
+
+import (
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *StrideTestSuite) TestCalibrate() {
+	tests := []struct {
+		name     string
+		steps    int
+		distance float64
+		want     Calibrated
+		wantErr  bool
+	}{
+		{name: "круг стадиона", steps: 500, distance: 400, want: 0.8},
+		{name: "два круга", steps: 1100, distance: 800, want: Calibrated(800.0 / 1100)},
+		{name: "ноль шагов", steps: 0, distance: 400, wantErr: true},
+		{name: "отрицательная дистанция", steps: 500, distance: -400, wantErr: true},
+		{name: "слишком короткий шаг", steps: 5000, distance: 400, wantErr: true},
+		{name: "слишком длинный шаг", steps: 50, distance: 400, wantErr: true},
+		{name: "шаг длиннее двух метров", steps: 180, distance: 400, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := Calibrate(tt.steps, tt.distance)
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), float64(tt.want), float64(got), 1e-12)
+		})
+	}
+}
+
+func (suite *StrideTestSuite) TestCalibratedPassesValidation() {
+	// Любой шаг, который принимает Calibrate, проходит проверку профиля, включая границы
+	for _, distance := range []float64{1000 * MinLength, 400, 1000 * MaxLength} {
+		length, err := Calibrate(1000, distance)
+		if !assert.NoError(suite.T(), err) {
+			continue
+		}
+		p := profile.Profile{Weight: 75.0, Height: 1.75, Stride: float64(length)}
+		assert.NoError(suite.T(), p.Validate(), "шаг %.2f м", float64(length))
+	}
+
+	// За границами отказывают обе проверки
+	for _, length := range []float64{MinLength - 0.01, MaxLength + 0.01} {
+		_, err := Calibrate(1000, 1000*length)
+		assert.Error(suite.T(), err)
+		p := profile.Profile{Weight: 75.0, Height: 1.75, Stride: length}
+		assert.Error(suite.T(), p.Validate())
+	}
+}
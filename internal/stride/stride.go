@@ -0,0 +1,92 @@
+package stride
+
+import (
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+)
+
+// Константы моделей длины шага
+const (
+	// Средняя длина шага в метрах, по которой daysteps считает дистанцию без модели
+	DefaultLength = 0.65
+	// Коэффициент длины шага от роста для модели Default
+	HeightCoefficient = 0.45
+	// Коэффициенты длины шага от роста с учетом пола
+	maleCoefficient    = 0.415
+	femaleCoefficient  = 0.413
+	unknownCoefficient = (maleCoefficient + femaleCoefficient) / 2
+	// Количество метров в одном километре
+	mInKm = 1000
+)
+
+// Model рассчитывает длину шага в метрах по росту в метрах
+type Model interface {
+	Length(height float64) float64
+}
+
+// Default возвращает модель шага по росту, по которой spentcalories считает
+// дистанцию, если модель не задана явно
+func Default() Model {
+	return HeightBased(HeightCoefficient)
+}
+
+// Fixed - одинаковая длина шага в метрах для любого роста
+type Fixed float64
+
+func (f Fixed) Length(float64) float64 {
+	return float64(f)
+}
+
+// HeightBased - длина шага как доля роста
+type HeightBased float64
+
+func (h HeightBased) Length(height float64) float64 {
+	return height * float64(h)
+}
+
+// SexAdjusted - длина шага от роста с коэффициентом, зависящим от пола
+type SexAdjusted struct {
+	Sex profile.Sex
+}
+
+func (s SexAdjusted) Length(height float64) float64 {
+	switch s.Sex {
+	case profile.Male:
+		return height * maleCoefficient
+	case profile.Female:
+		return height * femaleCoefficient
+	default:
+		return height * unknownCoefficient
+	}
+}
+
+// Calibrated - длина шага в метрах, измеренная пользователем
+type Calibrated float64
+
+func (c Calibrated) Length(float64) float64 {
+	return float64(c)
+}
+
+// FromProfile выбирает модель по данным профиля: измеренный шаг, затем пол,
+// а если нет ни того, ни другого - модель Default
+func FromProfile(p profile.Profile) Model {
+	switch {
+	case p.Stride > 0:
+		return Calibrated(p.Stride)
+	case p.Sex != profile.SexUnknown:
+		return SexAdjusted{Sex: p.Sex}
+	default:
+		return Default()
+	}
+}
+
+// Distance возвращает дистанцию в километрах для steps шагов
+func Distance(m Model, steps int, height float64) float64 {
+	return float64(steps) * m.Length(height) / mInKm
+}
+
+// EquivalentHeight возвращает рост, при котором формула рост*HeightCoefficient
+// дает шаг length. Так функции расчета калорий с сигнатурой (шаги, вес, рост,
+// продолжительность) получают скорость, согласованную с выбранной моделью шага
+func EquivalentHeight(length float64) float64 {
+	return length / HeightCoefficient
+}
@@ -0,0 +1,49 @@
+package stride
+
+import (
+	"testing"
+
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StrideTestSuite struct {
+	suite.Suite
+}
+
+func TestStrideSuite(t *testing.T) {
+	suite.Run(t, new(StrideTestSuite))
+}
+
+func (suite *StrideTestSuite) TestModels() {
+	tests := []struct {
+		name  string
+		model Model
+		want  float64
+	}{
+		{name: "фиксированный шаг", model: Fixed(DefaultLength), want: 0.65},
+		{name: "от роста", model: HeightBased(HeightCoefficient), want: 0.7875},
+		{name: "мужчина", model: SexAdjusted{Sex: profile.Male}, want: 0.72625},
+		{name: "женщина", model: SexAdjusted{Sex: profile.Female}, want: 0.72275},
+		{name: "пол не указан", model: SexAdjusted{}, want: 0.7245},
+		{name: "откалиброванный шаг", model: Calibrated(0.81), want: 0.81},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			assert.InDelta(suite.T(), tt.want, tt.model.Length(1.75), 1e-9)
+			assert.InDelta(suite.T(), tt.want*10, Distance(tt.model, 10000, 1.75), 1e-9)
+		})
+	}
+}
+
+func (suite *StrideTestSuite) TestFromProfile() {
+	assert.Equal(suite.T(), HeightBased(HeightCoefficient), FromProfile(profile.Profile{Height: 1.75}))
+	assert.Equal(suite.T(), SexAdjusted{Sex: profile.Female}, FromProfile(profile.Profile{Sex: profile.Female}))
+	assert.Equal(suite.T(), Calibrated(0.8), FromProfile(profile.Profile{Sex: profile.Male, Stride: 0.8}))
+}
+
+func (suite *StrideTestSuite) TestEquivalentHeight() {
+	assert.InDelta(suite.T(), 0.72, HeightBased(HeightCoefficient).Length(EquivalentHeight(0.72)), 1e-12)
+}
@@ -0,0 +1,400 @@
+package tcx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+)
+
+// Пространства имен TCX и расширения Garmin с шагами и каденсом
+const (
+	namespace    = "http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2"
+	extNamespace = "http://www.garmin.com/xmlschemas/ActivityExtension/v2"
+)
+
+// Виды спорта, которые допускает схема TCX
+const (
+	SportRunning = "Running"
+	SportBiking  = "Biking"
+	SportOther   = "Other"
+)
+
+// Константы для пересчета единиц
+const (
+	// Количество метров в одном километре
+	mInKm = 1000
+	// Каденс бега в TCX записывается по одной ноге, шагов в минуту вдвое больше
+	stepsPerStride = 2
+)
+
+// Trackpoint - точка трека внутри круга
+type Trackpoint struct {
+	Time time.Time
+	Lat  float64
+	Lon  float64
+	// Высота в метрах
+	Altitude float64
+	// Пройденная от начала тренировки дистанция в метрах
+	Distance float64
+	// Пульс в ударах в минуту, 0 если не записан
+	HeartRate int
+	// Каденс в шагах в минуту, 0 если не записан
+	Cadence int
+}
+
+// Lap - круг тренировки
+type Lap struct {
+	Start    time.Time
+	Duration time.Duration
+	// Дистанция в километрах
+	Distance float64
+	// Калории, которые посчитало устройство
+	Calories float64
+	// Набор высоты в метрах по точкам трека
+	Elevation float64
+	// Средний и максимальный пульс, 0 если не записан
+	HeartRate    float64
+	MaxHeartRate float64
+	// Средний каденс в шагах в минуту, 0 если не записан
+	Cadence float64
+	// Количество шагов из расширения Garmin, 0 если не записано
+	Steps  int
+	Points []Trackpoint
+}
+
+// Activity - тренировка из TCX-файла
+type Activity struct {
+	Sport string
+	ID    time.Time
+	Notes string
+	Laps  []Lap
+}
+
+// Структура TCX в объеме, нужном для импорта и экспорта. Пространства имен
+// при разборе не проверяются, поэтому подходят и файлы с нестандартными префиксами
+type database struct {
+	XMLName    xml.Name      `xml:"TrainingCenterDatabase"`
+	Xmlns      string        `xml:"xmlns,attr,omitempty"`
+	Activities []xmlActivity `xml:"Activities>Activity"`
+}
+
+type xmlActivity struct {
+	Sport string   `xml:"Sport,attr"`
+	ID    string   `xml:"Id"`
+	Laps  []xmlLap `xml:"Lap"`
+	Notes string   `xml:"Notes,omitempty"`
+}
+
+type xmlLap struct {
+	StartTime     string        `xml:"StartTime,attr"`
+	TotalTime     float64       `xml:"TotalTimeSeconds"`
+	Distance      float64       `xml:"DistanceMeters"`
+	Calories      float64       `xml:"Calories"`
+	HeartRate     *xmlValue     `xml:"AverageHeartRateBpm,omitempty"`
+	MaxHeartRate  *xmlValue     `xml:"MaximumHeartRateBpm,omitempty"`
+	Intensity     string        `xml:"Intensity"`
+	TriggerMethod string        `xml:"TriggerMethod"`
+	Track         *xmlTrack     `xml:"Track,omitempty"`
+	Extensions    *xmlLapExtend `xml:"Extensions,omitempty"`
+}
+
+type xmlTrack struct {
+	Points []xmlPoint `xml:"Trackpoint"`
+}
+
+type xmlValue struct {
+	Value float64 `xml:"Value"`
+}
+
+type xmlLapExtend struct {
+	LX struct {
+		Xmlns         string  `xml:"xmlns,attr,omitempty"`
+		AvgSpeed      float64 `xml:"AvgSpeed,omitempty"`
+		AvgRunCadence float64 `xml:"AvgRunCadence,omitempty"`
+		Steps         int     `xml:"Steps,omitempty"`
+	} `xml:"LX"`
+}
+
+type xmlPoint struct {
+	Time      string    `xml:"Time"`
+	Lat       float64   `xml:"Position>LatitudeDegrees"`
+	Lon       float64   `xml:"Position>LongitudeDegrees"`
+	Altitude  *float64  `xml:"AltitudeMeters"`
+	Distance  float64   `xml:"DistanceMeters"`
+	HeartRate *xmlValue `xml:"HeartRateBpm"`
+	Cadence   int       `xml:"Extensions>TPX>RunCadence"`
+}
+
+// Parse читает все тренировки из TCX-файла
+func Parse(r io.Reader) ([]Activity, error) {
+	var db database
+	if err := xml.NewDecoder(r).Decode(&db); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать TCX: %w", err)
+	}
+
+	activities := make([]Activity, 0, len(db.Activities))
+	for i, a := range db.Activities {
+		id, err := parseTime(a.ID)
+		if err != nil {
+			return nil, fmt.Errorf("тренировка %d: %w", i+1, err)
+		}
+		activity := Activity{Sport: a.Sport, ID: id, Notes: a.Notes}
+		for j, l := range a.Laps {
+			lap, err := parseLap(l)
+			if err != nil {
+				return nil, fmt.Errorf("тренировка %d, круг %d: %w", i+1, j+1, err)
+			}
+			activity.Laps = append(activity.Laps, lap)
+		}
+		activities = append(activities, activity)
+	}
+	return activities, nil
+}
+
+func parseLap(l xmlLap) (Lap, error) {
+	start, err := parseTime(l.StartTime)
+	if err != nil {
+		return Lap{}, err
+	}
+	lap := Lap{
+		Start:    start,
+		Duration: time.Duration(math.Round(l.TotalTime * float64(time.Second))),
+		Distance: l.Distance / mInKm,
+		Calories: l.Calories,
+	}
+	if l.HeartRate != nil {
+		lap.HeartRate = l.HeartRate.Value
+	}
+	if l.MaxHeartRate != nil {
+		lap.MaxHeartRate = l.MaxHeartRate.Value
+	}
+	if l.Extensions != nil {
+		lap.Cadence = l.Extensions.LX.AvgRunCadence * stepsPerStride
+		lap.Steps = l.Extensions.LX.Steps
+	}
+
+	var heartRate, cadence float64
+	var heartRates, cadences int
+	var prevAltitude *float64
+	var points []xmlPoint
+	if l.Track != nil {
+		points = l.Track.Points
+	}
+	for _, p := range points {
+		t, err := parseTime(p.Time)
+		if err != nil {
+			return Lap{}, err
+		}
+		point := Trackpoint{Time: t, Lat: p.Lat, Lon: p.Lon, Distance: p.Distance, Cadence: p.Cadence * stepsPerStride}
+		if p.Altitude != nil {
+			point.Altitude = *p.Altitude
+			if prevAltitude != nil && *p.Altitude > *prevAltitude {
+				lap.Elevation += *p.Altitude - *prevAltitude
+			}
+			prevAltitude = p.Altitude
+		}
+		if p.HeartRate != nil {
+			point.HeartRate = int(p.HeartRate.Value)
+			heartRate += p.HeartRate.Value
+			heartRates++
+		}
+		if point.Cadence > 0 {
+			cadence += float64(point.Cadence)
+			cadences++
+		}
+		lap.Points = append(lap.Points, point)
+	}
+
+	// Если устройство не записало средние значения круга, считаем их по точкам
+	if lap.HeartRate == 0 && heartRates > 0 {
+		lap.HeartRate = heartRate / float64(heartRates)
+	}
+	if lap.Cadence == 0 && cadences > 0 {
+		lap.Cadence = cadence / float64(cadences)
+	}
+	return lap, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверное время %q", value)
+	}
+	return t, nil
+}
+
+// heartRateSamples возвращает измерения пульса из точек круга
+func (l Lap) heartRateSamples() []spentcalories.HeartRateSample {
+	var samples []spentcalories.HeartRateSample
+	for _, p := range l.Points {
+		if p.HeartRate > 0 {
+			samples = append(samples, spentcalories.HeartRateSample{Time: p.Time, BPM: float64(p.HeartRate)})
+		}
+	}
+	return samples
+}
+
+// Workout переводит тренировку TCX в тренировку spentcalories, круги становятся
+// отрезками. Активность берется из заметки, если такая зарегистрирована, иначе
+// из вида спорта, а activity используется, только если вид спорта не указан.
+// Незарегистрированный вид спорта, например Biking или Other, не подменяется
+// активностью по умолчанию: Compute отклонит такую тренировку с ErrUnknownActivity
+func (a Activity) Workout(activity string) (spentcalories.Workout, error) {
+	if len(a.Laps) == 0 {
+		return spentcalories.Workout{}, errors.New("тренировка не содержит кругов")
+	}
+
+	if _, ok := spentcalories.LookupActivity(a.Notes); ok && a.Notes != "" {
+		activity = a.Notes
+	} else if a.Sport != "" {
+		activity = a.Sport
+	}
+
+	w := spentcalories.Workout{Start: a.ID, Activity: activity}
+	allSteps, allCadence := true, true
+	var heartRate, heartMinutes, cadence float64
+	for _, lap := range a.Laps {
+		if lap.Duration <= 0 {
+			continue
+		}
+		samples := lap.heartRateSamples()
+		w.Segments = append(w.Segments, spentcalories.Workout{
+			Start:            lap.Start,
+			Steps:            lap.Steps,
+			Duration:         lap.Duration,
+			Distance:         lap.Distance,
+			Elevation:        lap.Elevation,
+			HeartRate:        lap.HeartRate,
+			HeartRateSamples: samples,
+			Cadence:          lap.Cadence,
+		})
+		w.HeartRateSamples = append(w.HeartRateSamples, samples...)
+		w.Steps += lap.Steps
+		w.Duration += lap.Duration
+		w.Distance += lap.Distance
+		w.Elevation += lap.Elevation
+		allSteps = allSteps && lap.Steps > 0
+		allCadence = allCadence && lap.Cadence > 0
+		cadence += lap.Cadence * lap.Duration.Minutes()
+		if lap.HeartRate > 0 {
+			heartRate += lap.HeartRate * lap.Duration.Minutes()
+			heartMinutes += lap.Duration.Minutes()
+		}
+	}
+	if w.Duration <= 0 {
+		return spentcalories.Workout{}, errors.New("продолжительность тренировки должна быть положительной")
+	}
+
+	// Итоговые шаги и каденс известны, только если они записаны во всех кругах
+	if !allSteps {
+		w.Steps = 0
+	}
+	if allCadence {
+		w.Cadence = cadence / w.Duration.Minutes()
+	}
+	if heartMinutes > 0 {
+		w.HeartRate = heartRate / heartMinutes
+	}
+	if w.Start.IsZero() {
+		w.Start = a.Laps[0].Start
+	}
+	return w, nil
+}
+
+// Import читает TCX-файл и рассчитывает каждую тренировку из него
+func Import(r io.Reader, activity string, weight, height float64, opts ...spentcalories.Option) ([]spentcalories.TrainingResult, error) {
+	activities, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]spentcalories.TrainingResult, 0, len(activities))
+	for i, a := range activities {
+		w, err := a.Workout(activity)
+		if err != nil {
+			return nil, fmt.Errorf("тренировка %d: %w", i+1, err)
+		}
+		result, err := spentcalories.Compute(w, weight, height, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("тренировка %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Export записывает рассчитанные тренировки в формате TCX. Отрезки становятся
+// кругами, а название активности сохраняется в заметке, чтобы Import нашел ее снова
+func Export(w io.Writer, results []spentcalories.TrainingResult) error {
+	db := database{Xmlns: namespace}
+	for i, r := range results {
+		if r.Start.IsZero() {
+			return fmt.Errorf("тренировка %d: для экспорта в TCX нужно время начала", i+1)
+		}
+
+		laps := r.Segments
+		if len(laps) == 0 {
+			laps = []spentcalories.TrainingResult{r}
+		}
+		activity := xmlActivity{Sport: sport(r.Activity), ID: formatTime(r.Start), Notes: r.Activity}
+		for _, lap := range laps {
+			start := lap.Start
+			if start.IsZero() {
+				start = r.Start
+			}
+			l := xmlLap{
+				StartTime:     formatTime(start),
+				TotalTime:     lap.Duration.Seconds(),
+				Distance:      lap.Distance * mInKm,
+				Calories:      math.Round(lap.Calories),
+				Intensity:     "Active",
+				TriggerMethod: "Manual",
+				Extensions:    &xmlLapExtend{},
+			}
+			if lap.HeartRate > 0 {
+				l.HeartRate = &xmlValue{Value: math.Round(lap.HeartRate)}
+			}
+			l.Extensions.LX.Xmlns = extNamespace
+			l.Extensions.LX.AvgSpeed = lap.Distance * mInKm / lap.Duration.Seconds()
+			l.Extensions.LX.Steps = lap.Steps
+			activity.Laps = append(activity.Laps, l)
+		}
+		db.Activities = append(db.Activities, activity)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(db); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sport подбирает вид спорта TCX по английскому названию активности
+func sport(name string) string {
+	if a, ok := spentcalories.LookupActivity(name); ok {
+		name = a.Title(i18n.English)
+	}
+	switch name {
+	case SportRunning:
+		return SportRunning
+	case SportBiking, "Cycling":
+		return SportBiking
+	default:
+		return SportOther
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
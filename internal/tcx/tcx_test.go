@@ -0,0 +1,205 @@
+package tcx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2"
+    xmlns:ns3="http://www.garmin.com/xmlschemas/ActivityExtension/v2">
+  <Activities>
+    <Activity Sport="Running">
+      <Id>2024-03-05T07:00:00Z</Id>
+      <Lap StartTime="2024-03-05T07:00:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>2000</DistanceMeters>
+        <Calories>160</Calories>
+        <AverageHeartRateBpm><Value>140</Value></AverageHeartRateBpm>
+        <MaximumHeartRateBpm><Value>155</Value></MaximumHeartRateBpm>
+        <Intensity>Active</Intensity>
+        <TriggerMethod>Manual</TriggerMethod>
+        <Track>
+          <Trackpoint>
+            <Time>2024-03-05T07:00:00Z</Time>
+            <AltitudeMeters>100</AltitudeMeters>
+            <DistanceMeters>0</DistanceMeters>
+            <HeartRateBpm><Value>130</Value></HeartRateBpm>
+          </Trackpoint>
+          <Trackpoint>
+            <Time>2024-03-05T07:10:00Z</Time>
+            <AltitudeMeters>108</AltitudeMeters>
+            <DistanceMeters>2000</DistanceMeters>
+            <HeartRateBpm><Value>150</Value></HeartRateBpm>
+          </Trackpoint>
+        </Track>
+        <Extensions><ns3:LX><ns3:AvgRunCadence>80</ns3:AvgRunCadence><ns3:Steps>1600</ns3:Steps></ns3:LX></Extensions>
+      </Lap>
+      <Lap StartTime="2024-03-05T07:10:00Z">
+        <TotalTimeSeconds>1200</TotalTimeSeconds>
+        <DistanceMeters>3000</DistanceMeters>
+        <Calories>200</Calories>
+        <Intensity>Active</Intensity>
+        <TriggerMethod>Distance</TriggerMethod>
+        <Track>
+          <Trackpoint>
+            <Time>2024-03-05T07:10:00Z</Time>
+            <HeartRateBpm><Value>150</Value></HeartRateBpm>
+            <Extensions><ns3:TPX><ns3:RunCadence>85</ns3:RunCadence></ns3:TPX></Extensions>
+          </Trackpoint>
+          <Trackpoint>
+            <Time>2024-03-05T07:30:00Z</Time>
+            <HeartRateBpm><Value>170</Value></HeartRateBpm>
+            <Extensions><ns3:TPX><ns3:RunCadence>75</ns3:RunCadence></ns3:TPX></Extensions>
+          </Trackpoint>
+        </Track>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+type TCXTestSuite struct {
+	suite.Suite
+}
+
+func TestTCXSuite(t *testing.T) {
+	suite.Run(t, new(TCXTestSuite))
+}
+
+func (suite *TCXTestSuite) TestParse() {
+	activities, err := Parse(strings.NewReader(sample))
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), activities, 1) {
+		return
+	}
+	a := activities[0]
+	assert.Equal(suite.T(), SportRunning, a.Sport)
+	assert.Equal(suite.T(), time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC), a.ID)
+	if !assert.Len(suite.T(), a.Laps, 2) {
+		return
+	}
+
+	first := a.Laps[0]
+	assert.Equal(suite.T(), 10*time.Minute, first.Duration)
+	assert.InDelta(suite.T(), 2.0, first.Distance, 1e-9)
+	assert.Equal(suite.T(), 160.0, first.Calories)
+	assert.Equal(suite.T(), 140.0, first.HeartRate)
+	assert.Equal(suite.T(), 155.0, first.MaxHeartRate)
+	assert.Equal(suite.T(), 160.0, first.Cadence)
+	assert.Equal(suite.T(), 1600, first.Steps)
+	assert.Equal(suite.T(), 8.0, first.Elevation)
+	assert.Len(suite.T(), first.Points, 2)
+
+	// Средние значения второго круга считаются по точкам
+	second := a.Laps[1]
+	assert.Equal(suite.T(), 160.0, second.HeartRate)
+	assert.Equal(suite.T(), 160.0, second.Cadence)
+	assert.Equal(suite.T(), 0, second.Steps)
+	assert.Equal(suite.T(), 170, second.Points[1].HeartRate)
+}
+
+func (suite *TCXTestSuite) TestImport() {
+	results, err := Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), results, 1) {
+		return
+	}
+
+	r := results[0]
+	assert.Equal(suite.T(), "Бег", r.Activity)
+	assert.Equal(suite.T(), 30*time.Minute, r.Duration)
+	assert.InDelta(suite.T(), 5.0, r.Distance, 1e-9)
+	assert.InDelta(suite.T(), 10.0, r.Speed, 1e-9)
+	// Шаги второго круга не записаны, поэтому итог считается по каденсу
+	assert.Equal(suite.T(), 4800, r.Steps)
+	assert.InDelta(suite.T(), 153.33, r.HeartRate, 0.01)
+	// Калории считаются заново по скорости: 75 * 10 * 0.5
+	assert.InDelta(suite.T(), 375.0, r.Calories, 1e-9)
+
+	if assert.Len(suite.T(), r.Segments, 2) {
+		assert.Equal(suite.T(), 1600, r.Segments[0].Steps)
+		assert.InDelta(suite.T(), 12.0, r.Segments[0].Speed, 1e-9)
+		assert.Equal(suite.T(), 3200, r.Segments[1].Steps)
+		assert.InDelta(suite.T(), 9.0, r.Segments[1].Speed, 1e-9)
+	}
+}
+
+func (suite *TCXTestSuite) TestRoundTrip() {
+	results, err := Import(strings.NewReader(sample), "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+
+	var buf bytes.Buffer
+	assert.NoError(suite.T(), Export(&buf, results))
+	again, err := Import(&buf, "Ходьба", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), again, 1) {
+		return
+	}
+	assert.Equal(suite.T(), results[0].Activity, again[0].Activity)
+	assert.Equal(suite.T(), results[0].Start, again[0].Start)
+	assert.Equal(suite.T(), results[0].Steps, again[0].Steps)
+	assert.Equal(suite.T(), results[0].Duration, again[0].Duration)
+	assert.InDelta(suite.T(), results[0].Distance, again[0].Distance, 1e-9)
+	assert.InDelta(suite.T(), results[0].Calories, again[0].Calories, 1e-9)
+	assert.Len(suite.T(), again[0].Segments, 2)
+
+	// Тренировка из обычной записи становится одним кругом, активность сохраняется в заметке
+	walk, err := spentcalories.Training("2024-03-05T18:00:00+03:00,6000,Ходьба,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	buf.Reset()
+	assert.NoError(suite.T(), Export(&buf, []spentcalories.TrainingResult{walk}))
+	assert.Contains(suite.T(), buf.String(), `Sport="Other"`)
+	again, err = Import(&buf, "Бег", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Ходьба", again[0].Activity)
+	assert.InDelta(suite.T(), walk.Calories, again[0].Calories, 1e-9)
+	assert.True(suite.T(), walk.Start.Equal(again[0].Start))
+}
+
+func (suite *TCXTestSuite) TestErrors() {
+	_, err := Parse(strings.NewReader("<TrainingCenterDatabase"))
+	assert.Error(suite.T(), err)
+
+	noLaps := `<TrainingCenterDatabase><Activities><Activity Sport="Running"><Id>2024-03-05T07:00:00Z</Id></Activity></Activities></TrainingCenterDatabase>`
+	_, err = Import(strings.NewReader(noLaps), "Бег", 75.0, 1.75)
+	assert.Error(suite.T(), err)
+
+	// Вид спорта без зарегистрированной активности не считается активностью по умолчанию
+	for _, sport := range []string{SportBiking, SportOther} {
+		unknown := strings.Replace(sample, `Sport="Running"`, `Sport="`+sport+`"`, 1)
+		results, err := Import(strings.NewReader(unknown), "Ходьба", 75.0, 1.75)
+		assert.ErrorIs(suite.T(), err, spentcalories.ErrUnknownActivity, sport)
+		assert.Empty(suite.T(), results)
+	}
+
+	badTime := strings.Replace(sample, "<Id>2024-03-05T07:00:00Z</Id>", "<Id>вчера</Id>", 1)
+	_, err = Parse(strings.NewReader(badTime))
+	assert.Error(suite.T(), err)
+
+	// Без времени начала экспорт невозможен
+	r, err := spentcalories.Training("6000,Бег,1h00m", 75.0, 1.75)
+	assert.NoError(suite.T(), err)
+	assert.Error(suite.T(), Export(&bytes.Buffer{}, []spentcalories.TrainingResult{r}))
+}
+
+func (suite *TCXTestSuite) TestImportHeartRateModel() {
+	results, err := Import(strings.NewReader(sample), "Бег", 75.0, 1.75,
+		spentcalories.WithModel(spentcalories.HeartRateModel), spentcalories.WithAge(30))
+	assert.NoError(suite.T(), err)
+	if !assert.Len(suite.T(), results, 1) {
+		return
+	}
+
+	r := results[0]
+	assert.Equal(suite.T(), spentcalories.HeartRateModel, r.Model)
+	// Калории всей тренировки считаются по тем же измерениям пульса, что и круги
+	if assert.Len(suite.T(), r.Segments, 2) {
+		assert.InDelta(suite.T(), r.Segments[0].Calories+r.Segments[1].Calories, r.Calories, 1e-9)
+	}
+}
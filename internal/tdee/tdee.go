@@ -0,0 +1,61 @@
+package tdee
+
+import (
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Report - суммарный расход энергии за день (TDEE): основной обмен,
+// дневная ходьба и тренировки
+type Report struct {
+	Date time.Time `json:"date"`
+	// Формула и величина основного обмена в ккал
+	Formula spentcalories.BMRFormula `json:"bmr_formula"`
+	BMR     float64                  `json:"bmr"`
+	// Шаги и калории дневной активности из пакетов шагов
+	Steps   int     `json:"steps"`
+	Walking float64 `json:"walking_calories"`
+	// Количество тренировок дня и потраченные на них калории
+	Trainings int     `json:"trainings"`
+	Training  float64 `json:"training_calories"`
+	// Итог: основной обмен + ходьба + тренировки
+	Total float64 `json:"total_calories"`
+}
+
+// Format форматирует отчет на языке loc. Единицы не влияют на отчет, он только в ккал
+func (r Report) Format(loc i18n.Locale, _ units.System) string {
+	return loc.T("tdee.report", r.Date.Format(time.DateOnly), r.BMR, r.Formula.String(),
+		loc.Count(r.Steps, "noun.steps"), r.Walking, r.Trainings, r.Training, r.Total)
+}
+
+// Day собирает отчет за день day с основным обменом bmr по формуле formula.
+// Учитываются тренировки этого дня в часовом поясе day.Date и тренировки без времени
+// начала, которые считаются записанными в этот день
+func Day(formula spentcalories.BMRFormula, bmr float64, day daysteps.DaySummary, trainings []spentcalories.TrainingResult) Report {
+	r := Report{
+		Date:    day.Date,
+		Formula: formula,
+		BMR:     bmr,
+		Steps:   day.Steps,
+		Walking: day.Calories,
+	}
+	for _, t := range trainings {
+		if !t.Start.IsZero() && !sameDay(t.Start, day.Date) {
+			continue
+		}
+		r.Trainings++
+		r.Training += t.Calories
+	}
+	r.Total = r.BMR + r.Walking + r.Training
+	return r
+}
+
+func sameDay(t, day time.Time) bool {
+	y, m, d := t.In(day.Location()).Date()
+	dy, dm, dd := day.Date()
+	return y == dy && m == dm && d == dd
+}
@@ -0,0 +1,70 @@
+package tdee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/daysteps"
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TDEETestSuite struct {
+	suite.Suite
+}
+
+func TestTDEESuite(t *testing.T) {
+	suite.Run(t, new(TDEETestSuite))
+}
+
+func (suite *TDEETestSuite) TestDay() {
+	msk := time.FixedZone("MSK", 3*60*60)
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, msk)
+	day := daysteps.DaySummary{Date: date, Steps: 9000, Calories: 245.5}
+	trainings := []spentcalories.TrainingResult{
+		{Start: date.Add(7 * time.Hour), Calories: 400},
+		// Без времени начала тренировка относится к дню отчета
+		{Calories: 100},
+		// 2024-03-05 23:30 по Москве, хотя в UTC это еще 20:30
+		{Start: time.Date(2024, time.March, 5, 20, 30, 0, 0, time.UTC), Calories: 50},
+		// Другой день
+		{Start: date.Add(25 * time.Hour), Calories: 1000},
+		{Start: time.Date(2024, time.March, 4, 20, 0, 0, 0, time.UTC), Calories: 1000},
+	}
+
+	got := Day(spentcalories.MifflinStJeor, 1700, day, trainings)
+	assert.Equal(suite.T(), Report{
+		Date:      date,
+		Formula:   spentcalories.MifflinStJeor,
+		BMR:       1700,
+		Steps:     9000,
+		Walking:   245.5,
+		Trainings: 3,
+		Training:  550,
+		Total:     2495.5,
+	}, got)
+
+	// День без шагов и тренировок - только основной обмен
+	empty := Day(spentcalories.HarrisBenedict, 1650, daysteps.DaySummary{Date: date}, nil)
+	assert.Equal(suite.T(), 1650.0, empty.Total)
+}
+
+func (suite *TDEETestSuite) TestFormat() {
+	r := Report{
+		Date:      time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+		Formula:   spentcalories.MifflinStJeor,
+		BMR:       1698.75,
+		Steps:     9001,
+		Walking:   245.11,
+		Trainings: 1,
+		Training:  435.75,
+		Total:     2379.61,
+	}
+
+	assert.Equal(suite.T(), "Дата: 2024-03-05\nОсновной обмен (mifflin): 1698.75 ккал.\nХодьба: 9001 шаг, 245.11 ккал.\n"+
+		"Тренировки: 1, 435.75 ккал.\nВсего за день: 2379.61 ккал.\n", r.Format(i18n.Russian, units.Metric))
+	assert.Contains(suite.T(), r.Format(i18n.English, units.Metric), "Total for the day: 2379.61 kcal.")
+}
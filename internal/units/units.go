@@ -0,0 +1,271 @@
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// System - система единиц для ввода и вывода. Внутри трекера все величины
+// хранятся в метрической системе: кг, м, км, км/ч
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// Коэффициенты перевода
+const (
+	kgInLb     = 0.45359237
+	kmInMile   = 1.609344
+	mInFoot    = 0.3048
+	mInInch    = 0.0254
+	mInYard    = 0.9144
+	mInKm      = 1000
+	inchInFoot = 12
+	cmInM      = 100
+)
+
+// ParseSystem разбирает название системы единиц
+func ParseSystem(s string) (System, error) {
+	switch System(strings.ToLower(strings.TrimSpace(s))) {
+	case Metric:
+		return Metric, nil
+	case Imperial:
+		return Imperial, nil
+	default:
+		return "", fmt.Errorf("неизвестная система единиц %q: ожидается metric или imperial", s)
+	}
+}
+
+func KgToLb(kg float64) float64 { return kg / kgInLb }
+func LbToKg(lb float64) float64 { return lb * kgInLb }
+
+func KmToMiles(km float64) float64 { return km / kmInMile }
+func MilesToKm(mi float64) float64 { return mi * kmInMile }
+
+func MToFeet(m float64) float64  { return m / mInFoot }
+func FeetToM(ft float64) float64 { return ft * mInFoot }
+
+func MToInches(m float64) float64  { return m / mInInch }
+func InchesToM(in float64) float64 { return in * mInInch }
+
+func MToYards(m float64) float64  { return m / mInYard }
+func YardsToM(yd float64) float64 { return yd * mInYard }
+
+// FeetInchesToM переводит рост в футах и дюймах в метры
+func FeetInchesToM(feet, inches float64) float64 {
+	return feet*mInFoot + inches*mInInch
+}
+
+// MToFeetInches переводит метры в целые футы и дюймы
+func MToFeetInches(m float64) (feet int, inches float64) {
+	total := m / mInInch
+	feet = int(total / inchInFoot)
+	return feet, total - float64(feet*inchInFoot)
+}
+
+// Distance переводит километры в единицы системы
+func (s System) Distance(km float64) float64 {
+	if s == Imperial {
+		return KmToMiles(km)
+	}
+	return km
+}
+
+// Speed переводит км/ч в единицы системы
+func (s System) Speed(kmh float64) float64 {
+	// Скорость переводится тем же коэффициентом, что и дистанция
+	return s.Distance(kmh)
+}
+
+// DistanceKey возвращает ключ каталога i18n с обозначением единицы дистанции
+func (s System) DistanceKey() string {
+	if s == Imperial {
+		return "unit.mi"
+	}
+	return "unit.km"
+}
+
+// SpeedKey возвращает ключ каталога i18n с обозначением единицы скорости
+func (s System) SpeedKey() string {
+	if s == Imperial {
+		return "unit.mph"
+	}
+	return "unit.kmh"
+}
+
+// WeightKey возвращает ключ каталога i18n с обозначением единицы веса
+func (s System) WeightKey() string {
+	if s == Imperial {
+		return "unit.lb"
+	}
+	return "unit.kg"
+}
+
+// Weight переводит килограммы в единицы системы
+func (s System) Weight(kg float64) float64 {
+	if s == Imperial {
+		return KgToLb(kg)
+	}
+	return kg
+}
+
+// Суффиксы имен полей структурированного вывода и их замены в имперской системе
+var imperialFields = []struct {
+	metric, imperial string
+	convert          func(float64) float64
+}{
+	{metric: "_kmh", imperial: "_mph", convert: KmToMiles},
+	{metric: "_km", imperial: "_mi", convert: KmToMiles},
+	{metric: "_kg", imperial: "_lb", convert: KgToLb},
+	{metric: "_m", imperial: "_ft", convert: MToFeet},
+}
+
+// Field переводит поле структурированного вывода вида distance_km в систему s:
+// возвращает новое имя поля и значение, например distance_mi и мили
+func (s System) Field(name string, v float64) (string, float64) {
+	if s != Imperial {
+		return name, v
+	}
+	for _, f := range imperialFields {
+		if strings.HasSuffix(name, f.metric) {
+			return strings.TrimSuffix(name, f.metric) + f.imperial, f.convert(v)
+		}
+	}
+	return name, v
+}
+
+// FieldName возвращает имя поля в системе s
+func (s System) FieldName(name string) string {
+	name, _ = s.Field(name, 0)
+	return name
+}
+
+// Length форматирует длину в метрах в единицах системы: метры или дюймы.
+// Результат разбирается обратно ParseStride и ParseHeight
+func (s System) Length(m float64) string {
+	if s == Imperial {
+		return fmt.Sprintf("%.1f in", MToInches(m))
+	}
+	return fmt.Sprintf("%.2f m", m)
+}
+
+// Форматы записи длины: 1.87, 187cm, 1.87m, 5'11", 5ft 11in, 71in
+var (
+	feetInchesRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:'|ft)\s*(?:(\d+(?:\.\d+)?)\s*(?:"|in)?)?$`)
+	suffixRe     = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]*)$`)
+)
+
+// ParseHeight разбирает рост и возвращает его в метрах. Число без единиц
+// трактуется как метры в метрической системе и как дюймы в имперской
+func ParseHeight(s string, sys System) (float64, error) {
+	return parseLength(s, sys, "рост", "роста")
+}
+
+// ParseStride разбирает длину шага и возвращает ее в метрах. Форматы те же,
+// что и у роста: число без единиц - метры или дюймы в имперской системе
+func ParseStride(s string, sys System) (float64, error) {
+	return parseLength(s, sys, "длину шага", "длины шага")
+}
+
+// parseLength разбирает длину в метрах, сантиметрах, футах и дюймах.
+// what и of - название величины для сообщений об ошибках
+func parseLength(s string, sys System, what, of string) (float64, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+
+	if m := feetInchesRe.FindStringSubmatch(value); m != nil {
+		feet, _ := strconv.ParseFloat(m[1], 64)
+		var inches float64
+		if m[2] != "" {
+			inches, _ = strconv.ParseFloat(m[2], 64)
+		}
+		return FeetInchesToM(feet, inches), nil
+	}
+
+	m := suffixRe.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("не удалось разобрать %s %q", what, s)
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+
+	unit := m[2]
+	if unit == "" {
+		unit = "m"
+		if sys == Imperial {
+			unit = "in"
+		}
+	}
+	switch unit {
+	case "m":
+		return v, nil
+	case "cm":
+		return v / cmInM, nil
+	case "in":
+		return InchesToM(v), nil
+	case "ft":
+		return FeetToM(v), nil
+	default:
+		return 0, fmt.Errorf("неизвестная единица %s %q", of, m[2])
+	}
+}
+
+// ParseDistance разбирает дистанцию и возвращает ее в метрах. Число без единиц
+// трактуется как метры в метрической системе и как ярды в имперской
+func ParseDistance(s string, sys System) (float64, error) {
+	m := suffixRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("не удалось разобрать дистанцию %q", s)
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+
+	unit := m[2]
+	if unit == "" {
+		unit = "m"
+		if sys == Imperial {
+			unit = "yd"
+		}
+	}
+	switch unit {
+	case "m":
+		return v, nil
+	case "km":
+		return v * mInKm, nil
+	case "ft":
+		return FeetToM(v), nil
+	case "yd":
+		return YardsToM(v), nil
+	case "mi":
+		return MilesToKm(v) * mInKm, nil
+	default:
+		return 0, fmt.Errorf("неизвестная единица дистанции %q", m[2])
+	}
+}
+
+// ParseWeight разбирает вес и возвращает его в килограммах. Число без единиц
+// трактуется как килограммы в метрической системе и как фунты в имперской
+func ParseWeight(s string, sys System) (float64, error) {
+	m := suffixRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("не удалось разобрать вес %q", s)
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+
+	unit := m[2]
+	if unit == "" {
+		unit = "kg"
+		if sys == Imperial {
+			unit = "lb"
+		}
+	}
+	switch unit {
+	case "kg":
+		return v, nil
+	case "lb", "lbs":
+		return LbToKg(v), nil
+	default:
+		return 0, fmt.Errorf("неизвестная единица веса %q", m[2])
+	}
+}
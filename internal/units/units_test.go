@@ -0,0 +1,192 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UnitsTestSuite struct {
+	suite.Suite
+}
+
+func TestUnitsSuite(t *testing.T) {
+	suite.Run(t, new(UnitsTestSuite))
+}
+
+func (suite *UnitsTestSuite) TestRoundTrip() {
+	for _, v := range []float64{0, 0.001, 1, 1.87, 42.195, 84.6, 160.9344, 1e6} {
+		assert.InDelta(suite.T(), v, LbToKg(KgToLb(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, KgToLb(LbToKg(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, MilesToKm(KmToMiles(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, KmToMiles(MilesToKm(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, FeetToM(MToFeet(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, InchesToM(MToInches(v)), 1e-9*(1+v))
+		assert.InDelta(suite.T(), v, YardsToM(MToYards(v)), 1e-9*(1+v))
+
+		feet, inches := MToFeetInches(v)
+		assert.InDelta(suite.T(), v, FeetInchesToM(float64(feet), inches), 1e-9*(1+v))
+	}
+}
+
+func (suite *UnitsTestSuite) TestKnownValues() {
+	assert.InDelta(suite.T(), 26.2188, KmToMiles(42.195), 1e-4)
+	assert.InDelta(suite.T(), 186.51, KgToLb(84.6), 1e-2)
+	assert.InDelta(suite.T(), 1.8034, FeetInchesToM(5, 11), 1e-4)
+
+	feet, inches := MToFeetInches(1.87)
+	assert.Equal(suite.T(), 6, feet)
+	assert.InDelta(suite.T(), 1.622, inches, 1e-3)
+}
+
+func (suite *UnitsTestSuite) TestParseHeight() {
+	tests := []struct {
+		input   string
+		sys     System
+		want    float64
+		wantErr bool
+	}{
+		{input: "1.87", sys: Metric, want: 1.87},
+		{input: "187cm", sys: Metric, want: 1.87},
+		{input: "1.87 m", sys: Metric, want: 1.87},
+		{input: `5'11"`, sys: Imperial, want: 1.8034},
+		{input: `5'11`, sys: Metric, want: 1.8034},
+		{input: "5ft 11in", sys: Imperial, want: 1.8034},
+		{input: "6ft", sys: Imperial, want: 1.8288},
+		{input: "71", sys: Imperial, want: 1.8034},
+		{input: "71in", sys: Metric, want: 1.8034},
+		{input: "tall", sys: Metric, wantErr: true},
+		{input: "180mm", sys: Metric, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.input, func() {
+			got, err := ParseHeight(tt.input, tt.sys)
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+}
+
+func (suite *UnitsTestSuite) TestParseWeight() {
+	got, err := ParseWeight("84.6", Metric)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 84.6, got)
+
+	got, err = ParseWeight("186.5", Imperial)
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 84.595, got, 1e-3)
+
+	got, err = ParseWeight("186.5 lbs", Metric)
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 84.595, got, 1e-3)
+
+	_, err = ParseWeight("-5", Metric)
+	assert.Error(suite.T(), err)
+	_, err = ParseWeight("10st", Imperial)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *UnitsTestSuite) TestParseStride() {
+	tests := []struct {
+		input   string
+		sys     System
+		want    float64
+		wantErr bool
+	}{
+		{input: "0.78", sys: Metric, want: 0.78},
+		{input: "78cm", sys: Metric, want: 0.78},
+		{input: "30", sys: Imperial, want: 0.762},
+		{input: "30in", sys: Metric, want: 0.762},
+		{input: "2.5ft", sys: Imperial, want: 0.762},
+		{input: `2'6"`, sys: Imperial, want: 0.762},
+		{input: "long", sys: Metric, wantErr: true},
+		{input: "1yd", sys: Imperial, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.input, func() {
+			got, err := ParseStride(tt.input, tt.sys)
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+}
+
+func (suite *UnitsTestSuite) TestParseDistance() {
+	tests := []struct {
+		input   string
+		sys     System
+		want    float64
+		wantErr bool
+	}{
+		{input: "400", sys: Metric, want: 400},
+		{input: "400m", sys: Imperial, want: 400},
+		{input: "1.5km", sys: Metric, want: 1500},
+		{input: "440", sys: Imperial, want: 402.336},
+		{input: "440yd", sys: Metric, want: 402.336},
+		{input: "0.25mi", sys: Metric, want: 402.336},
+		{input: "1320ft", sys: Imperial, want: 402.336},
+		{input: "far", sys: Metric, wantErr: true},
+		{input: "1nmi", sys: Imperial, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.input, func() {
+			got, err := ParseDistance(tt.input, tt.sys)
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				return
+			}
+			assert.NoError(suite.T(), err)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+}
+
+func (suite *UnitsTestSuite) TestLengthRoundTrip() {
+	for _, sys := range []System{Metric, Imperial} {
+		for _, m := range []float64{0.3, 0.65, 0.7875, 1.2, 2.5} {
+			got, err := ParseStride(sys.Length(m), sys)
+			assert.NoError(suite.T(), err)
+			// Length округляет до сантиметра или десятой доли дюйма
+			assert.InDelta(suite.T(), m, got, 0.005)
+		}
+	}
+	assert.Equal(suite.T(), "0.79 m", Metric.Length(0.7875))
+	assert.Equal(suite.T(), "31.0 in", Imperial.Length(0.7875))
+}
+
+func (suite *UnitsTestSuite) TestField() {
+	name, v := Imperial.Field("distance_km", 1.609344)
+	assert.Equal(suite.T(), "distance_mi", name)
+	assert.InDelta(suite.T(), 1, v, 1e-12)
+
+	name, v = Imperial.Field("speed_kmh", 16.09344)
+	assert.Equal(suite.T(), "speed_mph", name)
+	assert.InDelta(suite.T(), 10, v, 1e-12)
+
+	name, v = Imperial.Field("elevation_gain_m", 30.48)
+	assert.Equal(suite.T(), "elevation_gain_ft", name)
+	assert.InDelta(suite.T(), 100, v, 1e-12)
+
+	name, v = Imperial.Field("calories", 100)
+	assert.Equal(suite.T(), "calories", name)
+	assert.Equal(suite.T(), 100.0, v)
+
+	assert.Equal(suite.T(), "distance_km", Metric.FieldName("distance_km"))
+
+	_, err := ParseSystem("Imperial")
+	assert.NoError(suite.T(), err)
+	_, err = ParseSystem("nautical")
+	assert.Error(suite.T(), err)
+}
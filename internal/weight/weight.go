@@ -0,0 +1,202 @@
+package weight
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+)
+
+// Entry - взвешивание в один из дней
+type Entry struct {
+	Date profile.Date `json:"date"`
+	// Вес в килограммах
+	Weight float64 `json:"weight_kg"`
+}
+
+// Method определяет, как вес берется на дату между взвешиваниями
+type Method int
+
+const (
+	// LastKnown - вес последнего взвешивания не позже даты
+	LastKnown Method = iota
+	// Interpolate - линейная интерполяция между соседними взвешиваниями
+	Interpolate
+)
+
+func (m Method) String() string {
+	switch m {
+	case LastKnown:
+		return "last"
+	case Interpolate:
+		return "interpolate"
+	default:
+		return fmt.Sprintf("Method(%d)", int(m))
+	}
+}
+
+// ParseMethod возвращает способ по названию: last или interpolate
+func ParseMethod(name string) (Method, error) {
+	for _, m := range []Method{LastKnown, Interpolate} {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("неизвестный способ выбора веса %q: ожидается last или interpolate", name)
+}
+
+// Log - история взвешиваний пользователя, по одному значению на день по возрастанию даты
+type Log struct {
+	entries []Entry
+}
+
+// NewLog создает историю из взвешиваний в любом порядке. Для одного дня остается последнее
+func NewLog(entries ...Entry) (*Log, error) {
+	l := &Log{}
+	for _, e := range entries {
+		if err := l.Add(e.Date, e.Weight); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Add добавляет или заменяет взвешивание за день date
+func (l *Log) Add(date profile.Date, kg float64) error {
+	if date.IsZero() {
+		return errors.New("не задана дата взвешивания")
+	}
+	if kg < profile.MinWeight || kg > profile.MaxWeight {
+		return fmt.Errorf("вес %g вне допустимого диапазона от %g до %g кг", kg, profile.MinWeight, profile.MaxWeight)
+	}
+
+	date = dayOf(date.Time)
+	i := sort.Search(len(l.entries), func(i int) bool { return !l.entries[i].Date.Before(date.Time) })
+	if i < len(l.entries) && l.entries[i].Date.Equal(date.Time) {
+		l.entries[i].Weight = kg
+		return nil
+	}
+	l.entries = append(l.entries, Entry{})
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = Entry{Date: date, Weight: kg}
+	return nil
+}
+
+// Entries возвращает взвешивания по возрастанию даты
+func (l *Log) Entries() []Entry {
+	return append([]Entry(nil), l.entries...)
+}
+
+// At возвращает вес на момент t. До первого взвешивания берется первое, после
+// последнего - последнее. ok = false, только если история пуста
+func (l *Log) At(t time.Time, m Method) (kg float64, ok bool) {
+	if len(l.entries) == 0 {
+		return 0, false
+	}
+
+	// Сравниваем по календарным дням в часовом поясе t
+	day := dayOf(t)
+	i := sort.Search(len(l.entries), func(i int) bool { return l.entries[i].Date.After(day.Time) })
+	switch {
+	case i == 0:
+		return l.entries[0].Weight, true
+	case i == len(l.entries) || m == LastKnown:
+		return l.entries[i-1].Weight, true
+	}
+
+	prev, next := l.entries[i-1], l.entries[i]
+	share := day.Sub(prev.Date.Time).Hours() / next.Date.Sub(prev.Date.Time).Hours()
+	return prev.Weight + share*(next.Weight-prev.Weight), true
+}
+
+// Source возвращает источник веса для spentcalories.WithWeightHistory
+func (l *Log) Source(m Method) Source {
+	return Source{log: l, method: m}
+}
+
+// Source - история взвешиваний с выбранным способом, реализует spentcalories.WeightSource
+type Source struct {
+	log    *Log
+	method Method
+}
+
+func (s Source) WeightAt(t time.Time) (float64, bool) {
+	return s.log.At(t, s.method)
+}
+
+// TrendEntry - взвешивание со сглаженным значением
+type TrendEntry struct {
+	Entry
+	Trend float64 `json:"trend_kg"`
+}
+
+// Format форматирует взвешивание и тренд на языке loc, вес выводится в единицах sys
+func (e TrendEntry) Format(loc i18n.Locale, sys units.System) string {
+	return loc.T("weight.entry", e.Date.String(), sys.Weight(e.Weight), sys.Weight(e.Trend), loc.T(sys.WeightKey()))
+}
+
+// Trend сглаживает вес экспоненциальным скользящим средним: каждый день без
+// взвешивания тренд остается прежним, а в день взвешивания сдвигается на долю
+// alpha к измеренному весу. alpha = 0.1 дает тренд как в «Hacker's Diet»
+func (l *Log) Trend(alpha float64) ([]TrendEntry, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("коэффициент сглаживания должен быть больше 0 и не больше 1, задано %g", alpha)
+	}
+
+	trend := make([]TrendEntry, len(l.entries))
+	for i, e := range l.entries {
+		value := e.Weight
+		if i > 0 {
+			prev := trend[i-1].Trend
+			value = prev + alpha*(e.Weight-prev)
+		}
+		trend[i] = TrendEntry{Entry: e, Trend: value}
+	}
+	return trend, nil
+}
+
+func dayOf(t time.Time) profile.Date {
+	y, m, d := t.Date()
+	return profile.NewDate(y, m, d)
+}
+
+// Load читает историю из JSON-файла. Отсутствующий файл - пустая история
+func Load(path string) (*Log, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Log{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("история веса %s: %w", path, err)
+	}
+	l, err := NewLog(entries...)
+	if err != nil {
+		return nil, fmt.Errorf("история веса %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Save записывает историю в JSON-файл
+func Save(path string, l *Log) error {
+	entries := l.entries
+	if entries == nil {
+		entries = []Entry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,137 @@
+package weight
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yandex-Practicum/tracker/internal/i18n"
+	"github.com/Yandex-Practicum/tracker/internal/profile"
+	"github.com/Yandex-Practicum/tracker/internal/spentcalories"
+	"github.com/Yandex-Practicum/tracker/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WeightTestSuite struct {
+	suite.Suite
+}
+
+func TestWeightSuite(t *testing.T) {
+	suite.Run(t, new(WeightTestSuite))
+}
+
+func (suite *WeightTestSuite) newLog() *Log {
+	l, err := NewLog(
+		Entry{Date: profile.NewDate(2024, time.March, 1), Weight: 84},
+		Entry{Date: profile.NewDate(2024, time.January, 1), Weight: 90},
+		Entry{Date: profile.NewDate(2024, time.February, 1), Weight: 88},
+	)
+	assert.NoError(suite.T(), err)
+	return l
+}
+
+func (suite *WeightTestSuite) TestAdd() {
+	l := suite.newLog()
+	dates := make([]string, 0, 3)
+	for _, e := range l.Entries() {
+		dates = append(dates, e.Date.String())
+	}
+	assert.Equal(suite.T(), []string{"2024-01-01", "2024-02-01", "2024-03-01"}, dates)
+
+	// Повторное взвешивание за день заменяет прежнее
+	assert.NoError(suite.T(), l.Add(profile.NewDate(2024, time.February, 1), 87.5))
+	assert.Len(suite.T(), l.Entries(), 3)
+	assert.Equal(suite.T(), 87.5, l.Entries()[1].Weight)
+
+	assert.Error(suite.T(), l.Add(profile.NewDate(2024, time.April, 1), 8460))
+	assert.Error(suite.T(), l.Add(profile.Date{}, 84))
+}
+
+func (suite *WeightTestSuite) TestAt() {
+	l := suite.newLog()
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		method Method
+		want   float64
+	}{
+		{name: "день взвешивания", at: time.Date(2024, time.February, 1, 20, 0, 0, 0, msk), method: Interpolate, want: 88},
+		{name: "последний известный", at: time.Date(2024, time.February, 16, 0, 0, 0, 0, time.UTC), method: LastKnown, want: 88},
+		// 15 из 29 дней между 88 и 84 кг
+		{name: "интерполяция", at: time.Date(2024, time.February, 16, 7, 0, 0, 0, time.UTC), method: Interpolate, want: 88 - 4*15.0/29},
+		{name: "до первого взвешивания", at: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC), method: Interpolate, want: 90},
+		{name: "после последнего", at: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), method: Interpolate, want: 84},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, ok := l.At(tt.at, tt.method)
+			assert.True(suite.T(), ok)
+			assert.InDelta(suite.T(), tt.want, got, 1e-9)
+		})
+	}
+
+	_, ok := (&Log{}).At(time.Now(), LastKnown)
+	assert.False(suite.T(), ok)
+}
+
+func (suite *WeightTestSuite) TestTrend() {
+	trend, err := suite.newLog().Trend(0.5)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), trend, 3)
+	assert.Equal(suite.T(), []float64{90, 89, 86.5}, []float64{trend[0].Trend, trend[1].Trend, trend[2].Trend})
+
+	for _, alpha := range []float64{0, -0.1, 1.5} {
+		_, err := suite.newLog().Trend(alpha)
+		assert.Error(suite.T(), err)
+	}
+
+	assert.Equal(suite.T(), "2024-02-01: 88.0 кг, тренд 89.0 кг", trend[1].Format(i18n.Russian, units.Metric))
+	assert.Equal(suite.T(), "2024-02-01: 194.0 lb, trend 196.2 lb", trend[1].Format(i18n.English, units.Imperial))
+}
+
+func (suite *WeightTestSuite) TestRecompute() {
+	l := suite.newLog()
+	opts := []spentcalories.Option{spentcalories.WithWeightHistory(l.Source(LastKnown))}
+
+	// Прошлогодняя тренировка считается по весу того времени, а не по текущему
+	got, err := spentcalories.Training("2024-02-10T07:00:00Z,8000,Бег,1h00m", 84, 1.75, opts...)
+	assert.NoError(suite.T(), err)
+	want, _ := spentcalories.Training("2024-02-10T07:00:00Z,8000,Бег,1h00m", 88, 1.75)
+	assert.InDelta(suite.T(), want.Calories, got.Calories, 1e-9)
+	assert.Equal(suite.T(), 88.0, got.Weight)
+}
+
+func (suite *WeightTestSuite) TestSaveLoad() {
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "weights.json")
+
+	// Файла еще нет - история пуста
+	empty, err := Load(path)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), empty.Entries())
+
+	want := suite.newLog()
+	assert.NoError(suite.T(), Save(path, want))
+	got, err := Load(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), want.Entries(), got.Entries())
+
+	assert.NoError(suite.T(), os.WriteFile(path, []byte(`[{"date": "2024-01-01", "weight_kg": 8460}]`), 0o644))
+	_, err = Load(path)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *WeightTestSuite) TestParseMethod() {
+	for _, m := range []Method{LastKnown, Interpolate} {
+		got, err := ParseMethod(m.String())
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), m, got)
+	}
+	_, err := ParseMethod("ema")
+	assert.Error(suite.T(), err)
+}